@@ -1,71 +1,294 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata" // tax years are measured in Europe/London time
 
 	"cushon/internal/adapters/primary/http"
+	"cushon/internal/adapters/secondary/authorization"
+	"cushon/internal/adapters/secondary/clock"
+	"cushon/internal/adapters/secondary/persistence/memory"
 	"cushon/internal/adapters/secondary/persistence/mysql"
+	"cushon/internal/adapters/secondary/publisher"
+	"cushon/internal/config"
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
 	"cushon/internal/core/services"
+	"cushon/internal/health"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// idempotencyPurgeInterval is how often expired idempotency keys are deleted.
+// An expired key is free to use as soon as it expires; deleting it only
+// reclaims the space.
+const idempotencyPurgeInterval = time.Hour
+
 func main() {
-	// Initialize MySQL connection
-	dbConfig := mysql.Config{
-		Host:     "localhost",
-		Port:     3306,
-		User:     "root",
-		Password: os.Getenv("DB_PASSWORD"),
-		Database: "cushon",
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run starts the API server and blocks until it has shut down. Teardown runs
+// in reverse order of setup: the server drains in-flight requests and the
+// background workers finish before the database connections they use are
+// closed.
+func run() error {
+	configFile := flag.String("config", "", "path to a YAML or TOML config file, defaults to $CONFIG_FILE")
+	storage := flag.String("storage", "", "where data is kept: mysql, or memory to run without a database; overrides the config")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if *storage != "" {
+		cfg.Storage = *storage
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(cfg, flag.Args()[1:]); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		return nil
+	}
+
+	// Stop on Ctrl-C, or when the platform asks the process to terminate
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	db, err := mysql.NewConnection(dbConfig)
+	// Load the keys tokens are signed with before anything else starts, so a
+	// missing or malformed key set stops the server at once
+	verifier, err := http.NewJWTVerifier(cfg.Auth.JWT())
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return fmt.Errorf("failed to load token keys, set auth.jwks_file: %w", err)
 	}
-	defer db.Close()
+
+	// Readiness checks are registered alongside the dependencies they check
+	healthChecks := health.NewRegistry(time.Duration(cfg.Health.CheckTimeout))
 
 	// Initialize repositories
-	directUserRepo := mysql.NewDirectUserRepository(db)
-	transactionRepo := mysql.NewTransactionRepository(db)
+	var (
+		directUserRepo  output.DirectUserRepository
+		transactionRepo output.TransactionRepository
+		fundRepo        output.FundRepository
+		fundPriceRepo   output.FundPriceRepository
+		unitOfWork      output.UnitOfWork
+		idempotencyRepo output.IdempotencyRepository
+		auditLog        output.AuditLog
+		outbox          output.Outbox
+	)
+
+	switch cfg.Storage {
+	case config.StorageMySQL:
+		// Initialize MySQL connection
+		db, err := mysql.NewConnection(cfg.Database.MySQL())
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				log.Printf("Failed to close database: %v", err)
+				return
+			}
+			log.Println("Database connections closed")
+		}()
+
+		migrator, err := mysql.NewMigrator(db)
+		if err != nil {
+			return fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if cfg.Database.MigrateOnStart {
+			applied, err := migrator.Up(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to migrate database: %w", err)
+			}
+			log.Printf("Applied %d migrations", len(applied))
+		}
+
+		directUserRepo = mysql.NewDirectUserRepository(db)
+		transactionRepo = mysql.NewTransactionRepository(db)
+		fundRepo = mysql.NewFundRepository(db)
+		fundPriceRepo = mysql.NewFundPriceRepository(db)
+		unitOfWork = mysql.NewUnitOfWork(db)
+		idempotencyRepo = mysql.NewIdempotencyRepository(db)
+		auditLog = mysql.NewAuditLog(db)
+		outbox = mysql.NewOutbox(db)
+
+		healthChecks.Register("database", db.PingContext)
+		healthChecks.Register("migrations", migrator.CheckVersion)
+	case config.StorageMemory:
+		store := memory.NewStore()
+		directUserRepo = memory.NewDirectUserRepository(store)
+		transactionRepo = memory.NewTransactionRepository(store)
+		fundRepo = memory.NewFundRepository(store)
+		fundPriceRepo = memory.NewFundPriceRepository(store)
+		unitOfWork = memory.NewUnitOfWork(store)
+		idempotencyRepo = memory.NewIdempotencyRepository(store)
+		auditLog = memory.NewAuditLog(store)
+		outbox = memory.NewOutbox(store)
+
+		// Start with the fund the MySQL migrations create, so there is a fund to invest in
+		if err := fundRepo.Save(ctx, defaultFund()); err != nil {
+			return fmt.Errorf("failed to seed fund catalogue: %w", err)
+		}
+		log.Println("Using in-memory storage, data will be lost when the server stops")
+	}
+
+	systemClock := clock.NewSystem()
+	authorizer := authorization.NewRoleBased(log.Default())
+
+	// A stale price feed delays unit allocation but doesn't stop deposits being taken
+	healthChecks.RegisterOptional("pricing", services.NewPriceFreshnessCheck(fundRepo, fundPriceRepo, time.Duration(cfg.Health.PriceMaxAge), systemClock))
 
 	// Initialize services
-	directUserService := services.NewDirectUserService(directUserRepo)
-	transactionService := services.NewTransactionService(transactionRepo)
+	directUserService := services.NewDirectUserService(directUserRepo, unitOfWork, auditLog, outbox, authorizer, systemClock)
+	transactionService := services.NewTransactionService(transactionRepo, directUserRepo, fundRepo, unitOfWork, cfg.Allowance.Policy(), auditLog, outbox, authorizer, systemClock)
+	fundService := services.NewFundService(fundRepo, authorizer)
+	pricingService := services.NewPricingService(fundRepo, fundPriceRepo, transactionRepo, unitOfWork, auditLog, authorizer, systemClock)
+	portfolioService := services.NewPortfolioService(directUserRepo, transactionRepo, fundRepo, fundPriceRepo, authorizer, systemClock)
+	auditService := services.NewAuditService(auditLog, authorizer)
+	outboxRelay := services.NewOutboxRelay(outbox, newEventPublisher(cfg.Events), systemClock, cfg.Events.Relay())
 
 	// Initialize handlers
 	directUserHandler := http.NewDirectUserHandler(directUserService)
 	transactionHandler := http.NewTransactionHandler(transactionService)
+	fundHandler := http.NewFundHandler(fundService)
+	fundPriceHandler := http.NewFundPriceHandler(pricingService)
+	portfolioHandler := http.NewPortfolioHandler(portfolioService)
+	auditHandler := http.NewAuditHandler(auditService)
+	healthHandler := http.NewHealthHandler(healthChecks)
+	openAPIHandler := http.NewOpenAPIHandler()
 
 	// Initialize router
 	router := gin.Default()
 
 	// Configure CORS
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"}, // Frontend URL
+		AllowOrigins:     cfg.CORS.AllowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", http.IdempotencyKeyHeader, http.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", http.IdempotentReplayedHeader, http.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * 60 * 60, // 12 hours
 	}))
 
-	// Register routes
+	// Identify each request, so the audit log can trace the changes it makes
+	router.Use(http.RequestID())
+
+	// Cancel a request's queries when the client goes away or it runs too long
+	router.Use(http.RequestTimeout(time.Duration(cfg.Server.RequestTimeout)))
+
+	// Register the routes anyone may call: probes and the API description
+	healthHandler.RegisterRoutes(router)
+	openAPIHandler.RegisterRoutes(router)
+
+	// Routes registered from here on need a bearer token
+	router.Use(http.Authenticate(verifier))
 	directUserHandler.RegisterRoutes(router)
-	transactionHandler.RegisterRoutes(router)
-
-	// Add health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
-
-	// Start server
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	transactionHandler.RegisterRoutes(router, http.Idempotency(idempotencyRepo, systemClock, time.Duration(cfg.Idempotency.TTL), time.Duration(cfg.Idempotency.PendingTTL)))
+	fundHandler.RegisterRoutes(router)
+	fundPriceHandler.RegisterRoutes(router)
+	portfolioHandler.RegisterRoutes(router)
+	auditHandler.RegisterRoutes(router)
+
+	// Background workers run until the server stops, and are waited for
+	// before the database they use is closed
+	var workers sync.WaitGroup
+	defer func() {
+		stop()
+		workers.Wait()
+	}()
+
+	// Forget idempotency keys once they have expired, until the server stops
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		purgeIdempotencyKeys(ctx, idempotencyRepo, systemClock)
+	}()
+
+	// Publish the events the services add to the outbox, until the server stops
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relayEvents(ctx, outboxRelay, time.Duration(cfg.Events.RelayInterval))
+	}()
+
+	// Serve until signalled, failing readiness while in-flight requests drain
+	server := http.NewServer(router, cfg.Server.HTTP())
+	server.OnShutdown(healthChecks.Drain)
+	return server.Run(ctx)
+}
+
+// purgeIdempotencyKeys deletes expired idempotency keys every
+// idempotencyPurgeInterval until ctx is done
+func purgeIdempotencyKeys(ctx context.Context, repo output.IdempotencyRepository, clock output.Clock) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeleteExpired(ctx, clock.Now())
+			if err != nil {
+				log.Printf("Failed to delete expired idempotency keys: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d expired idempotency keys", deleted)
+			}
+		}
+	}
+}
+
+// newEventPublisher returns the publisher the configuration selects
+func newEventPublisher(cfg config.EventsConfig) output.EventPublisher {
+	switch cfg.Publisher {
+	case config.PublisherWebhook:
+		return publisher.NewWebhook(cfg.WebhookURL, time.Duration(cfg.WebhookTimeout))
+	case config.PublisherFile:
+		return publisher.NewFile(cfg.File)
+	default:
+		return publisher.NewLog(log.Default())
 	}
-} 
\ No newline at end of file
+}
+
+// relayEvents publishes the events in the outbox every interval until ctx is
+// done. A failed event is retried on the next tick.
+func relayEvents(ctx context.Context, relay *services.OutboxRelay, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := relay.Relay(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to publish events: %v", err)
+			}
+		}
+	}
+}
+
+// defaultFund returns the fund the MySQL migrations seed the catalogue with
+func defaultFund() *domain.Fund {
+	fund := domain.NewFund("Cushon Equities Fund", "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+	fund.ID = "7d1c1e0a-4a4e-4f7e-9a51-1f3b0c6d2e01"
+	return fund
+}
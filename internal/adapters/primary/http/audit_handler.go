@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles HTTP requests for the audit log
+type AuditHandler struct {
+	auditService input.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService input.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// RegisterRoutes registers the audit routes
+func (h *AuditHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/audit-entries", h.GetAuditEntries)
+}
+
+// GetAuditEntries handles retrieval of a page of the audit log, filtered by
+// entity and by the time the changes were made
+func (h *AuditHandler) GetAuditEntries(c *gin.Context) {
+	query, err := auditQuery(c)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := h.auditService.GetAuditLog(c.Request.Context(), query)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuditPageResponse(page))
+}
+
+// auditQuery reads an audit log query from the request
+func auditQuery(c *gin.Context) (domain.AuditQuery, error) {
+	query := domain.AuditQuery{
+		EntityType: domain.AuditEntityType(c.Query("entity_type")),
+		EntityID:   c.Query("entity_id"),
+	}
+
+	var err error
+	if query.From, err = queryTime(c, "from"); err != nil {
+		return query, err
+	}
+	if query.To, err = queryTime(c, "to"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = queryInt(c, "limit"); err != nil {
+		return query, err
+	}
+	if query.After, err = decodeAuditCursor(c.Query("cursor")); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MockAuditService implements input.AuditService for testing
+type MockAuditService struct {
+	entries []*domain.AuditEntry
+	// denied makes every request fail as if the principal lacked the permission
+	denied bool
+	// query is the last query the service was asked for
+	query domain.AuditQuery
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultAuditPageSize
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if m.denied {
+		return nil, domain.NewForbiddenError("not permitted to view the audit log")
+	}
+	m.query = query
+
+	page := &domain.AuditPage{Entries: []*domain.AuditEntry{}}
+	for _, entry := range m.entries {
+		if query.Matches(entry) {
+			page.Entries = append(page.Entries, entry)
+		}
+	}
+	return page, nil
+}
+
+func setupAuditTestRouter(service input.AuditService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(recordExchanges())
+	handler := NewAuditHandler(service)
+	handler.RegisterRoutes(router)
+	return router
+}
+
+func TestAuditHandler_GetAuditEntries(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	service := &MockAuditService{entries: []*domain.AuditEntry{
+		{
+			ID:         "audit-1",
+			Actor:      testStaff.Subject,
+			Action:     domain.AuditActionUpdate,
+			EntityType: domain.AuditEntityDirectUser,
+			EntityID:   "user123",
+			Before:     json.RawMessage(`{"name":"John Doe"}`),
+			After:      json.RawMessage(`{"name":"Jane Doe"}`),
+			RequestID:  "req-1",
+			CreatedAt:  createdAt,
+		},
+	}}
+	router := setupAuditTestRouter(service)
+	cursor := encodeAuditCursor(&domain.AuditCursor{CreatedAt: createdAt.Add(-time.Hour), ID: "audit-0"})
+
+	tests := []struct {
+		name            string
+		query           string
+		denied          bool
+		expectedStatus  int
+		expectedEntries int
+		expectedCursor  string
+	}{
+		{
+			name:            "entries for an entity",
+			query:           "?entity_type=direct-user&entity_id=user123&from=2025-01-01&to=2025-02-01T00:00:00Z&limit=10&cursor=" + cursor,
+			expectedStatus:  http.StatusOK,
+			expectedEntries: 1,
+			expectedCursor:  "audit-0",
+		},
+		{
+			name:            "outside the time range",
+			query:           "?entity_type=direct-user&from=2025-01-03",
+			expectedStatus:  http.StatusOK,
+			expectedEntries: 0,
+		},
+		{
+			name:           "missing entity type",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid date",
+			query:          "?entity_type=transaction&from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid cursor",
+			query:          "?entity_type=transaction&cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "without the permission",
+			query:          "?entity_type=transaction",
+			denied:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.denied = tt.denied
+			req := httptest.NewRequest(http.MethodGet, "/audit-entries"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response auditPageResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if len(response.Entries) != tt.expectedEntries {
+				t.Errorf("Expected %d entries, got %d", tt.expectedEntries, len(response.Entries))
+			}
+			if tt.expectedCursor != "" && (service.query.After == nil || service.query.After.ID != tt.expectedCursor) {
+				t.Errorf("Expected the cursor passed to the service, got %+v", service.query.After)
+			}
+		})
+	}
+}
@@ -34,45 +34,39 @@ func (h *DirectUserHandler) RegisterRoutes(router *gin.Engine) {
 
 // CreateDirectUser handles direct user creation
 func (h *DirectUserHandler) CreateDirectUser(c *gin.Context) {
-	var request struct {
-		Name string `json:"name" binding:"required"`
-	}
-
+	var request directUserRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindError(c, err)
 		return
 	}
 
-	directUser, err := h.directUserService.CreateDirectUser(request.Name)
+	directUser, err := h.directUserService.CreateDirectUser(c.Request.Context(), request.Name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, directUser)
+	c.JSON(http.StatusCreated, newDirectUserResponse(directUser))
 }
 
 // GetDirectUser handles direct user retrieval
 func (h *DirectUserHandler) GetDirectUser(c *gin.Context) {
 	id := c.Param("id")
-	directUser, err := h.directUserService.GetDirectUser(id)
+	directUser, err := h.directUserService.GetDirectUser(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "direct user not found"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, directUser)
+	c.JSON(http.StatusOK, newDirectUserResponse(directUser))
 }
 
 // UpdateDirectUser handles direct user updates
 func (h *DirectUserHandler) UpdateDirectUser(c *gin.Context) {
 	id := c.Param("id")
-	var request struct {
-		Name string `json:"name" binding:"required"`
-	}
-
+	var request directUserRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindError(c, err)
 		return
 	}
 
@@ -81,19 +75,19 @@ func (h *DirectUserHandler) UpdateDirectUser(c *gin.Context) {
 		Name: request.Name,
 	}
 
-	if err := h.directUserService.UpdateDirectUser(directUser); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.directUserService.UpdateDirectUser(c.Request.Context(), directUser); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, directUser)
+	c.JSON(http.StatusOK, newDirectUserResponse(directUser))
 }
 
 // DeleteDirectUser handles direct user deletion
 func (h *DirectUserHandler) DeleteDirectUser(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.directUserService.DeleteDirectUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.directUserService.DeleteDirectUser(c.Request.Context(), id); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -1,12 +1,13 @@
 package http
 
 import (
+	"context"
 	"bytes"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/input"
@@ -25,51 +26,51 @@ func NewMockDirectUserService() *MockDirectUserService {
 	}
 }
 
-func (m *MockDirectUserService) CreateDirectUser(name string) (*domain.DirectUser, error) {
-	user := domain.NewDirectUser(name)
+func (m *MockDirectUserService) CreateDirectUser(ctx context.Context, name string) (*domain.DirectUser, error) {
+	user := domain.NewDirectUser(name, time.Now())
 	m.users[user.ID] = user
 	return user, nil
 }
 
-func (m *MockDirectUserService) GetDirectUser(id string) (*domain.DirectUser, error) {
+func (m *MockDirectUserService) GetDirectUser(ctx context.Context, id string) (*domain.DirectUser, error) {
 	if id == "" {
-		return nil, errors.New("direct user ID is required")
+		return nil, domain.NewValidationError("id", "direct user ID is required")
 	}
 
 	if user, exists := m.users[id]; exists {
 		return user, nil
 	}
-	return nil, errors.New("direct user not found")
+	return nil, domain.ErrUserNotFound
 }
 
-func (m *MockDirectUserService) UpdateDirectUser(user *domain.DirectUser) error {
+func (m *MockDirectUserService) UpdateDirectUser(ctx context.Context, user *domain.DirectUser) error {
 	if user == nil {
-		return errors.New("direct user cannot be nil")
+		return domain.NewValidationError("user", "direct user cannot be nil")
 	}
 
 	if user.ID == "" {
-		return errors.New("direct user ID is required")
+		return domain.NewValidationError("id", "direct user ID is required")
 	}
 
 	if user.Name == "" {
-		return errors.New("name is required")
+		return domain.NewValidationError("name", "name is required")
 	}
 
 	if _, exists := m.users[user.ID]; !exists {
-		return errors.New("direct user not found")
+		return domain.ErrUserNotFound
 	}
 
 	m.users[user.ID] = user
 	return nil
 }
 
-func (m *MockDirectUserService) DeleteDirectUser(id string) error {
+func (m *MockDirectUserService) DeleteDirectUser(ctx context.Context, id string) error {
 	if id == "" {
-		return errors.New("direct user ID is required")
+		return domain.NewValidationError("id", "direct user ID is required")
 	}
 
 	if _, exists := m.users[id]; !exists {
-		return errors.New("direct user not found")
+		return domain.ErrUserNotFound
 	}
 
 	delete(m.users, id)
@@ -79,6 +80,7 @@ func (m *MockDirectUserService) DeleteDirectUser(id string) error {
 func setupTestRouter(service input.DirectUserService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(recordExchanges())
 	handler := NewDirectUserHandler(service)
 	handler.RegisterRoutes(router)
 	return router
@@ -128,7 +130,7 @@ func TestDirectUserHandler_CreateDirectUser(t *testing.T) {
 			}
 
 			if tt.expectedStatus == http.StatusCreated {
-				var response domain.DirectUser
+				var response directUserResponse
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 					t.Errorf("Failed to unmarshal response: %v", err)
 				}
@@ -145,7 +147,7 @@ func TestDirectUserHandler_GetDirectUser(t *testing.T) {
 	router := setupTestRouter(service)
 
 	// Create a test user
-	user, _ := service.CreateDirectUser("John Doe")
+	user, _ := service.CreateDirectUser(context.Background(), "John Doe")
 
 	tests := []struct {
 		name           string
@@ -176,7 +178,7 @@ func TestDirectUserHandler_GetDirectUser(t *testing.T) {
 			}
 
 			if tt.expectedStatus == http.StatusOK {
-				var response domain.DirectUser
+				var response directUserResponse
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 					t.Errorf("Failed to unmarshal response: %v", err)
 				}
@@ -188,12 +190,35 @@ func TestDirectUserHandler_GetDirectUser(t *testing.T) {
 	}
 }
 
+func TestDirectUserHandler_GetDirectUser_Timestamps(t *testing.T) {
+	service := NewMockDirectUserService()
+	router := setupTestRouter(service)
+
+	user := domain.NewDirectUser("John Doe", time.Date(2024, 6, 14, 9, 0, 0, 123456000, time.UTC))
+	service.users[user.ID] = user
+
+	req := httptest.NewRequest(http.MethodGet, "/direct-users/"+user.ID, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	// Timestamps are RFC 3339 in UTC
+	for _, key := range []string{"created_at", "updated_at"} {
+		if response[key] != "2024-06-14T09:00:00.123456Z" {
+			t.Errorf("Expected %s 2024-06-14T09:00:00.123456Z, got %v", key, response[key])
+		}
+	}
+}
+
 func TestDirectUserHandler_UpdateDirectUser(t *testing.T) {
 	service := NewMockDirectUserService()
 	router := setupTestRouter(service)
 
 	// Create a test user
-	user, _ := service.CreateDirectUser("John Doe")
+	user, _ := service.CreateDirectUser(context.Background(), "John Doe")
 
 	tests := []struct {
 		name           string
@@ -215,7 +240,7 @@ func TestDirectUserHandler_UpdateDirectUser(t *testing.T) {
 			payload: map[string]interface{}{
 				"name": "Jane Doe",
 			},
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNotFound,
 		},
 		{
 			name:   "missing name",
@@ -241,7 +266,7 @@ func TestDirectUserHandler_UpdateDirectUser(t *testing.T) {
 			}
 
 			if tt.expectedStatus == http.StatusOK {
-				var response domain.DirectUser
+				var response directUserResponse
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 					t.Errorf("Failed to unmarshal response: %v", err)
 				}
@@ -258,7 +283,7 @@ func TestDirectUserHandler_DeleteDirectUser(t *testing.T) {
 	router := setupTestRouter(service)
 
 	// Create a test user
-	user, _ := service.CreateDirectUser("John Doe")
+	user, _ := service.CreateDirectUser(context.Background(), "John Doe")
 
 	tests := []struct {
 		name           string
@@ -273,7 +298,7 @@ func TestDirectUserHandler_DeleteDirectUser(t *testing.T) {
 		{
 			name:           "non-existent user",
 			userID:         "non-existent",
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNotFound,
 		},
 	}
 
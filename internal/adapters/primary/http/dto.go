@@ -0,0 +1,355 @@
+package http
+
+import (
+	"encoding/json"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// The request and response bodies of the API. Handlers map between these and
+// the domain types, so the JSON contract only changes when these do. Amounts,
+// units and prices are decimal strings and times are RFC 3339 in UTC.
+
+// directUserRequest is the request body for creating and renaming a direct user
+type directUserRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// createTransactionRequest is the request body for recording a transaction
+type createTransactionRequest struct {
+	UserID   string          `json:"user_id" binding:"required"`
+	Type     string          `json:"type"`
+	Amount   decimal.Decimal `json:"amount" binding:"required"`
+	FundName string          `json:"fund_name" binding:"required"`
+}
+
+// correctTransactionRequest is the request body for correcting a transaction
+type correctTransactionRequest struct {
+	Amount   decimal.Decimal `json:"amount" binding:"required"`
+	FundName string          `json:"fund_name" binding:"required"`
+	Reason   string          `json:"reason" binding:"required"`
+}
+
+// fundRequest is the request body for creating and updating a fund
+type fundRequest struct {
+	Name       string `json:"name" binding:"required"`
+	ISIN       string `json:"isin" binding:"required"`
+	AssetClass string `json:"asset_class" binding:"required"`
+	Currency   string `json:"currency" binding:"required"`
+	RiskRating int    `json:"risk_rating" binding:"required"`
+	Status     string `json:"status"`
+}
+
+// importPricesRequest is the request body for importing a fund's prices
+type importPricesRequest struct {
+	Prices []fundPriceRequest `json:"prices" binding:"required,min=1,dive"`
+}
+
+// fundPriceRequest is a single price in an import. Single priced funds
+// supply nav, dual priced funds supply bid and offer.
+type fundPriceRequest struct {
+	ValuationDate time.Time        `json:"valuation_date" binding:"required"`
+	NAV           *decimal.Decimal `json:"nav"`
+	Bid           *decimal.Decimal `json:"bid"`
+	Offer         *decimal.Decimal `json:"offer"`
+}
+
+// directUserResponse is a direct user
+type directUserResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newDirectUserResponse maps a direct user to its response body
+func newDirectUserResponse(user *domain.DirectUser) directUserResponse {
+	return directUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		CreatedAt: user.CreatedAt.UTC(),
+		UpdatedAt: user.UpdatedAt.UTC(),
+	}
+}
+
+// transactionResponse is a ledger entry. Units, unit price and valuation date
+// are null until the transaction is priced.
+type transactionResponse struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	Type          string     `json:"type"`
+	Amount        string     `json:"amount"`
+	FundName      string     `json:"fund_name"`
+	Units         *string    `json:"units"`
+	UnitPrice     *string    `json:"unit_price"`
+	ValuationDate *time.Time `json:"valuation_date"`
+	ReversalOf    string     `json:"reversal_of,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	Actor         string     `json:"actor,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// newTransactionResponse maps a transaction to its response body
+func newTransactionResponse(transaction *domain.Transaction) transactionResponse {
+	response := transactionResponse{
+		ID:         transaction.ID,
+		UserID:     transaction.UserID,
+		Type:       string(transaction.Type),
+		Amount:     transaction.Amount.String(),
+		FundName:   string(transaction.FundName),
+		ReversalOf: transaction.ReversalOf,
+		Reason:     transaction.Reason,
+		Actor:      transaction.Actor,
+		CreatedAt:  transaction.CreatedAt.UTC(),
+		UpdatedAt:  transaction.UpdatedAt.UTC(),
+	}
+	if transaction.IsPriced() {
+		units := transaction.Units.String()
+		unitPrice := transaction.UnitPrice.String()
+		valuationDate := transaction.ValuationDate.UTC()
+		response.Units = &units
+		response.UnitPrice = &unitPrice
+		response.ValuationDate = &valuationDate
+	}
+	return response
+}
+
+// newTransactionResponses maps transactions to their response bodies, an empty list if there are none
+func newTransactionResponses(transactions []*domain.Transaction) []transactionResponse {
+	responses := make([]transactionResponse, 0, len(transactions))
+	for _, transaction := range transactions {
+		responses = append(responses, newTransactionResponse(transaction))
+	}
+	return responses
+}
+
+// transactionPageResponse is a page of a user's transaction history
+type transactionPageResponse struct {
+	Transactions []transactionResponse `json:"transactions"`
+	Total        int                   `json:"total"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+// newTransactionPageResponse maps a page of transaction history to its response body
+func newTransactionPageResponse(page *domain.TransactionPage) transactionPageResponse {
+	return transactionPageResponse{
+		Transactions: newTransactionResponses(page.Transactions),
+		Total:        page.Total,
+		NextCursor:   encodeCursor(page.Next),
+	}
+}
+
+// reversalResponse is a reversed transaction with the entry that reverses it
+// and, for a correction, the entry replacing it
+type reversalResponse struct {
+	Original   transactionResponse  `json:"original"`
+	Reversal   transactionResponse  `json:"reversal"`
+	Correction *transactionResponse `json:"correction,omitempty"`
+}
+
+// newReversalResponse maps a reversal to its response body
+func newReversalResponse(reversal *domain.Reversal) reversalResponse {
+	response := reversalResponse{
+		Original: newTransactionResponse(reversal.Original),
+		Reversal: newTransactionResponse(reversal.Reversal),
+	}
+	if reversal.Correction != nil {
+		correction := newTransactionResponse(reversal.Correction)
+		response.Correction = &correction
+	}
+	return response
+}
+
+// allowanceResponse is a user's ISA allowance for a tax year
+type allowanceResponse struct {
+	UserID    string `json:"user_id"`
+	TaxYear   string `json:"tax_year"`
+	Limit     string `json:"limit"`
+	Used      string `json:"used"`
+	Remaining string `json:"remaining"`
+}
+
+// newAllowanceResponse maps an allowance to its response body
+func newAllowanceResponse(allowance *domain.Allowance) allowanceResponse {
+	return allowanceResponse{
+		UserID:    allowance.UserID,
+		TaxYear:   allowance.TaxYear,
+		Limit:     allowance.Limit.String(),
+		Used:      allowance.Used.String(),
+		Remaining: allowance.Remaining.String(),
+	}
+}
+
+// fundResponse is a fund in the catalogue
+type fundResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ISIN       string `json:"isin"`
+	AssetClass string `json:"asset_class"`
+	Currency   string `json:"currency"`
+	RiskRating int    `json:"risk_rating"`
+	Status     string `json:"status"`
+}
+
+// newFundResponse maps a fund to its response body
+func newFundResponse(fund *domain.Fund) fundResponse {
+	return fundResponse{
+		ID:         fund.ID,
+		Name:       string(fund.Name),
+		ISIN:       fund.ISIN,
+		AssetClass: string(fund.AssetClass),
+		Currency:   fund.Currency,
+		RiskRating: fund.RiskRating,
+		Status:     string(fund.Status),
+	}
+}
+
+// newFundResponses maps funds to their response bodies, an empty list if there are none
+func newFundResponses(funds []*domain.Fund) []fundResponse {
+	responses := make([]fundResponse, 0, len(funds))
+	for _, fund := range funds {
+		responses = append(responses, newFundResponse(fund))
+	}
+	return responses
+}
+
+// fundPriceResponse is a fund's bid and offer price at a valuation point
+type fundPriceResponse struct {
+	ID            string    `json:"id"`
+	FundID        string    `json:"fund_id"`
+	ValuationDate time.Time `json:"valuation_date"`
+	Bid           string    `json:"bid"`
+	Offer         string    `json:"offer"`
+}
+
+// newFundPriceResponse maps a fund price to its response body
+func newFundPriceResponse(price *domain.FundPrice) fundPriceResponse {
+	return fundPriceResponse{
+		ID:            price.ID,
+		FundID:        price.FundID,
+		ValuationDate: price.ValuationDate.UTC(),
+		Bid:           price.Bid.String(),
+		Offer:         price.Offer.String(),
+	}
+}
+
+// newFundPriceResponses maps fund prices to their response bodies, an empty list if there are none
+func newFundPriceResponses(prices []*domain.FundPrice) []fundPriceResponse {
+	responses := make([]fundPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		responses = append(responses, newFundPriceResponse(price))
+	}
+	return responses
+}
+
+// priceImportResponse summarises a price import
+type priceImportResponse struct {
+	FundID             string `json:"fund_id"`
+	PricesImported     int    `json:"prices_imported"`
+	TransactionsPriced int    `json:"transactions_priced"`
+}
+
+// newPriceImportResponse maps the outcome of a price import to its response body
+func newPriceImportResponse(result *domain.PriceImport) priceImportResponse {
+	return priceImportResponse{
+		FundID:             result.FundID,
+		PricesImported:     result.PricesImported,
+		TransactionsPriced: result.TransactionsPriced,
+	}
+}
+
+// holdingResponse is a user's position in one fund. Price is null if the
+// fund has never been priced.
+type holdingResponse struct {
+	FundName      string             `json:"fund_name"`
+	Units         string             `json:"units"`
+	BookCost      string             `json:"book_cost"`
+	Price         *fundPriceResponse `json:"price"`
+	MarketValue   string             `json:"market_value"`
+	PendingAmount string             `json:"pending_amount"`
+}
+
+// portfolioResponse is a user's holdings valued at the latest prices
+type portfolioResponse struct {
+	UserID        string            `json:"user_id"`
+	Holdings      []holdingResponse `json:"holdings"`
+	BookCost      string            `json:"book_cost"`
+	MarketValue   string            `json:"market_value"`
+	PendingAmount string            `json:"pending_amount"`
+	ValuedAt      time.Time         `json:"valued_at"`
+}
+
+// newPortfolioResponse maps a portfolio to its response body
+func newPortfolioResponse(portfolio *domain.Portfolio) portfolioResponse {
+	holdings := make([]holdingResponse, 0, len(portfolio.Holdings))
+	for _, holding := range portfolio.Holdings {
+		response := holdingResponse{
+			FundName:      string(holding.FundName),
+			Units:         holding.Units.String(),
+			BookCost:      holding.BookCost.String(),
+			MarketValue:   holding.MarketValue.String(),
+			PendingAmount: holding.PendingAmount.String(),
+		}
+		if holding.Price != nil {
+			price := newFundPriceResponse(holding.Price)
+			response.Price = &price
+		}
+		holdings = append(holdings, response)
+	}
+
+	return portfolioResponse{
+		UserID:        portfolio.UserID,
+		Holdings:      holdings,
+		BookCost:      portfolio.BookCost.String(),
+		MarketValue:   portfolio.MarketValue.String(),
+		PendingAmount: portfolio.PendingAmount.String(),
+		ValuedAt:      portfolio.ValuedAt.UTC(),
+	}
+}
+
+// auditEntryResponse is an entry of the audit log. Before and after are the
+// entity's snapshots, null before a creation and after a deletion.
+type auditEntryResponse struct {
+	ID         string          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// auditPageResponse is a page of the audit log
+type auditPageResponse struct {
+	Entries    []auditEntryResponse `json:"entries"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// newAuditPageResponse maps a page of the audit log to its response body
+func newAuditPageResponse(page *domain.AuditPage) auditPageResponse {
+	entries := make([]auditEntryResponse, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		entries = append(entries, auditEntryResponse{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			Action:     string(entry.Action),
+			EntityType: string(entry.EntityType),
+			EntityID:   entry.EntityID,
+			Before:     entry.Before,
+			After:      entry.After,
+			RequestID:  entry.RequestID,
+			CreatedAt:  entry.CreatedAt.UTC(),
+		})
+	}
+
+	return auditPageResponse{
+		Entries:    entries,
+		NextCursor: encodeAuditCursor(page.Next),
+	}
+}
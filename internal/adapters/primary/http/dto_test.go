@@ -0,0 +1,212 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compares the JSON encoding of a response body with the golden
+// file testdata/<name>.golden.json. Run the tests with -update after an
+// intended change to the API contract to rewrite the file.
+func assertGolden(t *testing.T, name string, response any) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Response does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+var goldenTime = time.Date(2024, 6, 14, 9, 30, 0, 123456000, time.UTC)
+
+func goldenPrice() *domain.FundPrice {
+	return &domain.FundPrice{
+		ID:            "5f0c7a1e-3b8d-4a52-9c6e-2d1f4b7a8e90",
+		FundID:        "7d1c1e0a-4a4e-4f7e-9a51-1f3b0c6d2e01",
+		ValuationDate: time.Date(2024, 6, 14, 12, 0, 0, 0, time.UTC),
+		Bid:           decimal.RequireFromString("1.95"),
+		Offer:         decimal.RequireFromString("2.00"),
+	}
+}
+
+func goldenTransaction() *domain.Transaction {
+	return &domain.Transaction{
+		ID:        "0b6e2a7c-8f4d-4e1b-a3c5-9d7f1e2b4c60",
+		UserID:    "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+		Type:      domain.TransactionTypeDeposit,
+		Amount:    decimal.RequireFromString("1000.50"),
+		FundName:  domain.CushonEquitiesFund,
+		CreatedAt: goldenTime,
+		UpdatedAt: goldenTime,
+	}
+}
+
+func TestResponses_Golden(t *testing.T) {
+	priced := goldenTransaction()
+	priced.Allocate(goldenPrice(), goldenTime.Add(3*time.Hour))
+
+	reversal, err := goldenTransaction().Reverse("Amount keyed incorrectly", "ops@cushon.co.uk", goldenTime.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reversal.ID = "9a4d3c2b-1e0f-4a8b-b7c6-5d4e3f2a1b09"
+	correction := goldenTransaction()
+	correction.ID = "e1f2a3b4-c5d6-4e7f-8a9b-0c1d2e3f4a5b"
+	correction.Amount = decimal.RequireFromString("100.05")
+	correction.Reason = "Amount keyed incorrectly"
+	correction.Actor = "ops@cushon.co.uk"
+	correction.CreatedAt = goldenTime.Add(time.Hour)
+	correction.UpdatedAt = correction.CreatedAt
+
+	tests := []struct {
+		name     string
+		response any
+	}{
+		{
+			name: "direct_user",
+			response: newDirectUserResponse(&domain.DirectUser{
+				ID:        "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+				Name:      "John Doe",
+				CreatedAt: goldenTime,
+				UpdatedAt: goldenTime.Add(time.Hour),
+			}),
+		},
+		{
+			name:     "transaction_pending",
+			response: newTransactionResponse(goldenTransaction()),
+		},
+		{
+			name:     "transaction_priced",
+			response: newTransactionResponse(priced),
+		},
+		{
+			name: "transaction_page",
+			response: newTransactionPageResponse(&domain.TransactionPage{
+				Transactions: []*domain.Transaction{priced},
+				Total:        2,
+				Next:         &domain.TransactionCursor{CreatedAt: priced.CreatedAt, ID: priced.ID},
+			}),
+		},
+		{
+			name:     "transaction_page_empty",
+			response: newTransactionPageResponse(&domain.TransactionPage{}),
+		},
+		{
+			name: "reversal",
+			response: newReversalResponse(&domain.Reversal{
+				Original:   goldenTransaction(),
+				Reversal:   reversal,
+				Correction: correction,
+			}),
+		},
+		{
+			name: "allowance",
+			response: newAllowanceResponse(domain.NewAllowance(
+				"c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+				domain.TaxYearFor(goldenTime),
+				decimal.NewFromInt(20000),
+				decimal.RequireFromString("1000.50"),
+			)),
+		},
+		{
+			name: "fund",
+			response: newFundResponse(&domain.Fund{
+				ID:         "7d1c1e0a-4a4e-4f7e-9a51-1f3b0c6d2e01",
+				Name:       domain.CushonEquitiesFund,
+				ISIN:       "GB00B3X7QG63",
+				AssetClass: domain.AssetClassEquity,
+				Currency:   "GBP",
+				RiskRating: 5,
+				Status:     domain.FundStatusOpen,
+			}),
+		},
+		{
+			name:     "fund_prices",
+			response: newFundPriceResponses([]*domain.FundPrice{goldenPrice()}),
+		},
+		{
+			name: "price_import",
+			response: newPriceImportResponse(&domain.PriceImport{
+				FundID:             "7d1c1e0a-4a4e-4f7e-9a51-1f3b0c6d2e01",
+				PricesImported:     2,
+				TransactionsPriced: 3,
+			}),
+		},
+		{
+			name: "portfolio",
+			response: newPortfolioResponse(domain.NewPortfolio(
+				"c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+				[]*domain.Holding{
+					{
+						FundName:      domain.CushonEquitiesFund,
+						Units:         decimal.RequireFromString("500.25"),
+						BookCost:      decimal.RequireFromString("1000.50"),
+						Price:         goldenPrice(),
+						MarketValue:   decimal.RequireFromString("975.4875"),
+						PendingAmount: decimal.Zero,
+					},
+					{
+						FundName:      "Cushon Growth Fund",
+						Units:         decimal.Zero,
+						BookCost:      decimal.Zero,
+						MarketValue:   decimal.Zero,
+						PendingAmount: decimal.NewFromInt(250),
+					},
+				},
+				goldenTime,
+			)),
+		},
+		{
+			name: "audit_page",
+			response: newAuditPageResponse(&domain.AuditPage{
+				Entries: []*domain.AuditEntry{
+					{
+						ID:         "4c2b1a09-8f7e-4d6c-b5a4-3f2e1d0c9b8a",
+						Actor:      "ops@cushon.co.uk",
+						Action:     domain.AuditActionCreate,
+						EntityType: domain.AuditEntityDirectUser,
+						EntityID:   "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+						After:      json.RawMessage(`{"id":"c3ac8a0a-e6f5-451b-a4c6-37e023fe209e","name":"John Doe"}`),
+						RequestID:  "6b7c8d9e-0f1a-4b2c-8d3e-4f5a6b7c8d9e",
+						CreatedAt:  goldenTime,
+					},
+				},
+				Next: &domain.AuditCursor{CreatedAt: goldenTime, ID: "4c2b1a09-8f7e-4d6c-b5a4-3f2e1d0c9b8a"},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t, tt.name, tt.response)
+		})
+	}
+}
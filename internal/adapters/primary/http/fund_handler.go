@@ -0,0 +1,137 @@
+package http
+
+import (
+	"net/http"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FundHandler handles HTTP requests for fund catalogue operations
+type FundHandler struct {
+	fundService input.FundService
+}
+
+// NewFundHandler creates a new fund handler
+func NewFundHandler(fundService input.FundService) *FundHandler {
+	return &FundHandler{
+		fundService: fundService,
+	}
+}
+
+// RegisterRoutes registers the fund routes
+func (h *FundHandler) RegisterRoutes(router *gin.Engine) {
+	funds := router.Group("/funds")
+	{
+		funds.POST("", h.CreateFund)
+		funds.GET("", h.ListFunds)
+		funds.GET("/:id", h.GetFund)
+		funds.PUT("/:id", h.UpdateFund)
+		funds.DELETE("/:id", h.DeleteFund)
+	}
+
+	// Add route for getting the names of funds open to investment
+	router.GET("/fund-names", h.GetFundNames)
+}
+
+// GetFundNames returns the names of funds open to investment
+func (h *FundHandler) GetFundNames(c *gin.Context) {
+	fundNames, err := h.fundService.ListOpenFundNames(c.Request.Context())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fundNames)
+}
+
+// CreateFund handles fund creation
+func (h *FundHandler) CreateFund(c *gin.Context) {
+	var request fundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	fund := domain.NewFund(
+		domain.FundName(request.Name),
+		request.ISIN,
+		domain.AssetClass(request.AssetClass),
+		request.Currency,
+		request.RiskRating,
+	)
+	if request.Status != "" {
+		fund.Status = domain.FundStatus(request.Status)
+	}
+
+	fund, err := h.fundService.CreateFund(c.Request.Context(), fund)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newFundResponse(fund))
+}
+
+// ListFunds handles fund catalogue listing
+func (h *FundHandler) ListFunds(c *gin.Context) {
+	funds, err := h.fundService.ListFunds(c.Request.Context())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFundResponses(funds))
+}
+
+// GetFund handles fund retrieval
+func (h *FundHandler) GetFund(c *gin.Context) {
+	id := c.Param("id")
+	fund, err := h.fundService.GetFund(c.Request.Context(), id)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFundResponse(fund))
+}
+
+// UpdateFund handles fund updates
+func (h *FundHandler) UpdateFund(c *gin.Context) {
+	id := c.Param("id")
+	var request fundRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	fund := &domain.Fund{
+		ID:         id,
+		Name:       domain.FundName(request.Name),
+		ISIN:       request.ISIN,
+		AssetClass: domain.AssetClass(request.AssetClass),
+		Currency:   request.Currency,
+		RiskRating: request.RiskRating,
+		Status:     domain.FundStatus(request.Status),
+	}
+
+	if err := h.fundService.UpdateFund(c.Request.Context(), fund); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFundResponse(fund))
+}
+
+// DeleteFund handles fund deletion
+func (h *FundHandler) DeleteFund(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.fundService.DeleteFund(c.Request.Context(), id); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,279 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MockFundService implements input.FundService for testing
+type MockFundService struct {
+	funds map[string]*domain.Fund
+}
+
+func NewMockFundService() *MockFundService {
+	return &MockFundService{
+		funds: make(map[string]*domain.Fund),
+	}
+}
+
+func (m *MockFundService) CreateFund(ctx context.Context, fund *domain.Fund) (*domain.Fund, error) {
+	if err := fund.Validate(); err != nil {
+		return nil, err
+	}
+	for _, existing := range m.funds {
+		if existing.Name == fund.Name {
+			return nil, domain.ErrFundExists
+		}
+	}
+	m.funds[fund.ID] = fund
+	return fund, nil
+}
+
+func (m *MockFundService) GetFund(ctx context.Context, id string) (*domain.Fund, error) {
+	if fund, exists := m.funds[id]; exists {
+		return fund, nil
+	}
+	return nil, domain.ErrFundNotFound
+}
+
+func (m *MockFundService) ListFunds(ctx context.Context) ([]*domain.Fund, error) {
+	var funds []*domain.Fund
+	for _, fund := range m.funds {
+		funds = append(funds, fund)
+	}
+	return funds, nil
+}
+
+func (m *MockFundService) ListOpenFundNames(ctx context.Context) ([]domain.FundName, error) {
+	fundNames := []domain.FundName{}
+	for _, fund := range m.funds {
+		if fund.IsOpen() {
+			fundNames = append(fundNames, fund.Name)
+		}
+	}
+	return fundNames, nil
+}
+
+func (m *MockFundService) UpdateFund(ctx context.Context, fund *domain.Fund) error {
+	if err := fund.Validate(); err != nil {
+		return err
+	}
+	if _, exists := m.funds[fund.ID]; !exists {
+		return domain.ErrFundNotFound
+	}
+	m.funds[fund.ID] = fund
+	return nil
+}
+
+func (m *MockFundService) DeleteFund(ctx context.Context, id string) error {
+	if _, exists := m.funds[id]; !exists {
+		return domain.ErrFundNotFound
+	}
+	delete(m.funds, id)
+	return nil
+}
+
+func setupFundTestRouter(service input.FundService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(recordExchanges())
+	handler := NewFundHandler(service)
+	handler.RegisterRoutes(router)
+	return router
+}
+
+func TestFundHandler_CreateFund(t *testing.T) {
+	service := NewMockFundService()
+	router := setupFundTestRouter(service)
+
+	tests := []struct {
+		name           string
+		payload        map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name: "valid fund",
+			payload: map[string]interface{}{
+				"name":        "Cushon Equities Fund",
+				"isin":        "GB00B3X7QG63",
+				"asset_class": "equity",
+				"currency":    "GBP",
+				"risk_rating": 5,
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "duplicate fund",
+			payload: map[string]interface{}{
+				"name":        "Cushon Equities Fund",
+				"isin":        "GB00B3X7QG63",
+				"asset_class": "equity",
+				"currency":    "GBP",
+				"risk_rating": 5,
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "invalid ISIN",
+			payload: map[string]interface{}{
+				"name":        "Cushon Bond Fund",
+				"isin":        "GB00B3X7QG64",
+				"asset_class": "bond",
+				"currency":    "GBP",
+				"risk_rating": 3,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing fields",
+			payload: map[string]interface{}{
+				"name": "Cushon Bond Fund",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.payload)
+			req := httptest.NewRequest(http.MethodPost, "/funds", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusCreated {
+				var response fundResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if response.Status != string(domain.FundStatusOpen) {
+					t.Errorf("Expected new fund to be open, got %s", response.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestFundHandler_GetFundNames(t *testing.T) {
+	service := NewMockFundService()
+	router := setupFundTestRouter(service)
+
+	service.CreateFund(context.Background(), domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5))
+	closedFund := domain.NewFund("Cushon Closed Fund", "US0378331005", domain.AssetClassCash, "GBP", 1)
+	closedFund.Status = domain.FundStatusClosed
+	service.CreateFund(context.Background(), closedFund)
+
+	req := httptest.NewRequest(http.MethodGet, "/fund-names", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response []string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Errorf("Failed to unmarshal response: %v", err)
+	}
+	if len(response) != 1 || response[0] != string(domain.CushonEquitiesFund) {
+		t.Errorf("Expected only %s, got %v", domain.CushonEquitiesFund, response)
+	}
+}
+
+func TestFundHandler_UpdateFund(t *testing.T) {
+	service := NewMockFundService()
+	router := setupFundTestRouter(service)
+
+	fund, _ := service.CreateFund(context.Background(), domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5))
+
+	tests := []struct {
+		name           string
+		fundID         string
+		expectedStatus int
+	}{
+		{
+			name:           "close existing fund",
+			fundID:         fund.ID,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "non-existent fund",
+			fundID:         "non-existent",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]interface{}{
+				"name":        "Cushon Equities Fund",
+				"isin":        "GB00B3X7QG63",
+				"asset_class": "equity",
+				"currency":    "GBP",
+				"risk_rating": 5,
+				"status":      "closed",
+			})
+			req := httptest.NewRequest(http.MethodPut, "/funds/"+tt.fundID, bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestFundHandler_DeleteFund(t *testing.T) {
+	service := NewMockFundService()
+	router := setupFundTestRouter(service)
+
+	fund, _ := service.CreateFund(context.Background(), domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5))
+
+	tests := []struct {
+		name           string
+		fundID         string
+		expectedStatus int
+	}{
+		{
+			name:           "existing fund",
+			fundID:         fund.ID,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "non-existent fund",
+			fundID:         "non-existent",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/funds/"+tt.fundID, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
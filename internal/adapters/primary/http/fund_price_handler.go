@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FundPriceHandler handles HTTP requests for fund pricing operations
+type FundPriceHandler struct {
+	pricingService input.PricingService
+}
+
+// NewFundPriceHandler creates a new fund price handler
+func NewFundPriceHandler(pricingService input.PricingService) *FundPriceHandler {
+	return &FundPriceHandler{
+		pricingService: pricingService,
+	}
+}
+
+// RegisterRoutes registers the fund price routes
+func (h *FundPriceHandler) RegisterRoutes(router *gin.Engine) {
+	funds := router.Group("/funds")
+	{
+		funds.POST("/:id/prices", h.ImportPrices)
+		funds.GET("/:id/prices", h.GetFundPrices)
+	}
+}
+
+// ImportPrices handles importing prices for a fund
+func (h *FundPriceHandler) ImportPrices(c *gin.Context) {
+	fundID := c.Param("id")
+	var request importPricesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	prices := make([]*domain.FundPrice, 0, len(request.Prices))
+	for _, p := range request.Prices {
+		switch {
+		case p.NAV != nil && p.Bid == nil && p.Offer == nil:
+			prices = append(prices, domain.NewNAVFundPrice(fundID, p.ValuationDate, *p.NAV))
+		case p.NAV == nil && p.Bid != nil && p.Offer != nil:
+			prices = append(prices, domain.NewFundPrice(fundID, p.ValuationDate, *p.Bid, *p.Offer))
+		default:
+			respondWithError(c, domain.NewValidationError("prices", "each price requires either nav or both bid and offer"))
+			return
+		}
+	}
+
+	result, err := h.pricingService.ImportPrices(c.Request.Context(), fundID, prices)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newPriceImportResponse(result))
+}
+
+// GetFundPrices handles fund price history retrieval
+func (h *FundPriceHandler) GetFundPrices(c *gin.Context) {
+	fundID := c.Param("id")
+	prices, err := h.pricingService.GetFundPrices(c.Request.Context(), fundID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newFundPriceResponses(prices))
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MockPricingService implements input.PricingService for testing
+type MockPricingService struct {
+	prices map[string][]*domain.FundPrice
+}
+
+func NewMockPricingService(fundIDs ...string) *MockPricingService {
+	prices := make(map[string][]*domain.FundPrice)
+	for _, fundID := range fundIDs {
+		prices[fundID] = nil
+	}
+	return &MockPricingService{prices: prices}
+}
+
+func (m *MockPricingService) ImportPrices(ctx context.Context, fundID string, prices []*domain.FundPrice) (*domain.PriceImport, error) {
+	if _, exists := m.prices[fundID]; !exists {
+		return nil, domain.ErrFundNotFound
+	}
+	for _, price := range prices {
+		if err := price.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	m.prices[fundID] = append(m.prices[fundID], prices...)
+	return &domain.PriceImport{FundID: fundID, PricesImported: len(prices)}, nil
+}
+
+func (m *MockPricingService) GetFundPrices(ctx context.Context, fundID string) ([]*domain.FundPrice, error) {
+	prices, exists := m.prices[fundID]
+	if !exists {
+		return nil, domain.ErrFundNotFound
+	}
+	return prices, nil
+}
+
+func setupFundPriceTestRouter(service input.PricingService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(recordExchanges())
+	handler := NewFundPriceHandler(service)
+	handler.RegisterRoutes(router)
+	return router
+}
+
+func TestFundPriceHandler_ImportPrices(t *testing.T) {
+	service := NewMockPricingService("fund-id")
+	router := setupFundPriceTestRouter(service)
+
+	tests := []struct {
+		name           string
+		fundID         string
+		payload        map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name:   "nav and bid/offer prices",
+			fundID: "fund-id",
+			payload: map[string]interface{}{
+				"prices": []map[string]interface{}{
+					{"valuation_date": "2025-01-02T12:00:00Z", "nav": "1.2345"},
+					{"valuation_date": "2025-01-03T12:00:00Z", "bid": "1.23", "offer": "1.25"},
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "unknown fund",
+			fundID: "non-existent",
+			payload: map[string]interface{}{
+				"prices": []map[string]interface{}{
+					{"valuation_date": "2025-01-02T12:00:00Z", "nav": "1.2345"},
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "nav and bid together",
+			fundID: "fund-id",
+			payload: map[string]interface{}{
+				"prices": []map[string]interface{}{
+					{"valuation_date": "2025-01-02T12:00:00Z", "nav": "1.2345", "bid": "1.2"},
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid price",
+			fundID: "fund-id",
+			payload: map[string]interface{}{
+				"prices": []map[string]interface{}{
+					{"valuation_date": "2025-01-02T12:00:00Z", "nav": "-1"},
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no prices",
+			fundID:         "fund-id",
+			payload:        map[string]interface{}{"prices": []map[string]interface{}{}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.payload)
+			req := httptest.NewRequest(http.MethodPost, "/funds/"+tt.fundID+"/prices", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if len(service.prices["fund-id"]) != 2 {
+		t.Errorf("Expected 2 prices imported, got %d", len(service.prices["fund-id"]))
+	}
+}
+
+func TestFundPriceHandler_GetFundPrices(t *testing.T) {
+	service := NewMockPricingService("fund-id")
+	router := setupFundPriceTestRouter(service)
+
+	tests := []struct {
+		name           string
+		fundID         string
+		expectedStatus int
+	}{
+		{
+			name:           "existing fund",
+			fundID:         "fund-id",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown fund",
+			fundID:         "non-existent",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/funds/"+tt.fundID+"/prices", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
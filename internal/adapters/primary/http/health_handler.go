@@ -0,0 +1,83 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"cushon/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler creates a new health handler reporting the registry's checks
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		registry: registry,
+	}
+}
+
+// healthResponse is the body of a probe response
+type healthResponse struct {
+	Status   health.Status         `json:"status"`
+	Draining bool                  `json:"draining,omitempty"`
+	Checks   []checkResultResponse `json:"checks,omitempty"`
+}
+
+// checkResultResponse is the outcome of one readiness check
+type checkResultResponse struct {
+	Name      string        `json:"name"`
+	Status    health.Status `json:"status"`
+	Critical  bool          `json:"critical"`
+	LatencyMS float64       `json:"latency_ms"`
+}
+
+// RegisterRoutes registers the health routes
+func (h *HealthHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/health", h.Live)
+	router.GET("/health/live", h.Live)
+	router.GET("/health/ready", h.Ready)
+}
+
+// Live reports that the process is running and serving requests. It checks
+// no dependencies, so an outage elsewhere doesn't get every instance restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse{Status: health.StatusUp})
+}
+
+// Ready reports whether the instance can serve traffic, with the status and
+// latency of each check. It responds 503 if a critical check fails or the
+// server is shutting down. The probe is unauthenticated, so why a check
+// failed is recorded on the context for the logger rather than returned.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.registry.Check(c.Request.Context())
+
+	response := healthResponse{
+		Status:   report.Status,
+		Draining: report.Draining,
+		Checks:   make([]checkResultResponse, 0, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		check := checkResultResponse{
+			Name:      result.Name,
+			Status:    result.Status,
+			Critical:  result.Critical,
+			LatencyMS: float64(result.Latency.Microseconds()) / 1000,
+		}
+		if result.Err != nil {
+			c.Error(fmt.Errorf("readiness check %s failed: %w", result.Name, result.Err))
+		}
+		response.Checks = append(response.Checks, check)
+	}
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, response)
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupHealthTestRouter(registry *health.Registry) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(recordExchanges())
+	NewHealthHandler(registry).RegisterRoutes(router)
+	return router
+}
+
+func TestHealthHandler_Live(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	router := setupHealthTestRouter(registry)
+
+	// Liveness ignores dependencies, so a database outage doesn't restart the process
+	for _, path := range []string{"/health", "/health/live"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	pass := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		register   func(r *health.Registry)
+		wantStatus int
+		wantBody   health.Status
+	}{
+		{
+			name: "all checks passing",
+			register: func(r *health.Registry) {
+				r.Register("database", pass)
+				r.RegisterOptional("pricing", pass)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   health.StatusUp,
+		},
+		{
+			name: "optional check failing",
+			register: func(r *health.Registry) {
+				r.Register("database", pass)
+				r.RegisterOptional("pricing", fail)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   health.StatusDegraded,
+		},
+		{
+			name: "critical check failing",
+			register: func(r *health.Registry) {
+				r.Register("database", fail)
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   health.StatusDown,
+		},
+		{
+			name: "draining",
+			register: func(r *health.Registry) {
+				r.Register("database", pass)
+				r.Drain()
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   health.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry(time.Second)
+			tt.register(registry)
+			router := setupHealthTestRouter(registry)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var response healthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Status != tt.wantBody {
+				t.Errorf("Expected status %q, got %q", tt.wantBody, response.Status)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Ready_ReportsEachCheck(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error { return nil })
+	registry.RegisterOptional("pricing", func(ctx context.Context) error {
+		return errors.New("prices older than 96h0m0s")
+	})
+	router := setupHealthTestRouter(registry)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	var response healthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Checks) != 2 {
+		t.Fatalf("Expected 2 checks, got %d", len(response.Checks))
+	}
+	if got := response.Checks[0]; got.Name != "database" || got.Status != health.StatusUp || !got.Critical {
+		t.Errorf("Unexpected database check %+v", got)
+	}
+	if got := response.Checks[1]; got.Name != "pricing" || got.Status != health.StatusDown || got.Critical {
+		t.Errorf("Unexpected pricing check %+v", got)
+	}
+}
+
+func TestHealthHandler_Ready_KeepsFailuresForTheLog(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error {
+		return errors.New("dial tcp: lookup mysql.internal.cushon on 10.0.0.2:53: no such host")
+	})
+
+	var logged []error
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Next()
+		for _, err := range c.Errors {
+			logged = append(logged, err.Err)
+		}
+	})
+	NewHealthHandler(registry).RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	// The probe is unauthenticated, so only the check's name and status are returned
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if body := w.Body.String(); strings.Contains(body, "mysql.internal.cushon") || strings.Contains(body, "error") {
+		t.Errorf("Expected the failure kept out of the response, got %s", body)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0].Error(), "readiness check database failed: dial tcp") {
+		t.Errorf("Expected the failure recorded for the logger, got %v", logged)
+	}
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header a client names a request with
+	// so that retrying it does not repeat it
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on a response replayed for a retried request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength is the longest key accepted, as long as the column it is kept in
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency is middleware that makes a request carrying an Idempotency-Key
+// header safe to retry. The first request with a key runs and its response is
+// kept until ttl has passed; a retry with the same key and body gets that
+// response again without running. A key reused with a different body is
+// refused with 422, and a retry while the first request is still running with
+// 409. Requests without the header run as usual.
+//
+// Keys are scoped to the principal, so it must run after Authenticate. A
+// request that fails with a server error or panics is not kept, so it can be
+// retried. While a request runs its key is held for pendingTTL, which must be
+// longer than any request may take, so a key whose request was cut off by
+// the server stopping is freed.
+func Idempotency(repo output.IdempotencyRepository, clock output.Clock, ttl, pendingTTL time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeProblem(c, http.StatusBadRequest, domain.NewValidationError(IdempotencyKeyHeader, fmt.Sprintf("idempotency key must be at most %d characters", maxIdempotencyKeyLength)))
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		principal, err := domain.PrincipalFromContext(ctx)
+		if err != nil {
+			writeProblem(c, http.StatusUnauthorized, err)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondWithBindError(c, err)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := domain.NewIdempotencyRecord(principal.Subject, key, requestHash(c.Request, body), clock.Now(), pendingTTL)
+		existing, err := repo.Reserve(ctx, record)
+		if err != nil {
+			respondWithError(c, err)
+			c.Abort()
+			return
+		}
+		if existing != nil {
+			replay(c, existing, record.RequestHash)
+			c.Abort()
+			return
+		}
+
+		// The request's own deadline may have passed by the time it ends, but
+		// what it did must still be recorded
+		ctx = context.WithoutCancel(ctx)
+		release := func() {
+			if err := repo.Release(ctx, record); err != nil {
+				c.Error(fmt.Errorf("failed to release idempotency key: %w", err))
+			}
+		}
+
+		// A panic is answered with a server error further up, so free the key
+		// for a retry before passing it on
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				release()
+				panic(recovered)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			release()
+			return
+		}
+
+		record.Complete(recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes(), clock.Now(), ttl)
+		err = repo.Complete(ctx, record)
+		switch {
+		case errors.Is(err, domain.ErrIdempotencyKeyNotFound):
+			// The request outlived its reservation, which may now be a retry's
+			c.Error(fmt.Errorf("idempotency key was freed before its request finished, so the response is not kept: %w", err))
+		case err != nil:
+			c.Error(fmt.Errorf("failed to record response for idempotency key: %w", err))
+		}
+	}
+}
+
+// replay answers a retried request with the response recorded for its key,
+// provided it is the same request
+func replay(c *gin.Context, existing *domain.IdempotencyRecord, hash string) {
+	switch {
+	case existing.RequestHash != hash:
+		writeProblem(c, http.StatusUnprocessableEntity, errors.New("idempotency key has already been used for a different request"))
+	case !existing.IsCompleted():
+		writeProblem(c, http.StatusConflict, domain.NewConflictError("a request with this idempotency key is still being processed"))
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+	}
+}
+
+// requestHash identifies a request by its method, path and body
+func requestHash(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", request.Method, request.URL.Path)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write implements io.Writer, keeping a copy of data
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implements io.StringWriter, keeping a copy of s
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockIdempotencyRepository implements output.IdempotencyRepository for testing
+type mockIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func newMockIdempotencyRepository() *mockIdempotencyRepository {
+	return &mockIdempotencyRepository{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func (m *mockIdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[record.Principal+"/"+record.Key]; ok && !existing.IsExpired(record.CreatedAt) {
+		return &existing, nil
+	}
+	m.records[record.Principal+"/"+record.Key] = *record
+	return nil, nil
+}
+
+func (m *mockIdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[record.Principal+"/"+record.Key]; !ok || !existing.SameReservation(record) {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+	stored := *record
+	stored.Body = slices.Clone(record.Body)
+	m.records[record.Principal+"/"+record.Key] = stored
+	return nil
+}
+
+func (m *mockIdempotencyRepository) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[record.Principal+"/"+record.Key]; ok && existing.SameReservation(record) {
+		delete(m.records, record.Principal+"/"+record.Key)
+	}
+	return nil
+}
+
+func (m *mockIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// mockClock implements output.Clock at a time the test moves
+type mockClock struct {
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	return c.now
+}
+
+func setupIdempotentTransactionRouter(service *MockTransactionService, repo *mockIdempotencyRepository, clock *mockClock, principal *domain.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(recordExchanges())
+	router.Use(authenticateAs(principal))
+	NewTransactionHandler(service).RegisterRoutes(router, Idempotency(repo, clock, 24*time.Hour, time.Minute))
+	return router
+}
+
+func postTransaction(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+const depositBody = `{"user_id":"user123","amount":"100.00","fund_name":"Cushon Equities Fund"}`
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	service := NewMockTransactionService()
+	router := setupIdempotentTransactionRouter(service, newMockIdempotencyRepository(), &mockClock{now: time.Now()}, testStaff)
+
+	first := postTransaction(router, "retry-1", depositBody)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, first.Code, first.Body.String())
+	}
+
+	retry := postTransaction(router, "retry-1", depositBody)
+	if retry.Code != http.StatusCreated {
+		t.Fatalf("Expected replayed status %d, got %d", http.StatusCreated, retry.Code)
+	}
+	if retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected replayed body %s, got %s", first.Body.String(), retry.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected %s header on the replayed response", IdempotentReplayedHeader)
+	}
+	if retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("Expected content type %q, got %q", first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	}
+	if len(service.transactions) != 1 {
+		t.Errorf("Expected 1 transaction, got %d", len(service.transactions))
+	}
+}
+
+func TestIdempotency_ReplaysErrors(t *testing.T) {
+	service := NewMockTransactionService()
+	router := setupIdempotentTransactionRouter(service, newMockIdempotencyRepository(), &mockClock{now: time.Now()}, testStaff)
+
+	body := `{"user_id":"user123","amount":"-5","fund_name":"Cushon Equities Fund"}`
+	first := postTransaction(router, "invalid-1", body)
+	retry := postTransaction(router, "invalid-1", body)
+
+	if first.Code != http.StatusBadRequest || retry.Code != http.StatusBadRequest {
+		t.Errorf("Expected both requests to get %d, got %d and %d", http.StatusBadRequest, first.Code, retry.Code)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected %s header on the replayed response", IdempotentReplayedHeader)
+	}
+}
+
+func TestIdempotency_DifferentRequest(t *testing.T) {
+	service := NewMockTransactionService()
+	router := setupIdempotentTransactionRouter(service, newMockIdempotencyRepository(), &mockClock{now: time.Now()}, testStaff)
+
+	postTransaction(router, "retry-1", depositBody)
+	w := postTransaction(router, "retry-1", `{"user_id":"user123","amount":"200.00","fund_name":"Cushon Equities Fund"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+	if len(service.transactions) != 1 {
+		t.Errorf("Expected 1 transaction, got %d", len(service.transactions))
+	}
+}
+
+func TestIdempotency_StillRunning(t *testing.T) {
+	service := NewMockTransactionService()
+	repo := newMockIdempotencyRepository()
+	clock := &mockClock{now: time.Now()}
+	router := setupIdempotentTransactionRouter(service, repo, clock, testStaff)
+
+	// The first request has been reserved but has not responded yet
+	req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
+	repo.Reserve(context.Background(), domain.NewIdempotencyRecord(testStaff.Subject, "retry-1", requestHash(req, []byte(depositBody)), clock.now, time.Hour))
+
+	w := postTransaction(router, "retry-1", depositBody)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if len(service.transactions) != 0 {
+		t.Errorf("Expected no transactions, got %d", len(service.transactions))
+	}
+}
+
+func TestIdempotency_KeysAreScopedAndExpire(t *testing.T) {
+	service := NewMockTransactionService()
+	repo := newMockIdempotencyRepository()
+	clock := &mockClock{now: time.Now()}
+
+	postTransaction(setupIdempotentTransactionRouter(service, repo, clock, testStaff), "retry-1", depositBody)
+
+	// Another principal choosing the same key makes a new request
+	support := &domain.Principal{Subject: "help@cushon.co.uk", Role: domain.RoleOperations}
+	postTransaction(setupIdempotentTransactionRouter(service, repo, clock, support), "retry-1", depositBody)
+	if len(service.transactions) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(service.transactions))
+	}
+
+	// Once expired the key starts a new request
+	clock.now = clock.now.Add(25 * time.Hour)
+	w := postTransaction(setupIdempotentTransactionRouter(service, repo, clock, testStaff), "retry-1", depositBody)
+	if w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("Expected a new response once the key expired")
+	}
+	if len(service.transactions) != 3 {
+		t.Errorf("Expected 3 transactions, got %d", len(service.transactions))
+	}
+}
+
+func TestIdempotency_WithoutKey(t *testing.T) {
+	service := NewMockTransactionService()
+	router := setupIdempotentTransactionRouter(service, newMockIdempotencyRepository(), &mockClock{now: time.Now()}, testStaff)
+
+	postTransaction(router, "", depositBody)
+	postTransaction(router, "", depositBody)
+
+	if len(service.transactions) != 2 {
+		t.Errorf("Expected 2 transactions without a key, got %d", len(service.transactions))
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := newMockIdempotencyRepository()
+	calls := 0
+	router := gin.New()
+	router.Use(authenticateAs(testStaff))
+	router.POST("/flaky", Idempotency(repo, &mockClock{now: time.Now()}, time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusNoContent, http.StatusNoContent} {
+		req := httptest.NewRequest(http.MethodPost, "/flaky", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Expected status %d, got %d", want, w.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected the handler to run twice, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_PanicsAreNotKept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := newMockIdempotencyRepository()
+	calls := 0
+	router := gin.New()
+	router.Use(gin.RecoveryWithWriter(io.Discard))
+	router.Use(authenticateAs(testStaff))
+	router.POST("/fragile", Idempotency(repo, &mockClock{now: time.Now()}, time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("something broke")
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, want := range []int{http.StatusInternalServerError, http.StatusNoContent} {
+		req := httptest.NewRequest(http.MethodPost, "/fragile", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Expected status %d, got %d", want, w.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected the retry to run the handler again, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_AbandonedRequestsFreeTheirKey(t *testing.T) {
+	service := NewMockTransactionService()
+	repo := newMockIdempotencyRepository()
+	clock := &mockClock{now: time.Now()}
+	router := setupIdempotentTransactionRouter(service, repo, clock, testStaff)
+
+	// The first request was reserved but the server stopped before it responded
+	req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
+	repo.Reserve(context.Background(), domain.NewIdempotencyRecord(testStaff.Subject, "retry-1", requestHash(req, []byte(depositBody)), clock.now, time.Minute))
+
+	clock.now = clock.now.Add(time.Minute)
+	w := postTransaction(router, "retry-1", depositBody)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d once the pending TTL passed, got %d", http.StatusCreated, w.Code)
+	}
+	if len(service.transactions) != 1 {
+		t.Errorf("Expected 1 transaction, got %d", len(service.transactions))
+	}
+}
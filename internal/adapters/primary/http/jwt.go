@@ -0,0 +1,306 @@
+package http
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"cushon/internal/core/domain"
+)
+
+// Signing algorithms a token may use. Tokens signed any other way, including
+// unsigned tokens with the "none" algorithm, are refused.
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+)
+
+// minHMACKeySize is the shortest HS256 key accepted, as long as the hash it keys
+const minHMACKeySize = sha256.Size
+
+// JWTConfig holds the settings for verifying JSON Web Tokens
+type JWTConfig struct {
+	// JWKSFile is the path of a JSON Web Key Set holding the keys tokens may be
+	// signed with: "oct" keys for HS256 and "RSA" keys for RS256
+	JWKSFile string
+	// Issuer, if set, must match a token's iss claim
+	Issuer string
+	// Audience, if set, must be among a token's aud claim
+	Audience string
+	// Leeway allows for clock skew when checking a token's exp and nbf claims
+	Leeway time.Duration
+}
+
+// JWTVerifier checks the signature and claims of JSON Web Tokens and returns
+// the principal a valid token names
+type JWTVerifier struct {
+	keys     []*verificationKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+// verificationKey is a key from the key set, usable with one algorithm
+type verificationKey struct {
+	id        string
+	algorithm string
+	secret    []byte
+	publicKey *rsa.PublicKey
+}
+
+// NewJWTVerifier creates a verifier trusting the keys in the configured key set file
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	if config.JWKSFile == "" {
+		return nil, errors.New("no key set file given")
+	}
+	data, err := os.ReadFile(config.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key set: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key set %s: %w", config.JWKSFile, err)
+	}
+
+	return &JWTVerifier{
+		keys:     keys,
+		issuer:   config.Issuer,
+		audience: config.Audience,
+		leeway:   config.Leeway,
+	}, nil
+}
+
+// jwk is a JSON Web Key as RFC 7517 writes it, with the members used for signing keys
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// K is the secret of a symmetric key
+	K string `json:"k"`
+	// N and E are the modulus and exponent of an RSA public key
+	N string `json:"n"`
+	E string `json:"e"`
+}
+
+// parseJWKS reads the signing keys from a JSON Web Key Set. Encryption keys are skipped.
+func parseJWKS(data []byte) ([]*verificationKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	var keys []*verificationKey
+	for i, key := range set.Keys {
+		if key.Use == "enc" {
+			continue
+		}
+		parsed, err := key.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		keys = append(keys, parsed)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+
+	return keys, nil
+}
+
+// verificationKey decodes the key material for the algorithm the key type implies
+func (k jwk) verificationKey() (*verificationKey, error) {
+	switch k.KeyType {
+	case "oct":
+		if k.Algorithm != "" && k.Algorithm != algHS256 {
+			return nil, fmt.Errorf("oct keys must be for %s, got %s", algHS256, k.Algorithm)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("invalid k: %w", err)
+		}
+		if len(secret) < minHMACKeySize {
+			return nil, fmt.Errorf("oct keys must be at least %d bytes, got %d", minHMACKeySize, len(secret))
+		}
+		return &verificationKey{id: k.KeyID, algorithm: algHS256, secret: secret}, nil
+	case "RSA":
+		if k.Algorithm != "" && k.Algorithm != algRS256 {
+			return nil, fmt.Errorf("RSA keys must be for %s, got %s", algRS256, k.Algorithm)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return nil, errors.New("invalid n")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid e")
+		}
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		if publicKey.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits, got %d", publicKey.N.BitLen())
+		}
+		return &verificationKey{id: k.KeyID, algorithm: algRS256, publicKey: publicKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// jwtClaims are the claims a token must carry to identify a principal
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Role      string   `json:"role"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+}
+
+// audience is the aud claim, which may be a single string or a list of them
+type audience []string
+
+// UnmarshalJSON accepts either form of the aud claim
+func (a *audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]string)(a))
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	*a = audience{single}
+	return nil
+}
+
+// Verify checks a compact serialised token and returns the principal it names.
+// Every failure matches domain.ErrUnauthenticated.
+func (v *JWTVerifier) Verify(token string) (*domain.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, domain.NewUnauthenticatedError("token is not a signed JWT")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, domain.NewUnauthenticatedError("token header is malformed")
+	}
+	key, err := v.key(header)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, domain.NewUnauthenticatedError("token signature is invalid")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, domain.NewUnauthenticatedError("token claims are malformed")
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return &domain.Principal{Subject: claims.Subject, Role: domain.Role(claims.Role)}, nil
+}
+
+// key picks the key a token's header names. A token without a key ID may be
+// verified only if the key set has a single key for its algorithm.
+func (v *JWTVerifier) key(header jwtHeader) (*verificationKey, error) {
+	if header.Algorithm != algHS256 && header.Algorithm != algRS256 {
+		return nil, domain.NewUnauthenticatedError(fmt.Sprintf("token algorithm %q is not accepted", header.Algorithm))
+	}
+
+	var candidates []*verificationKey
+	for _, key := range v.keys {
+		if key.algorithm != header.Algorithm {
+			continue
+		}
+		if header.KeyID == "" || key.id == header.KeyID {
+			candidates = append(candidates, key)
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case len(candidates) > 1:
+		return nil, domain.NewUnauthenticatedError("token does not say which key signed it")
+	default:
+		return nil, domain.NewUnauthenticatedError("token is signed with an unknown key")
+	}
+}
+
+// checkClaims checks the token is current, meant for this API, and names a principal
+func (v *JWTVerifier) checkClaims(claims jwtClaims) error {
+	now := time.Now()
+	if claims.ExpiresAt == nil {
+		return domain.NewUnauthenticatedError("token has no expiry")
+	}
+	if now.After(time.Unix(*claims.ExpiresAt, 0).Add(v.leeway)) {
+		return domain.NewUnauthenticatedError("token has expired")
+	}
+	if claims.NotBefore != nil && now.Before(time.Unix(*claims.NotBefore, 0).Add(-v.leeway)) {
+		return domain.NewUnauthenticatedError("token is not valid yet")
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return domain.NewUnauthenticatedError("token is from an untrusted issuer")
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return domain.NewUnauthenticatedError("token is meant for another audience")
+	}
+	if claims.Subject == "" {
+		return domain.NewUnauthenticatedError("token has no subject")
+	}
+	if !domain.Role(claims.Role).IsValid() {
+		return domain.NewUnauthenticatedError(fmt.Sprintf("token role %q is not known", claims.Role))
+	}
+	return nil
+}
+
+// verify reports whether signature is the key's signature of the signing input
+func (k *verificationKey) verify(signingInput, signature []byte) bool {
+	switch k.algorithm {
+	case algHS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(signingInput)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case algRS256:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(k.publicKey, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return false
+	}
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,287 @@
+package http
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+)
+
+// testSecret is the HS256 key of the test key set
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// testRSAKey is the RS256 key of the test key set, generated once as it is slow
+var testRSAKey = func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// encodeSegment base64url encodes v as JSON, as a token segment
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal token segment: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signToken signs claims with the test key for the algorithm, naming the key ID in the header
+func signToken(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	header := map[string]any{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	signingInput := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+
+	var signature []byte
+	switch alg {
+	case algHS256:
+		mac := hmac.New(sha256.New, testSecret)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case algRS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, testRSAKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// validClaims returns the claims of a current customer token for the test verifier
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":  "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+		"role": "customer",
+		"iss":  "https://auth.cushon.co.uk",
+		"aud":  "isa-api",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// newTestVerifier writes the test key set to a file and returns a verifier trusting it
+func newTestVerifier(t *testing.T) *JWTVerifier {
+	t.Helper()
+	jwks := map[string]any{
+		"keys": []map[string]any{
+			{"kty": "oct", "kid": "hmac-1", "alg": "HS256", "k": base64.RawURLEncoding.EncodeToString(testSecret)},
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(testRSAKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(testRSAKey.E)).Bytes()),
+			},
+			{"kty": "RSA", "kid": "rsa-enc", "use": "enc"},
+		},
+	}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("Failed to marshal key set: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write key set: %v", err)
+	}
+
+	verifier, err := NewJWTVerifier(JWTConfig{
+		JWKSFile: path,
+		Issuer:   "https://auth.cushon.co.uk",
+		Audience: "isa-api",
+		Leeway:   30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+	return verifier
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	verifier := newTestVerifier(t)
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr string
+	}{
+		{
+			name:  "HS256",
+			token: func(t *testing.T) string { return signToken(t, algHS256, "hmac-1", validClaims()) },
+		},
+		{
+			name:  "RS256",
+			token: func(t *testing.T) string { return signToken(t, algRS256, "rsa-1", validClaims()) },
+		},
+		{
+			name:  "only key for its algorithm without a key ID",
+			token: func(t *testing.T) string { return signToken(t, algRS256, "", validClaims()) },
+		},
+		{
+			name: "audience in a list",
+			token: func(t *testing.T) string {
+				return signToken(t, algHS256, "hmac-1", with("aud", []string{"portal", "isa-api"}))
+			},
+		},
+		{
+			name: "expired within the leeway",
+			token: func(t *testing.T) string {
+				return signToken(t, algHS256, "hmac-1", with("exp", time.Now().Add(-10*time.Second).Unix()))
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return signToken(t, algHS256, "hmac-1", with("exp", time.Now().Add(-time.Minute).Unix()))
+			},
+			wantErr: "token has expired",
+		},
+		{
+			name:    "no expiry",
+			token:   func(t *testing.T) string { return signToken(t, algHS256, "hmac-1", with("exp", nil)) },
+			wantErr: "token has no expiry",
+		},
+		{
+			name: "not valid yet",
+			token: func(t *testing.T) string {
+				return signToken(t, algHS256, "hmac-1", with("nbf", time.Now().Add(time.Minute).Unix()))
+			},
+			wantErr: "token is not valid yet",
+		},
+		{
+			name: "another issuer",
+			token: func(t *testing.T) string {
+				return signToken(t, algHS256, "hmac-1", with("iss", "https://evil.example"))
+			},
+			wantErr: "untrusted issuer",
+		},
+		{
+			name:    "another audience",
+			token:   func(t *testing.T) string { return signToken(t, algHS256, "hmac-1", with("aud", "portal")) },
+			wantErr: "another audience",
+		},
+		{
+			name:    "no subject",
+			token:   func(t *testing.T) string { return signToken(t, algHS256, "hmac-1", with("sub", nil)) },
+			wantErr: "token has no subject",
+		},
+		{
+			name:    "unknown role",
+			token:   func(t *testing.T) string { return signToken(t, algHS256, "hmac-1", with("role", "superuser")) },
+			wantErr: `token role "superuser" is not known`,
+		},
+		{
+			name: "tampered claims",
+			token: func(t *testing.T) string {
+				parts := strings.Split(signToken(t, algHS256, "hmac-1", validClaims()), ".")
+				parts[1] = encodeSegment(t, with("role", "admin"))
+				return strings.Join(parts, ".")
+			},
+			wantErr: "token signature is invalid",
+		},
+		{
+			name: "unsigned",
+			token: func(t *testing.T) string {
+				return encodeSegment(t, map[string]any{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+			},
+			wantErr: `token algorithm "none" is not accepted`,
+		},
+		{
+			name:    "key used with another algorithm",
+			token:   func(t *testing.T) string { return signToken(t, algHS256, "rsa-1", validClaims()) },
+			wantErr: "token is signed with an unknown key",
+		},
+		{
+			name:    "unknown key",
+			token:   func(t *testing.T) string { return signToken(t, algHS256, "hmac-2", validClaims()) },
+			wantErr: "token is signed with an unknown key",
+		},
+		{
+			name:    "not a JWT",
+			token:   func(t *testing.T) string { return "not-a-token" },
+			wantErr: "token is not a signed JWT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(tt.token(t))
+
+			if tt.wantErr != "" {
+				if !errors.Is(err, domain.ErrUnauthenticated) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an unauthenticated error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			want := domain.Principal{Subject: "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e", Role: domain.RoleCustomer}
+			if *principal != want {
+				t.Errorf("Expected principal %+v, got %+v", want, *principal)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifier_InvalidKeySet(t *testing.T) {
+	tests := []struct {
+		name    string
+		jwks    string
+		wantErr string
+	}{
+		{name: "no file", wantErr: "no key set file given"},
+		{name: "not JSON", jwks: "keys", wantErr: "invalid character"},
+		{name: "no keys", jwks: `{"keys":[]}`, wantErr: "no signing keys"},
+		{name: "short secret", jwks: `{"keys":[{"kty":"oct","k":"c2hvcnQ"}]}`, wantErr: "at least 32 bytes"},
+		{name: "unsupported key type", jwks: `{"keys":[{"kty":"EC","crv":"P-256"}]}`, wantErr: `unsupported key type "EC"`},
+		{name: "mismatched algorithm", jwks: `{"keys":[{"kty":"RSA","alg":"HS256","n":"AQAB","e":"AQAB"}]}`, wantErr: "RSA keys must be for RS256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.jwks != "" {
+				path = filepath.Join(t.TempDir(), "jwks.json")
+				if err := os.WriteFile(path, []byte(tt.jwks), 0o600); err != nil {
+					t.Fatalf("Failed to write key set: %v", err)
+				}
+			}
+
+			_, err := NewJWTVerifier(JWTConfig{JWKSFile: path})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewJWTVerifier() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID of a request, so the changes it makes can be
+// traced in the audit log
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs clients may choose
+const maxRequestIDLength = 255
+
+// RequestTimeout bounds the context of each request, so the queries a request
+// runs are cancelled once it has taken longer than timeout, rather than
+// holding a database connection for a client that has given up
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequestID identifies each request by the ID the client sent in the
+// X-Request-ID header, or a new one if it sent none, echoes it in the
+// response and puts it on the request's context for the audit log
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.New().String()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(domain.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// Authenticate requires each request to carry a bearer token the verifier
+// accepts, and puts the principal the token names on the request's context
+// for the services to authorise against
+func Authenticate(verifier *JWTVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", `Bearer`)
+			writeProblem(c, http.StatusUnauthorized, domain.NewUnauthenticatedError("a bearer token is required"))
+			c.Abort()
+			return
+		}
+
+		principal, err := verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeProblem(c, http.StatusUnauthorized, err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testStaff is the member of staff the handler tests act as
+var testStaff = &domain.Principal{Subject: "ops@cushon.co.uk", Role: domain.RoleAdmin}
+
+// authenticateAs stands in for Authenticate in the handler tests, putting
+// the principal on every request's context
+func authenticateAs(principal *domain.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.ContextWithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestTimeout(20 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		// Stands in for a query that runs until its context is cancelled
+		select {
+		case <-c.Request.Context().Done():
+			respondWithError(c, c.Request.Context().Err())
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the request to be cut off at the timeout, took %s", elapsed)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authenticate(newTestVerifier(t)))
+	router.GET("/whoami", func(c *gin.Context) {
+		principal, err := domain.PrincipalFromContext(c.Request.Context())
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		c.String(http.StatusOK, principal.Subject)
+	})
+
+	tests := []struct {
+		name              string
+		authorization     string
+		expectedStatus    int
+		expectedChallenge string
+	}{
+		{
+			name:           "valid token",
+			authorization:  "Bearer " + signToken(t, algHS256, "hmac-1", validClaims()),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:              "no token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: "Bearer",
+		},
+		{
+			name:              "another scheme",
+			authorization:     "Basic b3BzOnNlY3JldA==",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: "Bearer",
+		},
+		{
+			name:              "invalid token",
+			authorization:     "Bearer not-a-token",
+			expectedStatus:    http.StatusUnauthorized,
+			expectedChallenge: `Bearer error="invalid_token"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.expectedChallenge {
+				t.Errorf("Expected WWW-Authenticate %q, got %q", tt.expectedChallenge, got)
+			}
+			if tt.expectedStatus == http.StatusOK && w.Body.String() != validClaims()["sub"] {
+				t.Errorf("Expected the token's subject on the context, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/request-id", func(c *gin.Context) {
+		c.String(http.StatusOK, domain.RequestIDFromContext(c.Request.Context()))
+	})
+
+	// The client's ID is kept
+	req := httptest.NewRequest(http.MethodGet, "/request-id", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get(RequestIDHeader); got != "req-123" {
+		t.Errorf("Expected the client's request ID echoed, got %q", got)
+	}
+	if w.Body.String() != "req-123" {
+		t.Errorf("Expected the client's request ID on the context, got %q", w.Body.String())
+	}
+
+	// Without one a new ID is made
+	req = httptest.NewRequest(http.MethodGet, "/request-id", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	generated := w.Header().Get(RequestIDHeader)
+	if generated == "" || generated != w.Body.String() {
+		t.Errorf("Expected a new request ID echoed and on the context, got %q and %q", generated, w.Body.String())
+	}
+}
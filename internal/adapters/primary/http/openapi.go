@@ -0,0 +1,57 @@
+package http
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPISpec is the OpenAPI 3.1 document describing the API
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI, loaded from a CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Cushon ISA API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// OpenAPIHandler serves the API's OpenAPI document and a page for browsing it
+type OpenAPIHandler struct{}
+
+// NewOpenAPIHandler creates a new OpenAPI handler
+func NewOpenAPIHandler() *OpenAPIHandler {
+	return &OpenAPIHandler{}
+}
+
+// RegisterRoutes registers the OpenAPI routes
+func (h *OpenAPIHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/openapi.json", h.GetSpec)
+	router.GET("/docs", h.GetDocs)
+}
+
+// GetSpec returns the OpenAPI document
+func (h *OpenAPIHandler) GetSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
+
+// GetDocs returns the Swagger UI page for the OpenAPI document
+func (h *OpenAPIHandler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
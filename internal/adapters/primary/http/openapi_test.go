@@ -0,0 +1,553 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestMain checks every request the handler tests made, and the response they
+// got, against the OpenAPI document once the tests have run, so a handler and
+// the document cannot drift apart unnoticed. A full run that recorded nothing
+// fails, as it would otherwise pass without checking anything; a run of
+// selected tests with -run may not reach a documented route.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if code == 0 {
+		err := checkExchanges()
+		if errors.Is(err, errNoExchanges) && flag.Lookup("test.run").Value.String() != "" {
+			err = nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "OpenAPI contract: %v\n", err)
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+// exchange is a request made by a handler test and the response it got
+type exchange struct {
+	method      string
+	path        string
+	query       map[string][]string
+	header      http.Header
+	requestBody []byte
+
+	status       int
+	contentType  string
+	responseBody []byte
+}
+
+func (e exchange) String() string {
+	return fmt.Sprintf("%s %s (%d)", e.method, e.path, e.status)
+}
+
+var (
+	exchangesMu sync.Mutex
+	exchanges   []exchange
+)
+
+// captureWriter keeps a copy of the response body as it is written
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// recordExchanges is middleware that records each request and response for
+// checkExchanges. Test routers for the documented routes use it.
+func recordExchanges() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody []byte
+		if c.Request.Body != nil {
+			requestBody, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		exchangesMu.Lock()
+		defer exchangesMu.Unlock()
+		exchanges = append(exchanges, exchange{
+			method:       c.Request.Method,
+			path:         c.Request.URL.Path,
+			query:        c.Request.URL.Query(),
+			header:       c.Request.Header.Clone(),
+			requestBody:  requestBody,
+			status:       writer.Status(),
+			contentType:  writer.Header().Get("Content-Type"),
+			responseBody: writer.body.Bytes(),
+		})
+	}
+}
+
+// errNoExchanges is returned by checkExchanges when there is nothing to check
+var errNoExchanges = errors.New("no exchanges were recorded, so none were checked")
+
+// checkExchanges checks every recorded exchange against the OpenAPI document
+func checkExchanges() error {
+	spec, err := loadSpec()
+	if err != nil {
+		return err
+	}
+
+	exchangesMu.Lock()
+	defer exchangesMu.Unlock()
+	if len(exchanges) == 0 {
+		return errNoExchanges
+	}
+
+	var errs []error
+	for _, e := range exchanges {
+		if err := spec.check(e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// openAPIDoc is the OpenAPI document decoded into generic JSON values
+type openAPIDoc struct {
+	root map[string]any
+}
+
+func loadSpec() (*openAPIDoc, error) {
+	var root map[string]any
+	if err := json.Unmarshal(openAPISpec, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi.json: %w", err)
+	}
+	return &openAPIDoc{root: root}, nil
+}
+
+// resolve follows a local $ref, returning node itself if it has none
+func (d *openAPIDoc) resolve(node map[string]any) (map[string]any, error) {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, nil
+		}
+		var target any = d.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			object, ok := target.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("unresolvable $ref %s", ref)
+			}
+			target = object[part]
+		}
+		if node, ok = target.(map[string]any); !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+	}
+}
+
+// operation finds the path item and operation serving a request. A path
+// template matching more literal segments wins over one with more parameters.
+func (d *openAPIDoc) operation(method, path string) (map[string]any, map[string]any, error) {
+	paths, _ := d.root["paths"].(map[string]any)
+	segments := strings.Split(path, "/")
+
+	var bestItem map[string]any
+	bestLiterals := -1
+	for template, item := range paths {
+		templateSegments := strings.Split(template, "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+		literals := 0
+		matches := true
+		for i, segment := range templateSegments {
+			switch {
+			case strings.HasPrefix(segment, "{"):
+				matches = matches && segments[i] != ""
+			case segment == segments[i]:
+				literals++
+			default:
+				matches = false
+			}
+		}
+		if matches && literals > bestLiterals {
+			bestItem, _ = item.(map[string]any)
+			bestLiterals = literals
+		}
+	}
+	if bestItem == nil {
+		return nil, nil, errors.New("path is not documented")
+	}
+
+	op, ok := bestItem[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return nil, nil, errors.New("method is not documented for the path")
+	}
+	return bestItem, op, nil
+}
+
+// parameters lists the parameters of an operation, including those shared by its path
+func (d *openAPIDoc) parameters(item, op map[string]any) ([]map[string]any, error) {
+	var params []map[string]any
+	for _, owner := range []map[string]any{item, op} {
+		list, _ := owner["parameters"].([]any)
+		for _, raw := range list {
+			node, _ := raw.(map[string]any)
+			param, err := d.resolve(node)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+		}
+	}
+	return params, nil
+}
+
+// check checks an exchange's query parameters, status, content type and
+// bodies against the document. Requests are only held to the document when
+// they succeeded, since the handler tests send invalid requests on purpose.
+func (d *openAPIDoc) check(e exchange) error {
+	item, op, err := d.operation(e.method, e.path)
+	if err != nil {
+		return err
+	}
+
+	params, err := d.parameters(item, op)
+	if err != nil {
+		return err
+	}
+	for name := range e.query {
+		if !slices.ContainsFunc(params, func(p map[string]any) bool { return p["in"] == "query" && p["name"] == name }) {
+			return fmt.Errorf("query parameter %q is not documented", name)
+		}
+	}
+
+	succeeded := e.status >= 200 && e.status < 300
+	if succeeded {
+		for _, param := range params {
+			if param["required"] != true {
+				continue
+			}
+			name, _ := param["name"].(string)
+			switch param["in"] {
+			case "query":
+				if _, ok := e.query[name]; !ok {
+					return fmt.Errorf("required query parameter %q is missing", name)
+				}
+			case "header":
+				if e.header.Get(name) == "" {
+					return fmt.Errorf("required header %q is missing", name)
+				}
+			}
+		}
+		if requestBody, ok := op["requestBody"].(map[string]any); ok {
+			if err := d.checkBody(requestBody, e.header.Get("Content-Type"), e.requestBody); err != nil {
+				return fmt.Errorf("request body: %w", err)
+			}
+		}
+	}
+
+	responses, _ := op["responses"].(map[string]any)
+	node, ok := responses[fmt.Sprint(e.status)].(map[string]any)
+	if !ok {
+		return errors.New("response status is not documented")
+	}
+	response, err := d.resolve(node)
+	if err != nil {
+		return err
+	}
+	if err := d.checkBody(response, e.contentType, e.responseBody); err != nil {
+		return fmt.Errorf("response body: %w", err)
+	}
+	return nil
+}
+
+// checkBody checks a body against a request body or response object. A body
+// is required exactly when the object documents content.
+func (d *openAPIDoc) checkBody(object map[string]any, contentType string, body []byte) error {
+	content, _ := object["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(body) > 0 {
+			return errors.New("no body is documented")
+		}
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		return fmt.Errorf("content type %q is not documented", contentType)
+	}
+	schema, _ := media["schema"].(map[string]any)
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return d.validate(schema, value, "$")
+}
+
+// validate checks a JSON value against a schema, supporting the subset of
+// JSON Schema the document uses
+func (d *openAPIDoc) validate(schema map[string]any, value any, at string) error {
+	schema, err := d.resolve(schema)
+	if err != nil {
+		return err
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		matched := 0
+		for _, option := range oneOf {
+			node, _ := option.(map[string]any)
+			if d.validate(node, value, at) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s matches %d of the oneOf schemas, want 1", at, matched)
+		}
+	}
+
+	// Every integer is also a number
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		valueType := jsonType(value)
+		if !slices.Contains(types, valueType) && !(valueType == "integer" && slices.Contains(types, "number")) {
+			return fmt.Errorf("%s is %s, want %s", at, valueType, strings.Join(types, " or "))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return fmt.Errorf("%s is %v, want one of %v", at, value, enum)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return fmt.Errorf("%s is %q, which does not match %s", at, v, pattern)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				return fmt.Errorf("%s is %q, not an RFC 3339 time", at, v)
+			}
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			return fmt.Errorf("%s is %v, below the minimum %v", at, v, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			return fmt.Errorf("%s is %v, above the maximum %v", at, v, maximum)
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := d.validate(items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("%s is missing required property %q", at, name)
+			}
+		}
+		for name, property := range v {
+			propertySchema, ok := properties[name].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s has undocumented property %q", at, name)
+				}
+				continue
+			}
+			if err := d.validate(propertySchema, property, at+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes lists the types a schema allows, given as a name or a list of names
+func schemaTypes(raw any) []string {
+	switch t := raw.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			types = append(types, name.(string))
+		}
+		return types
+	}
+	return nil
+}
+
+// jsonType names the JSON Schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewOpenAPIHandler().RegisterRoutes(router)
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+	}{
+		{name: "document", path: "/openapi.json", contentType: "application/json"},
+		{name: "swagger ui", path: "/docs", contentType: "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Expected content type %s, got %s", tt.contentType, got)
+			}
+		})
+	}
+}
+
+func TestOpenAPISpec_RefsResolve(t *testing.T) {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.root["openapi"] != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %v", spec.root["openapi"])
+	}
+
+	// Every $ref in the document points at something
+	var walk func(node any)
+	walk = func(node any) {
+		switch n := node.(type) {
+		case map[string]any:
+			if _, ok := n["$ref"]; ok {
+				if _, err := spec.resolve(n); err != nil {
+					t.Error(err)
+				}
+			}
+			for _, child := range n {
+				walk(child)
+			}
+		case []any:
+			for _, child := range n {
+				walk(child)
+			}
+		}
+	}
+	walk(spec.root)
+}
+
+func TestOpenAPISpec_Check(t *testing.T) {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	user := `{"id":"1","name":"John Doe","created_at":"2024-06-14T09:30:00Z","updated_at":"2024-06-14T09:30:00Z"}`
+
+	tests := []struct {
+		name     string
+		exchange exchange
+		wantErr  string
+	}{
+		{
+			name:     "documented exchange",
+			exchange: exchange{method: "GET", path: "/direct-users/1", status: 200, contentType: "application/json; charset=utf-8", responseBody: []byte(user)},
+		},
+		{
+			name:     "undocumented path",
+			exchange: exchange{method: "GET", path: "/accounts/1", status: 200},
+			wantErr:  "path is not documented",
+		},
+		{
+			name:     "undocumented status",
+			exchange: exchange{method: "GET", path: "/direct-users/1", status: 418},
+			wantErr:  "response status is not documented",
+		},
+		{
+			name:     "undocumented query parameter",
+			exchange: exchange{method: "GET", path: "/transactions/user/1", query: map[string][]string{"page": {"2"}}, status: 200},
+			wantErr:  `query parameter "page" is not documented`,
+		},
+		{
+			name:     "undocumented response property",
+			exchange: exchange{method: "GET", path: "/direct-users/1", status: 200, contentType: "application/json", responseBody: []byte(strings.Replace(user, `"id"`, `"email":"a@b.c","id"`, 1))},
+			wantErr:  `undocumented property "email"`,
+		},
+		{
+			name:     "wrong property type",
+			exchange: exchange{method: "GET", path: "/direct-users/1", status: 200, contentType: "application/json", responseBody: []byte(strings.Replace(user, `"John Doe"`, `7`, 1))},
+			wantErr:  "$.name is integer, want string",
+		},
+		{
+			name:     "invalid request body on success",
+			exchange: exchange{method: "POST", path: "/direct-users", header: jsonHeader, requestBody: []byte(`{}`), status: 201, contentType: "application/json", responseBody: []byte(user)},
+			wantErr:  `missing required property "name"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.exchange.header == nil {
+				tt.exchange.header = http.Header{}
+			}
+			err := spec.check(tt.exchange)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
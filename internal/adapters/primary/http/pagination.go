@@ -0,0 +1,108 @@
+package http
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// encodeCursor turns a cursor into the opaque token a client passes back to
+// fetch the next page
+func encodeCursor(cursor *domain.TransactionCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return encodePosition(cursor.CreatedAt, cursor.ID)
+}
+
+// decodeCursor reads a token made by encodeCursor, nil if the token is empty
+func decodeCursor(token string) (*domain.TransactionCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	createdAt, id, err := decodePosition(token)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TransactionCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// encodeAuditCursor turns an audit log cursor into the token a client passes
+// back to fetch the next page
+func encodeAuditCursor(cursor *domain.AuditCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return encodePosition(cursor.CreatedAt, cursor.ID)
+}
+
+// decodeAuditCursor reads a token made by encodeAuditCursor, nil if the token is empty
+func decodeAuditCursor(token string) (*domain.AuditCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	createdAt, id, err := decodePosition(token)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuditCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// encodePosition encodes the time and ID that order a paged list
+func encodePosition(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "," + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePosition reads a token made by encodePosition
+func decodePosition(token string) (time.Time, string, error) {
+	invalid := domain.NewValidationError("cursor", "invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	createdAt, id, found := strings.Cut(string(raw), ",")
+	if !found || id == "" {
+		return time.Time{}, "", invalid
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+
+	return parsed, id, nil
+}
+
+// queryTime reads a time query parameter given as an RFC 3339 timestamp or a
+// date, which is taken as midnight UTC. A missing parameter is the zero time.
+func queryTime(c *gin.Context, name string) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, domain.NewValidationError(name, name+" must be a date or an RFC 3339 time")
+}
+
+// queryInt reads an integer query parameter, zero if it is missing
+func queryInt(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, domain.NewValidationError(name, name+" must be a whole number")
+	}
+	return parsed, nil
+}
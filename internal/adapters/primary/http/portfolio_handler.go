@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PortfolioHandler handles HTTP requests for portfolio valuation
+type PortfolioHandler struct {
+	portfolioService input.PortfolioService
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(portfolioService input.PortfolioService) *PortfolioHandler {
+	return &PortfolioHandler{
+		portfolioService: portfolioService,
+	}
+}
+
+// RegisterRoutes registers the portfolio routes
+func (h *PortfolioHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/direct-users/:id/portfolio", h.GetPortfolio)
+}
+
+// GetPortfolio handles portfolio valuation for a direct user
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	id := c.Param("id")
+	portfolio, err := h.portfolioService.GetPortfolio(c.Request.Context(), id)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPortfolioResponse(portfolio))
+}
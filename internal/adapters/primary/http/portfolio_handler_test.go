@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// MockPortfolioService implements input.PortfolioService for testing
+type MockPortfolioService struct {
+	portfolios map[string]*domain.Portfolio
+}
+
+func NewMockPortfolioService() *MockPortfolioService {
+	return &MockPortfolioService{
+		portfolios: make(map[string]*domain.Portfolio),
+	}
+}
+
+func (m *MockPortfolioService) GetPortfolio(ctx context.Context, userID string) (*domain.Portfolio, error) {
+	if portfolio, exists := m.portfolios[userID]; exists {
+		return portfolio, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func setupPortfolioTestRouter(service input.PortfolioService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(recordExchanges())
+	handler := NewPortfolioHandler(service)
+	handler.RegisterRoutes(router)
+	return router
+}
+
+func TestPortfolioHandler_GetPortfolio(t *testing.T) {
+	service := NewMockPortfolioService()
+	router := setupPortfolioTestRouter(service)
+
+	service.portfolios["user123"] = domain.NewPortfolio("user123", []*domain.Holding{
+		{
+			FundName:      domain.CushonEquitiesFund,
+			Units:         decimal.NewFromInt(500),
+			BookCost:      decimal.NewFromInt(1000),
+			MarketValue:   decimal.NewFromInt(1100),
+			PendingAmount: decimal.Zero,
+		},
+	}, time.Now())
+
+	tests := []struct {
+		name           string
+		userID         string
+		expectedStatus int
+	}{
+		{
+			name:           "existing user",
+			userID:         "user123",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "non-existent user",
+			userID:         "non-existent",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/direct-users/"+tt.userID+"/portfolio", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response portfolioResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Errorf("Failed to unmarshal response: %v", err)
+				}
+				if response.MarketValue != "1100" {
+					t.Errorf("Expected market value 1100, got %s", response.MarketValue)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"cushon/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// problemContentType is the media type of an RFC 7807 problem details body
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body describing an error response
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam names a request field that failed validation and why
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// respondWithError writes the problem details response for an error returned by the core
+func respondWithError(c *gin.Context, err error) {
+	writeProblem(c, statusFor(err), err)
+}
+
+// respondWithBindError writes the problem details response for a request body that could not be bound
+func respondWithBindError(c *gin.Context, err error) {
+	writeProblem(c, http.StatusBadRequest, domain.NewValidationError("", err.Error()))
+}
+
+// statusFor maps the kind of a core error to an HTTP status code
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrAllowanceExceeded), errors.Is(err, domain.ErrInsufficientBalance):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeProblem writes err as a problem details body with the given status.
+// The detail of an internal error or a timeout is not exposed; it is recorded
+// on the context for the logger instead.
+func writeProblem(c *gin.Context, status int, err error) {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: c.Request.URL.Path,
+	}
+
+	switch status {
+	case http.StatusInternalServerError:
+		c.Error(err)
+		problem.Detail = "internal server error"
+	case http.StatusServiceUnavailable:
+		c.Error(err)
+		problem.Detail = "the request did not complete in time"
+	}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) && validationErr.Field != "" {
+		problem.InvalidParams = []InvalidParam{{Name: validationErr.Field, Reason: validationErr.Message}}
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(status, problem)
+}
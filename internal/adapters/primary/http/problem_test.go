@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cushon/internal/core/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+func TestRespondWithError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedDetail string
+	}{
+		{
+			name:           "validation",
+			err:            domain.NewValidationError("amount", "amount must be positive"),
+			expectedStatus: http.StatusBadRequest,
+			expectedDetail: "amount must be positive",
+		},
+		{
+			name:           "unauthenticated",
+			err:            domain.NewUnauthenticatedError("token has expired"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedDetail: "token has expired",
+		},
+		{
+			name:           "forbidden",
+			err:            domain.NewForbiddenError("the direct user's account belongs to someone else"),
+			expectedStatus: http.StatusForbidden,
+			expectedDetail: "the direct user's account belongs to someone else",
+		},
+		{
+			name:           "not found",
+			err:            domain.ErrTransactionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedDetail: "transaction not found",
+		},
+		{
+			name:           "conflict",
+			err:            domain.ErrFundExists,
+			expectedStatus: http.StatusConflict,
+			expectedDetail: "fund already exists",
+		},
+		{
+			name:           "allowance exceeded",
+			err:            &domain.AllowanceExceededError{TaxYear: 2024, Limit: decimal.NewFromInt(20000), Used: decimal.NewFromInt(20000), Requested: decimal.NewFromInt(1)},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedDetail: "deposit of 1.00 exceeds the 2024/25 ISA allowance: 20000.00 of 20000.00 already used",
+		},
+		{
+			name:           "insufficient balance",
+			err:            &domain.InsufficientBalanceError{FundName: domain.CushonEquitiesFund, Available: decimal.NewFromInt(10), Requested: decimal.NewFromInt(20)},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedDetail: "insufficient balance in Cushon Equities Fund: 10.00 available, 20.00 requested",
+		},
+		{
+			name:           "timeout is not exposed",
+			err:            fmt.Errorf("failed to query transactions: %w", context.DeadlineExceeded),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedDetail: "the request did not complete in time",
+		},
+		{
+			name:           "internal error is not exposed",
+			err:            errors.New("dial tcp 127.0.0.1:3306: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedDetail: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/test", func(c *gin.Context) {
+				respondWithError(c, tt.err)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != problemContentType {
+				t.Errorf("Expected content type %s, got %s", problemContentType, contentType)
+			}
+
+			var problem Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if problem.Status != tt.expectedStatus || problem.Title != http.StatusText(tt.expectedStatus) {
+				t.Errorf("Expected status %d with its title, got %d %q", tt.expectedStatus, problem.Status, problem.Title)
+			}
+			if problem.Detail != tt.expectedDetail {
+				t.Errorf("Expected detail %q, got %q", tt.expectedDetail, problem.Detail)
+			}
+			if problem.Instance != "/test" {
+				t.Errorf("Expected instance /test, got %s", problem.Instance)
+			}
+		})
+	}
+}
+
+func TestRespondWithError_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/test", func(c *gin.Context) {
+		respondWithError(c, domain.NewValidationError("isin", "invalid ISIN"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(problem.InvalidParams) != 1 || problem.InvalidParams[0] != (InvalidParam{Name: "isin", Reason: "invalid ISIN"}) {
+		t.Errorf("Expected isin to be reported as invalid, got %+v", problem.InvalidParams)
+	}
+}
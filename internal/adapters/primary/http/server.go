@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ServerConfig holds the address and timeouts of the HTTP server
+type ServerConfig struct {
+	Addr string
+	// ReadTimeout limits reading a whole request, ReadHeaderTimeout just its headers
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout limits handling a request and writing the response
+	WriteTimeout time.Duration
+	// IdleTimeout limits how long a keep-alive connection waits for the next request
+	IdleTimeout time.Duration
+	// ShutdownDelay is how long the server keeps accepting requests after it is
+	// told to stop, reporting not ready, so load balancers stop sending traffic
+	// before connections are refused
+	ShutdownDelay time.Duration
+	// ShutdownTimeout limits how long in-flight requests are given to finish on shutdown
+	ShutdownTimeout time.Duration
+}
+
+// Server serves the API over HTTP until it is told to stop, then stops
+// accepting connections and lets in-flight requests finish
+type Server struct {
+	server          *http.Server
+	shutdownDelay   time.Duration
+	shutdownTimeout time.Duration
+	onShutdown      []func()
+}
+
+// NewServer creates a server for the handler
+func NewServer(handler http.Handler, config ServerConfig) *Server {
+	return &Server{
+		server: &http.Server{
+			Addr:              config.Addr,
+			Handler:           handler,
+			ReadTimeout:       config.ReadTimeout,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+		},
+		shutdownDelay:   config.ShutdownDelay,
+		shutdownTimeout: config.ShutdownTimeout,
+	}
+}
+
+// OnShutdown registers a function to call as soon as the server is told to
+// stop, before the shutdown delay, such as failing the readiness probe
+func (s *Server) OnShutdown(f func()) {
+	s.onShutdown = append(s.onShutdown, f)
+}
+
+// Run listens on the configured address and serves until ctx is done
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves connections from the listener until ctx is done, then shuts
+// down gracefully. It returns an error if the server fails, or if in-flight
+// requests are still running when the shutdown timeout expires.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	log.Printf("Listening on %s", listener.Addr())
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	for _, f := range s.onShutdown {
+		f()
+	}
+	if s.shutdownDelay > 0 {
+		log.Printf("Shutting down, serving for another %s while traffic drains", s.shutdownDelay)
+		time.Sleep(s.shutdownDelay)
+	}
+
+	log.Printf("Shutting down, waiting up to %s for in-flight requests", s.shutdownTimeout)
+
+	// The parent context is already done, so the deadline starts afresh
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(shutdownCtx); err != nil {
+		// Cut off whatever is still running rather than leave it to the process exit
+		s.server.Close()
+		return fmt.Errorf("in-flight requests did not finish before the shutdown deadline: %w", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	log.Println("Server stopped")
+	return nil
+}
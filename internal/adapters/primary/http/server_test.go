@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// startServer serves on a local port until the returned cancel is called, and
+// returns the server's URL and the channel Serve's result is sent on
+func startServer(t *testing.T, server *Server) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve(ctx, listener)
+	}()
+	t.Cleanup(cancel)
+
+	return "http://" + listener.Addr().String(), cancel, done
+}
+
+func TestServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "deposited")
+	})
+
+	url, stop, done := startServer(t, NewServer(handler, ServerConfig{ShutdownTimeout: 5 * time.Second}))
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	<-started
+	stop()
+
+	// The server stops accepting new connections while the request is in flight
+	time.Sleep(100 * time.Millisecond)
+	if _, err := http.Get(url); err == nil {
+		t.Error("Expected a new request to be refused during shutdown")
+	}
+
+	close(release)
+	if got := <-response; got != "deposited" {
+		t.Errorf("In-flight request got %q, want it to complete", got)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve() error = %v, want nil", err)
+	}
+}
+
+func TestServer_ShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	url, stop, done := startServer(t, NewServer(handler, ServerConfig{ShutdownTimeout: 50 * time.Millisecond}))
+
+	go http.Get(url)
+	<-started
+	stop()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Serve() error = nil, want the shutdown deadline to be reported")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after the shutdown deadline")
+	}
+}
+
+func TestServer_ShutdownDelay(t *testing.T) {
+	server := NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}), ServerConfig{ShutdownDelay: 200 * time.Millisecond, ShutdownTimeout: time.Second})
+	notified := make(chan struct{})
+	server.OnShutdown(func() { close(notified) })
+
+	url, stop, done := startServer(t, server)
+	stop()
+	<-notified
+
+	// New requests are still served during the delay, so none are refused
+	// before load balancers have seen the instance go unready
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Expected requests to be served during the shutdown delay: %v", err)
+	}
+	resp.Body.Close()
+
+	if err := <-done; err != nil {
+		t.Errorf("Serve() error = %v, want nil", err)
+	}
+}
@@ -1,13 +1,13 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/input"
 
 	"github.com/gin-gonic/gin"
-	"github.com/shopspring/decimal"
 )
 
 // TransactionHandler handles HTTP requests for transaction operations
@@ -22,144 +22,157 @@ func NewTransactionHandler(transactionService input.TransactionService) *Transac
 	}
 }
 
-// RegisterRoutes registers the transaction routes
-func (h *TransactionHandler) RegisterRoutes(router *gin.Engine) {
+// RegisterRoutes registers the transaction routes. The create middleware, such
+// as Idempotency, runs before a transaction is created and nowhere else.
+func (h *TransactionHandler) RegisterRoutes(router *gin.Engine, create ...gin.HandlerFunc) {
 	transactions := router.Group("/transactions")
 	{
-		transactions.POST("", h.CreateTransaction)
+		transactions.POST("", append(create, h.CreateTransaction)...)
 		transactions.GET("/:id", h.GetTransaction)
 		transactions.GET("/user/:userID", h.GetUserTransactions)
+		transactions.GET("/user/:userID/allowance", h.GetAllowance)
 		transactions.PUT("/:id", h.UpdateTransaction)
 		transactions.DELETE("/:id", h.DeleteTransaction)
 	}
-
-	// Add route for getting available fund names
-	router.GET("/fund-names", h.GetFundNames)
-}
-
-// GetFundNames returns a list of available fund names
-func (h *TransactionHandler) GetFundNames(c *gin.Context) {
-	fundNames := []string{
-		string(domain.CushonEquitiesFund),
-	}
-	c.JSON(http.StatusOK, fundNames)
 }
 
 // CreateTransaction handles transaction creation
 func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
-	var request struct {
-		UserID   string          `json:"user_id" binding:"required"`
-		Amount   decimal.Decimal `json:"amount" binding:"required"`
-		FundName string          `json:"fund_name" binding:"required"`
-	}
-
+	var request createTransactionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithBindError(c, err)
 		return
 	}
 
+	// Transactions without a type are deposits
+	transactionType := domain.TransactionTypeDeposit
+	if request.Type != "" {
+		transactionType = domain.TransactionType(request.Type)
+	}
+
 	transaction, err := h.transactionService.CreateTransaction(
+		c.Request.Context(),
 		request.UserID,
+		transactionType,
 		request.Amount,
 		domain.FundName(request.FundName),
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		// The user is part of the request body, so an unknown user is unprocessable rather than not found
+		if errors.Is(err, domain.ErrUserNotFound) {
+			writeProblem(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, transaction)
+	c.JSON(http.StatusCreated, newTransactionResponse(transaction))
 }
 
 // GetTransaction handles transaction retrieval
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	id := c.Param("id")
-	transaction, err := h.transactionService.GetTransaction(id)
+	transaction, err := h.transactionService.GetTransaction(c.Request.Context(), id)
 	if err != nil {
-		switch err.Error() {
-		case "transaction ID is required":
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case "transaction not found":
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	c.JSON(http.StatusOK, newTransactionResponse(transaction))
 }
 
-// GetUserTransactions handles user transactions retrieval
+// GetUserTransactions handles user transaction history retrieval. The history
+// can be filtered by time recorded, fund and type, and is paged with the
+// cursor returned with each page.
 func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
-	userID := c.Param("userID")
-	transactions, err := h.transactionService.GetUserTransactions(userID)
+	query, err := transactionQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, transactions)
+	page, err := h.transactionService.GetUserTransactions(c.Request.Context(), query)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTransactionPageResponse(page))
 }
 
-// UpdateTransaction handles transaction updates
-func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
-	id := c.Param("id")
-	var request struct {
-		Amount   decimal.Decimal `json:"amount" binding:"required"`
-		FundName string          `json:"fund_name" binding:"required"`
+// transactionQuery reads a transaction history query from the request
+func transactionQuery(c *gin.Context) (domain.TransactionQuery, error) {
+	query := domain.TransactionQuery{
+		UserID:   c.Param("userID"),
+		FundName: domain.FundName(c.Query("fund")),
+		Type:     domain.TransactionType(c.Query("type")),
+		Sort:     domain.SortOrder(c.Query("sort")),
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+	var err error
+	if query.From, err = queryTime(c, "from"); err != nil {
+		return query, err
+	}
+	if query.To, err = queryTime(c, "to"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = queryInt(c, "limit"); err != nil {
+		return query, err
 	}
+	if query.After, err = decodeCursor(c.Query("cursor")); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
 
-	transaction, err := h.transactionService.GetTransaction(id)
+// GetAllowance handles retrieval of a user's remaining ISA allowance
+func (h *TransactionHandler) GetAllowance(c *gin.Context) {
+	userID := c.Param("userID")
+	allowance, err := h.transactionService.GetAllowance(c.Request.Context(), userID)
 	if err != nil {
-		switch err.Error() {
-		case "transaction ID is required":
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case "transaction not found":
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		respondWithError(c, err)
 		return
 	}
 
-	transaction.Amount = request.Amount
-	transaction.FundName = domain.FundName(request.FundName)
+	c.JSON(http.StatusOK, newAllowanceResponse(allowance))
+}
 
-	if err := h.transactionService.UpdateTransaction(transaction); err != nil {
-		switch err.Error() {
-		case "transaction not found":
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		case "invalid fund name":
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+// UpdateTransaction handles transaction corrections. The original entry is
+// reversed and replaced by a corrected entry; both are returned.
+func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
+	id := c.Param("id")
+	var request correctTransactionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithBindError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	reversal, err := h.transactionService.CorrectTransaction(
+		c.Request.Context(),
+		id,
+		request.Amount,
+		domain.FundName(request.FundName),
+		request.Reason,
+	)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newReversalResponse(reversal))
 }
 
-// DeleteTransaction handles transaction deletion
+// DeleteTransaction handles transaction reversals. Transactions are never
+// deleted: a reversing entry is recorded and returned with the original.
 func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.transactionService.DeleteTransaction(id); err != nil {
-		switch err.Error() {
-		case "transaction ID is required":
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case "transaction not found":
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+	reversal, err := h.transactionService.ReverseTransaction(c.Request.Context(), id, c.Query("reason"))
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.Status(http.StatusNoContent)
-} 
\ No newline at end of file
+	c.JSON(http.StatusOK, newReversalResponse(reversal))
+}
@@ -1,24 +1,31 @@
 package http
 
 import (
+	"context"
 	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/input"
 
-	"errors"
-
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
 )
 
+// unknownUserID is a user the mock transaction service does not recognise
+const unknownUserID = "unknown-user"
+
 // MockTransactionService implements input.TransactionService for testing
 type MockTransactionService struct {
 	transactions map[string]*domain.Transaction
+	// allowanceLimit caps each user's deposits when non-zero
+	allowanceLimit decimal.Decimal
+	// lastQuery is the last transaction history query received
+	lastQuery domain.TransactionQuery
 }
 
 func NewMockTransactionService() *MockTransactionService {
@@ -27,87 +34,151 @@ func NewMockTransactionService() *MockTransactionService {
 	}
 }
 
-func (m *MockTransactionService) CreateTransaction(userID string, amount decimal.Decimal, fundName domain.FundName) (*domain.Transaction, error) {
+func (m *MockTransactionService) CreateTransaction(ctx context.Context, userID string, transactionType domain.TransactionType, amount decimal.Decimal, fundName domain.FundName) (*domain.Transaction, error) {
 	if userID == "" {
-		return nil, errors.New("user ID is required")
+		return nil, domain.NewValidationError("user_id", "user ID is required")
+	}
+	if !transactionType.IsValid() {
+		return nil, domain.NewValidationError("type", "invalid transaction type")
 	}
-	if amount.IsZero() {
-		return nil, errors.New("amount cannot be zero")
+	if !amount.IsPositive() {
+		return nil, domain.NewValidationError("amount", "amount must be positive")
+	}
+	if userID == unknownUserID {
+		return nil, domain.ErrUserNotFound
 	}
 	if !fundName.IsValid() {
-		return nil, errors.New("invalid fund name")
+		return nil, domain.NewValidationError("fund_name", "invalid fund name")
+	}
+	if transactionType.IsDebit() {
+		balance := decimal.Zero
+		for _, transaction := range m.transactions {
+			if transaction.UserID == userID && transaction.FundName == fundName {
+				balance = balance.Add(transaction.SignedAmount())
+			}
+		}
+		if amount.GreaterThan(balance) {
+			return nil, &domain.InsufficientBalanceError{FundName: fundName, Available: balance, Requested: amount}
+		}
+	}
+	if transactionType == domain.TransactionTypeDeposit && !m.allowanceLimit.IsZero() {
+		used := m.depositsFor(userID)
+		if used.Add(amount).GreaterThan(m.allowanceLimit) {
+			return nil, &domain.AllowanceExceededError{Limit: m.allowanceLimit, Used: used, Requested: amount}
+		}
 	}
 
-	transaction := domain.NewTransaction(userID, amount, fundName)
+	transaction := domain.NewTransaction(userID, transactionType, amount, fundName, time.Now())
 	m.transactions[transaction.ID] = transaction
 	return transaction, nil
 }
 
-func (m *MockTransactionService) GetTransaction(id string) (*domain.Transaction, error) {
+func (m *MockTransactionService) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
 	if id == "" {
-		return nil, errors.New("transaction ID is required")
+		return nil, domain.NewValidationError("id", "transaction ID is required")
 	}
 
 	if transaction, exists := m.transactions[id]; exists {
 		return transaction, nil
 	}
-	return nil, errors.New("transaction not found")
+	return nil, domain.ErrTransactionNotFound
 }
 
-func (m *MockTransactionService) GetUserTransactions(userID string) ([]*domain.Transaction, error) {
-	if userID == "" {
-		return nil, errors.New("user ID is required")
+func (m *MockTransactionService) GetUserTransactions(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error) {
+	if query.UserID == "" {
+		return nil, domain.NewValidationError("user_id", "user ID is required")
+	}
+	if query.UserID == unknownUserID {
+		return nil, domain.ErrUserNotFound
 	}
+	m.lastQuery = query
 
-	var userTransactions []*domain.Transaction
+	page := &domain.TransactionPage{Transactions: []*domain.Transaction{}}
 	for _, transaction := range m.transactions {
-		if transaction.UserID == userID {
-			userTransactions = append(userTransactions, transaction)
+		if query.Matches(transaction) {
+			page.Transactions = append(page.Transactions, transaction)
 		}
 	}
-	return userTransactions, nil
+	page.Total = len(page.Transactions)
+	return page, nil
 }
 
-func (m *MockTransactionService) UpdateTransaction(transaction *domain.Transaction) error {
-	if transaction == nil {
-		return errors.New("transaction cannot be nil")
+func (m *MockTransactionService) GetAllowance(ctx context.Context, userID string) (*domain.Allowance, error) {
+	if userID == "" {
+		return nil, domain.NewValidationError("user_id", "user ID is required")
 	}
-	if transaction.ID == "" {
-		return errors.New("transaction ID is required")
+	if userID == unknownUserID {
+		return nil, domain.ErrUserNotFound
 	}
-	if !transaction.FundName.IsValid() {
-		return errors.New("invalid fund name")
+
+	return domain.NewAllowance(userID, domain.TaxYearFor(time.Now()), m.allowanceLimit, m.depositsFor(userID)), nil
+}
+
+func (m *MockTransactionService) depositsFor(userID string) decimal.Decimal {
+	total := decimal.Zero
+	for _, transaction := range m.transactions {
+		if transaction.UserID == userID && transaction.Type == domain.TransactionTypeDeposit {
+			total = total.Add(transaction.SignedAmount())
+		}
 	}
+	return total
+}
 
-	existingTransaction, exists := m.transactions[transaction.ID]
-	if !exists {
-		return errors.New("transaction not found")
+func (m *MockTransactionService) CorrectTransaction(ctx context.Context, id string, amount decimal.Decimal, fundName domain.FundName, reason string) (*domain.Reversal, error) {
+	if !fundName.IsValid() {
+		return nil, domain.NewValidationError("fund_name", "invalid fund name")
+	}
+
+	result, err := m.ReverseTransaction(ctx, id, reason)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update only allowed fields
-	existingTransaction.Amount = transaction.Amount
-	existingTransaction.FundName = transaction.FundName
+	correction := domain.NewTransaction(result.Original.UserID, result.Original.Type, amount, fundName, time.Now())
+	correction.Reason = reason
+	correction.Actor = result.Reversal.Actor
+	m.transactions[correction.ID] = correction
 
-	m.transactions[transaction.ID] = existingTransaction
-	return nil
+	result.Correction = correction
+	return result, nil
 }
 
-func (m *MockTransactionService) DeleteTransaction(id string) error {
+func (m *MockTransactionService) ReverseTransaction(ctx context.Context, id, reason string) (*domain.Reversal, error) {
 	if id == "" {
-		return errors.New("transaction ID is required")
+		return nil, domain.NewValidationError("id", "transaction ID is required")
+	}
+	if reason == "" {
+		return nil, domain.NewValidationError("reason", "reason is required")
+	}
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, exists := m.transactions[id]; !exists {
-		return errors.New("transaction not found")
+	original, exists := m.transactions[id]
+	if !exists {
+		return nil, domain.ErrTransactionNotFound
+	}
+	for _, transaction := range m.transactions {
+		if transaction.ReversalOf == id {
+			return nil, domain.ErrAlreadyReversed
+		}
 	}
 
-	delete(m.transactions, id)
-	return nil
+	reversal, err := original.Reverse(reason, principal.Subject, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	m.transactions[reversal.ID] = reversal
+
+	return &domain.Reversal{Original: original, Reversal: reversal}, nil
 }
 
 func setupTransactionTestRouter(service input.TransactionService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(recordExchanges())
+	router.Use(authenticateAs(testStaff))
 	handler := NewTransactionHandler(service)
 	handler.RegisterRoutes(router)
 	return router
@@ -142,6 +213,59 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  true,
 		},
+		{
+			name: "withdrawal within balance",
+			payload: map[string]interface{}{
+				"user_id":   "user123",
+				"type":      "withdrawal",
+				"amount":    "1000.0000",
+				"fund_name": "Cushon Equities Fund",
+			},
+			expectedStatus: http.StatusCreated,
+			expectedError:  false,
+		},
+		{
+			name: "withdrawal exceeding balance",
+			payload: map[string]interface{}{
+				"user_id":   "user123",
+				"type":      "withdrawal",
+				"amount":    "30000.0000",
+				"fund_name": "Cushon Equities Fund",
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  true,
+		},
+		{
+			name: "negative amount",
+			payload: map[string]interface{}{
+				"user_id":   "user123",
+				"amount":    "-100.0000",
+				"fund_name": "Cushon Equities Fund",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name: "invalid type",
+			payload: map[string]interface{}{
+				"user_id":   "user123",
+				"type":      "refund",
+				"amount":    "100.0000",
+				"fund_name": "Cushon Equities Fund",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name: "unknown user",
+			payload: map[string]interface{}{
+				"user_id":   unknownUserID,
+				"amount":    "100.0000",
+				"fund_name": "Cushon Equities Fund",
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -158,7 +282,7 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 			}
 
 			if !tt.expectedError {
-				var response domain.Transaction
+				var response transactionResponse
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 					t.Errorf("Failed to unmarshal response: %v", err)
 				}
@@ -170,13 +294,61 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionHandler_CreateTransaction_AllowanceExceeded(t *testing.T) {
+	service := NewMockTransactionService()
+	service.allowanceLimit = domain.DefaultISAAllowance
+	router := setupTransactionTestRouter(service)
+
+	service.CreateTransaction(context.Background(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(19000), domain.CushonEquitiesFund)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"user_id":   "user123",
+		"amount":    "1000.01",
+		"fund_name": "Cushon Equities Fund",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestTransactionHandler_GetAllowance(t *testing.T) {
+	service := NewMockTransactionService()
+	service.allowanceLimit = domain.DefaultISAAllowance
+	router := setupTransactionTestRouter(service)
+
+	service.CreateTransaction(context.Background(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(4000), domain.CushonEquitiesFund)
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/user/user123/allowance", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response allowanceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Errorf("Failed to unmarshal response: %v", err)
+	}
+	if response.Remaining != "16000" {
+		t.Errorf("Expected remaining allowance 16000, got %s", response.Remaining)
+	}
+}
+
 func TestTransactionHandler_GetTransaction(t *testing.T) {
 	service := NewMockTransactionService()
 	router := setupTransactionTestRouter(service)
 
 	// Create a test transaction
 	amount, _ := decimal.NewFromString("25000.0000")
-	transaction, _ := service.CreateTransaction("user123", amount, domain.FundName("Cushon Equities Fund"))
+	transaction, _ := service.CreateTransaction(context.Background(), "user123", domain.TransactionTypeDeposit, amount, domain.FundName("Cushon Equities Fund"))
 
 	tests := []struct {
 		name           string
@@ -210,7 +382,7 @@ func TestTransactionHandler_GetTransaction(t *testing.T) {
 			}
 
 			if !tt.expectedError {
-				var response domain.Transaction
+				var response transactionResponse
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 					t.Errorf("Failed to unmarshal response: %v", err)
 				}
@@ -228,7 +400,7 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 
 	// Create test transactions
 	amount, _ := decimal.NewFromString("25000.0000")
-	service.CreateTransaction("user123", amount, domain.FundName("Cushon Equities Fund"))
+	service.CreateTransaction(context.Background(), "user123", domain.TransactionTypeDeposit, amount, domain.FundName("Cushon Equities Fund"))
 
 	tests := []struct {
 		name           string
@@ -248,6 +420,11 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedCount:  0,
 		},
+		{
+			name:           "unknown user",
+			userID:         unknownUserID,
+			expectedStatus: http.StatusNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -260,13 +437,104 @@ func TestTransactionHandler_GetUserTransactions(t *testing.T) {
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
 			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
 
-			var response []domain.Transaction
+			var response transactionPageResponse
 			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
 				t.Errorf("Failed to unmarshal response: %v", err)
 			}
-			if len(response) != tt.expectedCount {
-				t.Errorf("Expected %d transactions, got %d", tt.expectedCount, len(response))
+			if len(response.Transactions) != tt.expectedCount || response.Total != tt.expectedCount {
+				t.Errorf("Expected %d transactions, got %d of %d", tt.expectedCount, len(response.Transactions), response.Total)
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_GetUserTransactions_Query(t *testing.T) {
+	service := NewMockTransactionService()
+	router := setupTransactionTestRouter(service)
+	cursor := &domain.TransactionCursor{CreatedAt: time.Date(2024, 5, 1, 9, 30, 0, 123456789, time.UTC), ID: "txn-1"}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedParam  string
+		expected       domain.TransactionQuery
+	}{
+		{
+			name:           "no parameters",
+			expectedStatus: http.StatusOK,
+			expected:       domain.TransactionQuery{UserID: "user123"},
+		},
+		{
+			name:           "every parameter",
+			query:          "?from=2024-04-06&to=2025-04-06T00:00:00Z&fund=Cushon+Equities+Fund&type=deposit&limit=10&sort=created_at&cursor=" + encodeCursor(cursor),
+			expectedStatus: http.StatusOK,
+			expected: domain.TransactionQuery{
+				UserID:   "user123",
+				From:     time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2025, 4, 6, 0, 0, 0, 0, time.UTC),
+				FundName: domain.CushonEquitiesFund,
+				Type:     domain.TransactionTypeDeposit,
+				Sort:     domain.SortOldestFirst,
+				Limit:    10,
+				After:    cursor,
+			},
+		},
+		{
+			name:           "invalid date",
+			query:          "?from=last-week",
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "from",
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=ten",
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "limit",
+		},
+		{
+			name:           "invalid cursor",
+			query:          "?cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.lastQuery = domain.TransactionQuery{}
+			req := httptest.NewRequest(http.MethodGet, "/transactions/user/user123"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				var problem Problem
+				if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+					t.Fatalf("Failed to unmarshal problem: %v", err)
+				}
+				if len(problem.InvalidParams) != 1 || problem.InvalidParams[0].Name != tt.expectedParam {
+					t.Errorf("Expected invalid param %q, got %+v", tt.expectedParam, problem.InvalidParams)
+				}
+				return
+			}
+
+			got := service.lastQuery
+			if got.UserID != tt.expected.UserID || !got.From.Equal(tt.expected.From) || !got.To.Equal(tt.expected.To) ||
+				got.FundName != tt.expected.FundName || got.Type != tt.expected.Type ||
+				got.Sort != tt.expected.Sort || got.Limit != tt.expected.Limit {
+				t.Errorf("Expected query %+v, got %+v", tt.expected, got)
+			}
+			if (got.After == nil) != (tt.expected.After == nil) ||
+				got.After != nil && (!got.After.CreatedAt.Equal(tt.expected.After.CreatedAt) || got.After.ID != tt.expected.After.ID) {
+				t.Errorf("Expected cursor %+v, got %+v", tt.expected.After, got.After)
 			}
 		})
 	}
@@ -278,9 +546,9 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 
 	// Create a test transaction
 	amount, _ := decimal.NewFromString("25000.0000")
-	transaction, _ := service.CreateTransaction("user123", amount, domain.FundName("Cushon Equities Fund"))
+	transaction, _ := service.CreateTransaction(context.Background(), "user123", domain.TransactionTypeDeposit, amount, domain.FundName("Cushon Equities Fund"))
 
-	// Expected amount for update
+	// Expected amount for the correction
 	expectedAmount, _ := decimal.NewFromString("30000.0000")
 
 	tests := []struct {
@@ -293,23 +561,46 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 		expectedFund   string
 	}{
 		{
-			name:          "valid update",
+			name:          "missing reason",
+			transactionID: transaction.ID,
+			payload: map[string]interface{}{
+				"amount":    "30000.0000",
+				"fund_name": "Cushon Equities Fund",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:          "valid correction",
 			transactionID: transaction.ID,
 			payload: map[string]interface{}{
 				"amount":    "30000.0000",
 				"fund_name": "Cushon Equities Fund",
+				"reason":    "wrong amount",
 			},
 			expectedStatus: http.StatusOK,
 			expectedError:  false,
 			expectedAmount: expectedAmount,
 			expectedFund:   "Cushon Equities Fund",
 		},
+		{
+			name:          "already reversed",
+			transactionID: transaction.ID,
+			payload: map[string]interface{}{
+				"amount":    "30000.0000",
+				"fund_name": "Cushon Equities Fund",
+				"reason":    "wrong amount",
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
 		{
 			name:          "non-existent transaction",
 			transactionID: "non-existent",
 			payload: map[string]interface{}{
 				"amount":    "30000.0000",
 				"fund_name": "Cushon Equities Fund",
+				"reason":    "wrong amount",
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  true,
@@ -330,26 +621,32 @@ func TestTransactionHandler_UpdateTransaction(t *testing.T) {
 			}
 
 			if !tt.expectedError {
-				var response domain.Transaction
+				var response reversalResponse
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
-					t.Errorf("Failed to unmarshal response: %v", err)
+					t.Fatalf("Failed to unmarshal response: %v", err)
 				}
-				if response.ID != tt.transactionID {
-					t.Errorf("Expected ID %s, got %s", tt.transactionID, response.ID)
+				if response.Reversal.ReversalOf != tt.transactionID {
+					t.Fatalf("Expected a reversal of %s, got %+v", tt.transactionID, response.Reversal)
+				}
+				if response.Correction == nil {
+					t.Fatal("Expected a correcting entry, got nil")
+				}
+				if response.Correction.Amount != tt.expectedAmount.String() {
+					t.Errorf("Expected amount %s, got %s", tt.expectedAmount.String(), response.Correction.Amount)
 				}
-				if !response.Amount.Equal(tt.expectedAmount) {
-					t.Errorf("Expected amount %s, got %s", tt.expectedAmount.String(), response.Amount.String())
+				if response.Correction.FundName != tt.expectedFund {
+					t.Errorf("Expected fund name %s, got %s", tt.expectedFund, response.Correction.FundName)
 				}
-				if string(response.FundName) != tt.expectedFund {
-					t.Errorf("Expected fund name %s, got %s", tt.expectedFund, response.FundName)
+				if response.Correction.Actor != testStaff.Subject {
+					t.Errorf("Expected actor %s, got %s", testStaff.Subject, response.Correction.Actor)
 				}
 			} else {
-				var errorResponse map[string]string
-				if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+				var problem Problem
+				if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
 					t.Errorf("Failed to unmarshal error response: %v", err)
 				}
-				if errorResponse["error"] == "" {
-					t.Error("Expected error message in response, got empty string")
+				if problem.Status != tt.expectedStatus || problem.Detail == "" {
+					t.Errorf("Expected problem with status %d and a detail, got %+v", tt.expectedStatus, problem)
 				}
 			}
 		})
@@ -362,23 +659,35 @@ func TestTransactionHandler_DeleteTransaction(t *testing.T) {
 
 	// Create a test transaction
 	amount, _ := decimal.NewFromString("25000.0000")
-	transaction, _ := service.CreateTransaction("user123", amount, domain.FundName("Cushon Equities Fund"))
+	transaction, _ := service.CreateTransaction(context.Background(), "user123", domain.TransactionTypeDeposit, amount, domain.FundName("Cushon Equities Fund"))
 
 	tests := []struct {
 		name           string
-		transactionID  string
+		path           string
 		expectedStatus int
 		expectedError  bool
 	}{
+		{
+			name:           "missing reason",
+			path:           "/transactions/" + transaction.ID,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
 		{
 			name:           "existing transaction",
-			transactionID:  transaction.ID,
-			expectedStatus: http.StatusNoContent,
+			path:           "/transactions/" + transaction.ID + "?reason=duplicate",
+			expectedStatus: http.StatusOK,
 			expectedError:  false,
 		},
+		{
+			name:           "already reversed",
+			path:           "/transactions/" + transaction.ID + "?reason=duplicate",
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
 		{
 			name:           "non-existent transaction",
-			transactionID:  "non-existent",
+			path:           "/transactions/non-existent?reason=duplicate",
 			expectedStatus: http.StatusNotFound,
 			expectedError:  true,
 		},
@@ -386,7 +695,7 @@ func TestTransactionHandler_DeleteTransaction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodDelete, "/transactions/"+tt.transactionID, nil)
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
 			w := httptest.NewRecorder()
 
 			router.ServeHTTP(w, req)
@@ -394,6 +703,19 @@ func TestTransactionHandler_DeleteTransaction(t *testing.T) {
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
 			}
+
+			if !tt.expectedError {
+				var response reversalResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				if response.Original.ID != transaction.ID {
+					t.Errorf("Expected original %s in response, got %+v", transaction.ID, response.Original)
+				}
+				if response.Reversal.Reason != "duplicate" {
+					t.Errorf("Expected reversal with reason duplicate, got %+v", response.Reversal)
+				}
+			}
 		})
 	}
-} 
\ No newline at end of file
+}
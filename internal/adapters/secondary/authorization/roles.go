@@ -0,0 +1,126 @@
+// Package authorization implements the output.Authorizer port with a fixed
+// table of the permissions each role holds.
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// rolePermissions lists the permissions each role holds. A role missing from
+// the table holds none.
+var rolePermissions = map[domain.Role][]domain.Permission{
+	domain.RoleCustomer: {
+		domain.PermissionViewAccount,
+		domain.PermissionUpdateDirectUser,
+		domain.PermissionCreateTransaction,
+		domain.PermissionViewFunds,
+	},
+	domain.RoleSupport: {
+		domain.PermissionViewAccount,
+		domain.PermissionViewFunds,
+		domain.PermissionViewAuditLog,
+	},
+	domain.RoleOperations: {
+		domain.PermissionViewAccount,
+		domain.PermissionCreateDirectUser,
+		domain.PermissionUpdateDirectUser,
+		domain.PermissionCreateTransaction,
+		domain.PermissionBookTransaction,
+		domain.PermissionCorrectTransaction,
+		domain.PermissionViewFunds,
+		domain.PermissionManageFunds,
+		domain.PermissionViewAuditLog,
+	},
+	domain.RoleAdmin: {
+		domain.PermissionViewAccount,
+		domain.PermissionCreateDirectUser,
+		domain.PermissionUpdateDirectUser,
+		domain.PermissionDeleteDirectUser,
+		domain.PermissionCreateTransaction,
+		domain.PermissionBookTransaction,
+		domain.PermissionCorrectTransaction,
+		domain.PermissionViewFunds,
+		domain.PermissionManageFunds,
+		domain.PermissionViewAuditLog,
+	},
+}
+
+// RoleBased implements the output.Authorizer interface by the principal's
+// role, logging every denial
+type RoleBased struct {
+	grants map[domain.Role]map[domain.Permission]bool
+	logger *log.Logger
+}
+
+// NewRoleBased creates an authorizer that records denials to logger
+func NewRoleBased(logger *log.Logger) output.Authorizer {
+	grants := make(map[domain.Role]map[domain.Permission]bool, len(rolePermissions))
+	for role, permissions := range rolePermissions {
+		grants[role] = make(map[domain.Permission]bool, len(permissions))
+		for _, permission := range permissions {
+			grants[role][permission] = true
+		}
+	}
+	return &RoleBased{grants: grants, logger: logger}
+}
+
+// Authorize checks the principal's role holds the permission and, for a
+// customer, that the account is their own
+func (a *RoleBased) Authorize(ctx context.Context, permission domain.Permission, ownerID string) error {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !a.grants[principal.Role][permission] {
+		return a.deny(principal, permission, ownerID, fmt.Sprintf("the %s role may not %s", principal.Role, describe(permission)))
+	}
+	if ownerID != "" && !principal.CanAccessUser(ownerID) {
+		return a.deny(principal, permission, ownerID, "the direct user's account belongs to someone else")
+	}
+
+	return nil
+}
+
+// deny records a refused request and returns the error refusing it
+func (a *RoleBased) deny(principal *domain.Principal, permission domain.Permission, ownerID, reason string) error {
+	if ownerID == "" {
+		a.logger.Printf("Access denied: %s (%s) asked for %s: %s", principal.Subject, principal.Role, permission, reason)
+	} else {
+		a.logger.Printf("Access denied: %s (%s) asked for %s on account %q: %s", principal.Subject, principal.Role, permission, ownerID, reason)
+	}
+	return domain.NewForbiddenError(reason)
+}
+
+// describe says what a permission allows, for the message of a denial
+func describe(permission domain.Permission) string {
+	switch permission {
+	case domain.PermissionViewAccount:
+		return "view accounts"
+	case domain.PermissionCreateDirectUser:
+		return "create direct users"
+	case domain.PermissionUpdateDirectUser:
+		return "update direct users"
+	case domain.PermissionDeleteDirectUser:
+		return "delete direct users"
+	case domain.PermissionCreateTransaction:
+		return "deposit or withdraw"
+	case domain.PermissionBookTransaction:
+		return "book fees, interest or transfers"
+	case domain.PermissionCorrectTransaction:
+		return "correct or reverse transactions"
+	case domain.PermissionViewFunds:
+		return "view funds"
+	case domain.PermissionManageFunds:
+		return "manage funds"
+	case domain.PermissionViewAuditLog:
+		return "view the audit log"
+	default:
+		return string(permission)
+	}
+}
@@ -0,0 +1,85 @@
+package authorization
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"cushon/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contextAs(role domain.Role, subject string) context.Context {
+	return domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: subject, Role: role})
+}
+
+func TestRoleBased_Authorize(t *testing.T) {
+	authorizer := NewRoleBased(log.New(&bytes.Buffer{}, "", 0))
+
+	tests := []struct {
+		name       string
+		role       domain.Role
+		permission domain.Permission
+		ownerID    string
+		allowed    bool
+	}{
+		{name: "customer viewing their own account", role: domain.RoleCustomer, permission: domain.PermissionViewAccount, ownerID: "user-1", allowed: true},
+		{name: "customer viewing another account", role: domain.RoleCustomer, permission: domain.PermissionViewAccount, ownerID: "user-2"},
+		{name: "customer depositing into their own account", role: domain.RoleCustomer, permission: domain.PermissionCreateTransaction, ownerID: "user-1", allowed: true},
+		{name: "customer booking interest to their own account", role: domain.RoleCustomer, permission: domain.PermissionBookTransaction, ownerID: "user-1"},
+		{name: "operations booking interest", role: domain.RoleOperations, permission: domain.PermissionBookTransaction, ownerID: "user-2", allowed: true},
+		{name: "customer correcting their own transaction", role: domain.RoleCustomer, permission: domain.PermissionCorrectTransaction, ownerID: "user-1"},
+		{name: "customer creating a direct user", role: domain.RoleCustomer, permission: domain.PermissionCreateDirectUser},
+		{name: "customer viewing funds", role: domain.RoleCustomer, permission: domain.PermissionViewFunds, allowed: true},
+		{name: "support viewing any account", role: domain.RoleSupport, permission: domain.PermissionViewAccount, ownerID: "user-2", allowed: true},
+		{name: "support renaming a direct user", role: domain.RoleSupport, permission: domain.PermissionUpdateDirectUser, ownerID: "user-2"},
+		{name: "support correcting a transaction", role: domain.RoleSupport, permission: domain.PermissionCorrectTransaction, ownerID: "user-2"},
+		{name: "support managing funds", role: domain.RoleSupport, permission: domain.PermissionManageFunds},
+		{name: "operations correcting a transaction", role: domain.RoleOperations, permission: domain.PermissionCorrectTransaction, ownerID: "user-2", allowed: true},
+		{name: "operations managing funds", role: domain.RoleOperations, permission: domain.PermissionManageFunds, allowed: true},
+		{name: "operations deleting a direct user", role: domain.RoleOperations, permission: domain.PermissionDeleteDirectUser, ownerID: "user-2"},
+		{name: "customer viewing the audit log", role: domain.RoleCustomer, permission: domain.PermissionViewAuditLog},
+		{name: "support viewing the audit log", role: domain.RoleSupport, permission: domain.PermissionViewAuditLog, allowed: true},
+		{name: "admin deleting a direct user", role: domain.RoleAdmin, permission: domain.PermissionDeleteDirectUser, ownerID: "user-2", allowed: true},
+		{name: "unknown role", role: "auditor", permission: domain.PermissionViewFunds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(contextAs(tt.role, "user-1"), tt.permission, tt.ownerID)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestRoleBased_Authorize_NoPrincipal(t *testing.T) {
+	authorizer := NewRoleBased(log.New(&bytes.Buffer{}, "", 0))
+
+	err := authorizer.Authorize(context.Background(), domain.PermissionViewFunds, "")
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
+
+func TestRoleBased_Authorize_RecordsDenials(t *testing.T) {
+	var logged bytes.Buffer
+	authorizer := NewRoleBased(log.New(&logged, "", 0))
+
+	require.NoError(t, authorizer.Authorize(contextAs(domain.RoleOperations, "ops@cushon.co.uk"), domain.PermissionManageFunds, ""))
+	assert.Empty(t, logged.String())
+
+	err := authorizer.Authorize(contextAs(domain.RoleSupport, "help@cushon.co.uk"), domain.PermissionCorrectTransaction, "user-2")
+	require.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Equal(t, "the support role may not correct or reverse transactions", err.Error())
+	assert.Equal(t, "Access denied: help@cushon.co.uk (support) asked for transaction:correct on account \"user-2\": the support role may not correct or reverse transactions\n", logged.String())
+
+	logged.Reset()
+	err = authorizer.Authorize(contextAs(domain.RoleSupport, "help@cushon.co.uk"), domain.PermissionManageFunds, "")
+	require.ErrorIs(t, err, domain.ErrForbidden)
+	assert.Equal(t, "Access denied: help@cushon.co.uk (support) asked for fund:manage: the support role may not manage funds\n", logged.String())
+}
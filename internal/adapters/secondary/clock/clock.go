@@ -0,0 +1,22 @@
+// Package clock implements the output.Clock port with the system clock.
+package clock
+
+import (
+	"time"
+
+	"cushon/internal/core/ports/output"
+)
+
+// System implements the output.Clock interface with the system clock
+type System struct{}
+
+// NewSystem creates a new system clock
+func NewSystem() output.Clock {
+	return System{}
+}
+
+// Now returns the current time in UTC to the microsecond, the precision the
+// database keeps, so a time reads back as it was written
+func (System) Now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// AuditLog implements the output.AuditLog interface in memory
+type AuditLog struct {
+	store *Store
+}
+
+// NewAuditLog creates a new in-memory audit log
+func NewAuditLog(store *Store) output.AuditLog {
+	return &AuditLog{
+		store: store,
+	}
+}
+
+// Record saves an entry
+func (l *AuditLog) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	unlock, err := l.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	appendEntry(l.store, &l.store.auditLog, *copyAuditEntry(entry))
+	return nil
+}
+
+// FindPage retrieves a page of the entries matching the query, oldest first
+func (l *AuditLog) FindPage(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	unlock, err := l.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var matching []*domain.AuditEntry
+	for i := range l.store.auditLog {
+		if entry := &l.store.auditLog[i]; query.Matches(entry) {
+			matching = append(matching, entry)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return auditBefore(auditCursorOf(matching[i]), auditCursorOf(matching[j]))
+	})
+
+	// Skip to the first entry past the cursor
+	start := 0
+	if query.After != nil {
+		start = sort.Search(len(matching), func(i int) bool {
+			return auditBefore(*query.After, auditCursorOf(matching[i]))
+		})
+	}
+
+	page := &domain.AuditPage{Entries: []*domain.AuditEntry{}}
+	end := min(start+query.Limit, len(matching))
+	for i := start; i < end; i++ {
+		page.Entries = append(page.Entries, copyAuditEntry(matching[i]))
+	}
+	if end < len(matching) {
+		next := auditCursorOf(matching[end-1])
+		page.Next = &next
+	}
+
+	return page, nil
+}
+
+// auditCursorOf returns the position of an entry in the audit log
+func auditCursorOf(entry *domain.AuditEntry) domain.AuditCursor {
+	return domain.AuditCursor{CreatedAt: entry.CreatedAt, ID: entry.ID}
+}
+
+// auditBefore reports whether position a comes before b, oldest first
+func auditBefore(a, b domain.AuditCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// copyAuditEntry returns a copy of an entry that shares no memory with it
+func copyAuditEntry(entry *domain.AuditEntry) *domain.AuditEntry {
+	copied := *entry
+	copied.Before = slices.Clone(entry.Before)
+	copied.After = slices.Clone(entry.After)
+	return &copied
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog_FindPage(t *testing.T) {
+	log := NewAuditLog(NewStore())
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	user := domain.NewDirectUser("John Doe", now)
+	other := domain.NewDirectUser("Jane Doe", now)
+
+	var recorded []*domain.AuditEntry
+	for i, subject := range []*domain.DirectUser{user, other, user, user} {
+		entry, err := domain.NewAuditEntry("admin-1", domain.AuditActionUpdate, subject, subject, "req-1", now.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, log.Record(context.Background(), entry))
+		recorded = append(recorded, entry)
+	}
+
+	query := domain.AuditQuery{EntityType: domain.AuditEntityDirectUser, EntityID: user.ID, Limit: 2}
+	page, err := log.FindPage(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, recorded[0].ID, page.Entries[0].ID)
+	assert.Equal(t, recorded[2].ID, page.Entries[1].ID)
+	assert.JSONEq(t, string(recorded[0].Before), string(page.Entries[0].Before))
+	require.NotNil(t, page.Next)
+
+	query.After = page.Next
+	page, err = log.FindPage(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, recorded[3].ID, page.Entries[0].ID)
+	assert.Nil(t, page.Next)
+
+	// The time range is half-open
+	page, err = log.FindPage(context.Background(), domain.AuditQuery{
+		EntityType: domain.AuditEntityDirectUser,
+		From:       now.Add(time.Minute),
+		To:         now.Add(3 * time.Minute),
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, recorded[1].ID, page.Entries[0].ID)
+	assert.Equal(t, recorded[2].ID, page.Entries[1].ID)
+}
+
+func TestAuditLog_RolledBackWithUnitOfWork(t *testing.T) {
+	store := NewStore()
+	log := NewAuditLog(store)
+	user := domain.NewDirectUser("John Doe", time.Now())
+
+	failure := errors.New("failed")
+	err := NewUnitOfWork(store).Do(context.Background(), func(ctx context.Context) error {
+		entry, err := domain.NewAuditEntry("admin-1", domain.AuditActionCreate, nil, user, "", time.Now())
+		if err != nil {
+			return err
+		}
+		if err := log.Record(ctx, entry); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	page, err := log.FindPage(context.Background(), domain.AuditQuery{EntityType: domain.AuditEntityDirectUser, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+	"cushon/internal/core/ports/output/outputtest"
+)
+
+func TestDirectUserRepository_Conformance(t *testing.T) {
+	outputtest.RunDirectUserRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.DirectUser) output.DirectUserRepository {
+		repo := NewDirectUserRepository(NewStore())
+		if existing != nil {
+			mustSave(t, repo.Save(context.Background(), existing))
+		}
+		return repo
+	})
+}
+
+func TestFundRepository_Conformance(t *testing.T) {
+	outputtest.RunFundRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.Fund) output.FundRepository {
+		repo := NewFundRepository(NewStore())
+		if existing != nil {
+			mustSave(t, repo.Save(context.Background(), existing))
+		}
+		return repo
+	})
+}
+
+func TestFundPriceRepository_Conformance(t *testing.T) {
+	outputtest.RunFundPriceRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.FundPrice) output.FundPriceRepository {
+		store := NewStore()
+		repo := NewFundPriceRepository(store)
+		if existing != nil {
+			fund := testFund()
+			fund.ID = existing.FundID
+			mustSave(t, NewFundRepository(store).Save(context.Background(), fund))
+			mustSave(t, repo.Save(context.Background(), existing))
+		}
+		return repo
+	})
+}
+
+func TestTransactionRepository_Conformance(t *testing.T) {
+	outputtest.RunTransactionRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.Transaction) output.TransactionRepository {
+		store := NewStore()
+		repo := NewTransactionRepository(store)
+		if existing != nil {
+			mustSave(t, NewDirectUserRepository(store).Save(context.Background(), &domain.DirectUser{ID: existing.UserID, Name: "John Doe"}))
+			fund := testFund()
+			fund.Name = existing.FundName
+			mustSave(t, NewFundRepository(store).Save(context.Background(), fund))
+			mustSave(t, repo.Save(context.Background(), existing))
+		}
+		return repo
+	})
+}
+
+func mustSave(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Failed to seed store: %v", err)
+	}
+}
+
+func testFund() *domain.Fund {
+	return domain.NewFund("Cushon Equities Fund", "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// DirectUserRepository implements the output.DirectUserRepository interface in memory
+type DirectUserRepository struct {
+	store *Store
+}
+
+// NewDirectUserRepository creates a new in-memory direct user repository
+func NewDirectUserRepository(store *Store) output.DirectUserRepository {
+	return &DirectUserRepository{
+		store: store,
+	}
+}
+
+// Save persists a direct user
+func (r *DirectUserRepository) Save(ctx context.Context, user *domain.DirectUser) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, exists := r.store.users[user.ID]; exists {
+		return domain.NewConflictError("direct user already exists")
+	}
+
+	setEntry(r.store, r.store.users, user.ID, *user)
+	return nil
+}
+
+// FindByID retrieves a direct user by ID, returning domain.ErrUserNotFound if there is none
+func (r *DirectUserRepository) FindByID(ctx context.Context, id string) (*domain.DirectUser, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	user, exists := r.store.users[id]
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return &user, nil
+}
+
+// Update renames an existing direct user, keeping the time it was created
+func (r *DirectUserRepository) Update(ctx context.Context, user *domain.DirectUser) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	stored, exists := r.store.users[user.ID]
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+
+	stored.Name = user.Name
+	stored.UpdatedAt = user.UpdatedAt
+	setEntry(r.store, r.store.users, user.ID, stored)
+	return nil
+}
+
+// Delete removes a direct user by ID. A user with transactions cannot be
+// removed, as the ledger keeps every transaction.
+func (r *DirectUserRepository) Delete(ctx context.Context, id string) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, exists := r.store.users[id]; !exists {
+		return domain.ErrUserNotFound
+	}
+
+	if r.store.hasTransactions(func(t *domain.Transaction) bool { return t.UserID == id }) {
+		return domain.NewConflictError("direct user has transactions")
+	}
+
+	deleteEntry(r.store, r.store.users, id)
+	return nil
+}
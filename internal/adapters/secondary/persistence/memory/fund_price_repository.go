@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// FundPriceRepository implements the output.FundPriceRepository interface in memory
+type FundPriceRepository struct {
+	store *Store
+}
+
+// NewFundPriceRepository creates a new in-memory fund price repository
+func NewFundPriceRepository(store *Store) output.FundPriceRepository {
+	return &FundPriceRepository{
+		store: store,
+	}
+}
+
+// Save persists a fund price. The fund must exist and have no other price at the same valuation point.
+func (r *FundPriceRepository) Save(ctx context.Context, price *domain.FundPrice) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, exists := r.store.funds[price.FundID]; !exists {
+		return domain.ErrFundNotFound
+	}
+
+	prices := r.store.prices[price.FundID]
+	for _, existing := range prices {
+		if existing.ValuationDate.Equal(price.ValuationDate) {
+			return domain.NewConflictError("fund is already priced at this valuation point")
+		}
+	}
+
+	// Sort a copy, leaving the stored prices as they were for a unit of work
+	// to put back
+	prices = append(slices.Clip(prices), *price)
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].ValuationDate.Before(prices[j].ValuationDate)
+	})
+	setEntry(r.store, r.store.prices, price.FundID, prices)
+
+	return nil
+}
+
+// FindByFundID retrieves every price for a fund ordered by valuation date
+func (r *FundPriceRepository) FindByFundID(ctx context.Context, fundID string) ([]*domain.FundPrice, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	prices := make([]*domain.FundPrice, 0, len(r.store.prices[fundID]))
+	for _, price := range r.store.prices[fundID] {
+		price := price
+		prices = append(prices, &price)
+	}
+
+	return prices, nil
+}
+
+// FindLatest retrieves the most recent price for a fund, or nil if it has never been priced
+func (r *FundPriceRepository) FindLatest(ctx context.Context, fundID string) (*domain.FundPrice, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	prices := r.store.prices[fundID]
+	if len(prices) == 0 {
+		return nil, nil
+	}
+
+	latest := prices[len(prices)-1]
+	return &latest, nil
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// FundRepository implements the output.FundRepository interface in memory
+type FundRepository struct {
+	store *Store
+}
+
+// NewFundRepository creates a new in-memory fund repository
+func NewFundRepository(store *Store) output.FundRepository {
+	return &FundRepository{
+		store: store,
+	}
+}
+
+// Save persists a fund. Fund IDs, names and ISINs are unique.
+func (r *FundRepository) Save(ctx context.Context, fund *domain.Fund) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, exists := r.store.funds[fund.ID]; exists {
+		return domain.ErrFundExists
+	}
+	for _, existing := range r.store.funds {
+		if existing.Name == fund.Name || existing.ISIN == fund.ISIN {
+			return domain.ErrFundExists
+		}
+	}
+
+	setEntry(r.store, r.store.funds, fund.ID, *fund)
+	return nil
+}
+
+// FindByID retrieves a fund by ID, returning domain.ErrFundNotFound if there is none
+func (r *FundRepository) FindByID(ctx context.Context, id string) (*domain.Fund, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fund, exists := r.store.funds[id]
+	if !exists {
+		return nil, domain.ErrFundNotFound
+	}
+
+	return &fund, nil
+}
+
+// FindByName retrieves a fund by its name, returning domain.ErrFundNotFound if there is none
+func (r *FundRepository) FindByName(ctx context.Context, name domain.FundName) (*domain.Fund, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fund, exists := r.store.fundByName(name)
+	if !exists {
+		return nil, domain.ErrFundNotFound
+	}
+
+	return &fund, nil
+}
+
+// FindAll retrieves every fund in the catalogue ordered by name
+func (r *FundRepository) FindAll(ctx context.Context) ([]*domain.Fund, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	funds := make([]*domain.Fund, 0, len(r.store.funds))
+	for _, fund := range r.store.funds {
+		fund := fund
+		funds = append(funds, &fund)
+	}
+
+	sort.Slice(funds, func(i, j int) bool {
+		return funds[i].Name < funds[j].Name
+	})
+
+	return funds, nil
+}
+
+// Update updates an existing fund. The ISIN must stay unique.
+func (r *FundRepository) Update(ctx context.Context, fund *domain.Fund) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, exists := r.store.funds[fund.ID]; !exists {
+		return domain.ErrFundNotFound
+	}
+	for id, existing := range r.store.funds {
+		if id != fund.ID && existing.ISIN == fund.ISIN {
+			return domain.ErrFundISINTaken
+		}
+	}
+
+	setEntry(r.store, r.store.funds, fund.ID, *fund)
+	return nil
+}
+
+// Delete removes a fund by ID. A fund with prices or transactions cannot be removed.
+func (r *FundRepository) Delete(ctx context.Context, id string) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fund, exists := r.store.funds[id]
+	if !exists {
+		return domain.ErrFundNotFound
+	}
+
+	if len(r.store.prices[id]) > 0 ||
+		r.store.hasTransactions(func(t *domain.Transaction) bool { return t.FundName == fund.Name }) {
+		return domain.NewConflictError("fund has prices or transactions")
+	}
+
+	deleteEntry(r.store, r.store.funds, id)
+	return nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// idempotencyKey identifies a record: keys are scoped to the principal that sent them
+type idempotencyKey struct {
+	principal string
+	key       string
+}
+
+// IdempotencyRepository implements the output.IdempotencyRepository interface in memory
+type IdempotencyRepository struct {
+	store *Store
+}
+
+// NewIdempotencyRepository creates a new in-memory idempotency repository
+func NewIdempotencyRepository(store *Store) output.IdempotencyRepository {
+	return &IdempotencyRepository{
+		store: store,
+	}
+}
+
+// Reserve saves record unless an unexpired record already holds its key, which is returned instead
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	id := idempotencyKey{principal: record.Principal, key: record.Key}
+	if existing, exists := r.store.idempotency[id]; exists && !existing.IsExpired(record.CreatedAt) {
+		return copyIdempotencyRecord(&existing), nil
+	}
+
+	r.store.idempotency[id] = *copyIdempotencyRecord(record)
+	return nil, nil
+}
+
+// Complete stores the response of a reserved request, provided the key still
+// holds its reservation
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	id := idempotencyKey{principal: record.Principal, key: record.Key}
+	if existing, exists := r.store.idempotency[id]; !exists || !existing.SameReservation(record) {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+
+	r.store.idempotency[id] = *copyIdempotencyRecord(record)
+	return nil
+}
+
+// Release deletes the reservation record made, if it still holds the key
+func (r *IdempotencyRepository) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	id := idempotencyKey{principal: record.Principal, key: record.Key}
+	if existing, exists := r.store.idempotency[id]; exists && existing.SameReservation(record) {
+		delete(r.store.idempotency, id)
+	}
+	return nil
+}
+
+// DeleteExpired deletes the records expired by now
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	var deleted int64
+	for id, record := range r.store.idempotency {
+		if record.IsExpired(now) {
+			delete(r.store.idempotency, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// copyIdempotencyRecord returns a copy of a record that shares no memory with it
+func copyIdempotencyRecord(record *domain.IdempotencyRecord) *domain.IdempotencyRecord {
+	copied := *record
+	copied.Body = slices.Clone(record.Body)
+	return &copied
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	repo := NewIdempotencyRepository(NewStore())
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	first := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, time.Hour)
+	existing, err := repo.Reserve(context.Background(), first)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// The same key from the same principal finds the first request
+	retry := domain.NewIdempotencyRecord("user-1", "key-1", "hash-2", now.Add(time.Minute), time.Hour)
+	existing, err = repo.Reserve(context.Background(), retry)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "hash-1", existing.RequestHash)
+	assert.False(t, existing.IsCompleted())
+
+	// Keys are scoped to the principal
+	other := domain.NewIdempotencyRecord("user-2", "key-1", "hash-3", now, time.Hour)
+	existing, err = repo.Reserve(context.Background(), other)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// Once expired the key can be reserved again
+	later := domain.NewIdempotencyRecord("user-1", "key-1", "hash-4", now.Add(time.Hour), time.Hour)
+	existing, err = repo.Reserve(context.Background(), later)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestIdempotencyRepository_CompleteAndRelease(t *testing.T) {
+	repo := NewIdempotencyRepository(NewStore())
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	record := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, time.Minute)
+	_, err := repo.Reserve(context.Background(), record)
+	require.NoError(t, err)
+
+	body := []byte(`{"id":"tx-1"}`)
+	record.Complete(201, "application/json; charset=utf-8", body, now, time.Hour)
+	require.NoError(t, repo.Complete(context.Background(), record))
+	body[0] = '['
+
+	// Completing the request keeps the key past its pending TTL
+	existing, err := repo.Reserve(context.Background(), domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now.Add(30*time.Minute), time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, `{"id":"tx-1"}`, string(existing.Body))
+
+	require.NoError(t, repo.Release(context.Background(), record))
+	require.NoError(t, repo.Release(context.Background(), record))
+	assert.ErrorIs(t, repo.Complete(context.Background(), record), domain.ErrIdempotencyKeyNotFound)
+}
+
+func TestIdempotencyRepository_LateRequestKeepsTheRetry(t *testing.T) {
+	repo := NewIdempotencyRepository(NewStore())
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	late := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, time.Minute)
+	_, err := repo.Reserve(context.Background(), late)
+	require.NoError(t, err)
+
+	// The first request outlives its reservation and a retry takes the key
+	retry := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now.Add(time.Minute), time.Minute)
+	existing, err := repo.Reserve(context.Background(), retry)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	// The first request finishing, or failing, leaves the retry's reservation alone
+	late.Complete(201, "application/json; charset=utf-8", []byte(`{"id":"tx-1"}`), now.Add(2*time.Minute), time.Hour)
+	assert.ErrorIs(t, repo.Complete(context.Background(), late), domain.ErrIdempotencyKeyNotFound)
+	require.NoError(t, repo.Release(context.Background(), late))
+
+	existing, err = repo.Reserve(context.Background(), domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now.Add(90*time.Second), time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.False(t, existing.IsCompleted())
+	assert.True(t, existing.SameReservation(retry))
+}
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	repo := NewIdempotencyRepository(NewStore())
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	for _, record := range []*domain.IdempotencyRecord{
+		domain.NewIdempotencyRecord("user-1", "old", "hash", now.Add(-2*time.Hour), time.Hour),
+		domain.NewIdempotencyRecord("user-1", "new", "hash", now, time.Hour),
+	} {
+		_, err := repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+	}
+
+	deleted, err := repo.DeleteExpired(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	existing, err := repo.Reserve(context.Background(), domain.NewIdempotencyRecord("user-1", "new", "hash", now, time.Hour))
+	require.NoError(t, err)
+	assert.NotNil(t, existing)
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// outboxEvent is an event in the outbox with what has become of it
+type outboxEvent struct {
+	event          domain.Event
+	publishedAt    *time.Time
+	deadLetteredAt *time.Time
+	attempts       int
+	lastError      string
+	// nextAttemptAt and claimedUntil hold the event back, after a failure
+	// and while a relay has it
+	nextAttemptAt time.Time
+	claimedUntil  time.Time
+}
+
+// Outbox implements the output.Outbox interface in memory
+type Outbox struct {
+	store *Store
+}
+
+// NewOutbox creates a new in-memory outbox
+func NewOutbox(store *Store) output.Outbox {
+	return &Outbox{
+		store: store,
+	}
+}
+
+// Add stores an event to publish
+func (o *Outbox) Add(ctx context.Context, event *domain.Event) error {
+	unlock, err := o.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	appendEntry(o.store, &o.store.outbox, outboxEvent{event: *copyEvent(event)})
+	return nil
+}
+
+// Claim takes up to limit events due to be published at now, in the order
+// they were added, holding them until the given time. An event waiting behind
+// an earlier one of its aggregate is not offered.
+func (o *Outbox) Claim(ctx context.Context, now time.Time, until time.Time, limit int) ([]*domain.PendingEvent, error) {
+	unlock, err := o.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	events := []*domain.PendingEvent{}
+	waiting := make(map[string]bool)
+	for i := range o.store.outbox {
+		stored := o.store.outbox[i]
+		if stored.publishedAt != nil || stored.deadLetteredAt != nil || waiting[stored.event.AggregateID] {
+			continue
+		}
+		waiting[stored.event.AggregateID] = true
+		if len(events) == limit || stored.nextAttemptAt.After(now) || stored.claimedUntil.After(now) {
+			continue
+		}
+
+		stored.claimedUntil = until
+		replaceEntry(o.store, &o.store.outbox, i, stored)
+		events = append(events, &domain.PendingEvent{Event: copyEvent(&stored.event), Attempts: stored.attempts})
+	}
+
+	return events, nil
+}
+
+// MarkPublished records that an event was published at the given time
+func (o *Outbox) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return o.update(ctx, id, func(stored *outboxEvent) {
+		stored.publishedAt = &publishedAt
+	})
+}
+
+// RecordFailure counts a failed attempt to publish an event, keeping the
+// reason, and releases it to be claimed again from retryAt
+func (o *Outbox) RecordFailure(ctx context.Context, id string, reason string, retryAt time.Time) error {
+	return o.update(ctx, id, func(stored *outboxEvent) {
+		stored.attempts++
+		stored.lastError = reason
+		stored.nextAttemptAt = retryAt
+		stored.claimedUntil = time.Time{}
+	})
+}
+
+// DeadLetter counts a failed attempt to publish an event, keeping the reason,
+// and gives up on it
+func (o *Outbox) DeadLetter(ctx context.Context, id string, reason string, at time.Time) error {
+	return o.update(ctx, id, func(stored *outboxEvent) {
+		stored.attempts++
+		stored.lastError = reason
+		stored.deadLetteredAt = &at
+		stored.claimedUntil = time.Time{}
+	})
+}
+
+// update applies change to a copy of the stored event and stores the copy,
+// so a unit of work can put the original back
+func (o *Outbox) update(ctx context.Context, id string, change func(*outboxEvent)) error {
+	unlock, err := o.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for i := range o.store.outbox {
+		if o.store.outbox[i].event.ID == id {
+			updated := o.store.outbox[i]
+			change(&updated)
+			replaceEntry(o.store, &o.store.outbox, i, updated)
+			return nil
+		}
+	}
+	return domain.ErrEventNotFound
+}
+
+// copyEvent returns a copy of an event that shares no memory with it
+func copyEvent(event *domain.Event) *domain.Event {
+	copied := *event
+	copied.Payload = slices.Clone(event.Payload)
+	return &copied
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(t *testing.T, now time.Time) *domain.Event {
+	t.Helper()
+	event, err := domain.NewDirectUserCreatedEvent(domain.NewDirectUser("John Doe", now), now)
+	require.NoError(t, err)
+	return event
+}
+
+func TestOutbox_ClaimsInOrder(t *testing.T) {
+	store := NewStore()
+	outbox := NewOutbox(store)
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	until := now.Add(time.Minute)
+
+	var added []*domain.Event
+	for i := 0; i < 3; i++ {
+		event := newTestEvent(t, now.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, outbox.Add(context.Background(), event))
+		added = append(added, event)
+	}
+
+	events, err := outbox.Claim(context.Background(), now, until, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, added[0].ID, events[0].Event.ID)
+	assert.JSONEq(t, string(added[0].Payload), string(events[0].Event.Payload))
+	assert.Zero(t, events[0].Attempts)
+
+	// Claimed events are not offered again until the claim runs out
+	events, err = outbox.Claim(context.Background(), now, until, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, added[2].ID, events[0].Event.ID)
+
+	// A failed event waits until it is due, with the attempt counted
+	require.NoError(t, outbox.RecordFailure(context.Background(), added[0].ID, "webhook unavailable", now.Add(time.Hour)))
+	require.NoError(t, outbox.MarkPublished(context.Background(), added[1].ID, now))
+	assert.Equal(t, 1, store.outbox[0].attempts)
+	assert.Equal(t, "webhook unavailable", store.outbox[0].lastError)
+
+	events, err = outbox.Claim(context.Background(), until, until.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, added[2].ID, events[0].Event.ID)
+
+	events, err = outbox.Claim(context.Background(), now.Add(time.Hour), now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, added[0].ID, events[0].Event.ID)
+	assert.Equal(t, 1, events[0].Attempts)
+
+	assert.ErrorIs(t, outbox.MarkPublished(context.Background(), "missing", now), domain.ErrEventNotFound)
+}
+
+func TestOutbox_ClaimHoldsBackAnAggregate(t *testing.T) {
+	store := NewStore()
+	outbox := NewOutbox(store)
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	user := domain.NewDirectUser("John Doe", now)
+	created, err := domain.NewDirectUserCreatedEvent(user, now)
+	require.NoError(t, err)
+	deleted, err := domain.NewDirectUserDeletedEvent(user, now.Add(time.Minute))
+	require.NoError(t, err)
+	other := newTestEvent(t, now.Add(2*time.Minute))
+	for _, event := range []*domain.Event{created, deleted, other} {
+		require.NoError(t, outbox.Add(context.Background(), event))
+	}
+
+	// The user's deletion waits behind its creation
+	events, err := outbox.Claim(context.Background(), now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, created.ID, events[0].Event.ID)
+	assert.Equal(t, other.ID, events[1].Event.ID)
+
+	// Giving up on the creation lets the deletion through
+	require.NoError(t, outbox.DeadLetter(context.Background(), created.ID, "rejected", now))
+	assert.NotNil(t, store.outbox[0].deadLetteredAt)
+	assert.Equal(t, 1, store.outbox[0].attempts)
+
+	events, err = outbox.Claim(context.Background(), now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, deleted.ID, events[0].Event.ID)
+}
+
+func TestOutbox_RolledBackWithUnitOfWork(t *testing.T) {
+	store := NewStore()
+	outbox := NewOutbox(store)
+
+	failure := errors.New("failed")
+	err := NewUnitOfWork(store).Do(context.Background(), func(ctx context.Context) error {
+		if err := outbox.Add(ctx, newTestEvent(t, time.Now())); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	events, err := outbox.Claim(context.Background(), time.Now(), time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
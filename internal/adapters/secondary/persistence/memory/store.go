@@ -0,0 +1,105 @@
+// Package memory implements the output ports in process memory. It keeps the
+// same rules the MySQL schema enforces, so the API behaves the same without a
+// database, but nothing survives a restart.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"cushon/internal/core/domain"
+)
+
+// Store holds the data shared by the in-memory repositories. The repositories
+// check references to each other's entities, as the MySQL foreign keys do, so
+// they must share a store. It is safe for concurrent use.
+type Store struct {
+	mu           sync.RWMutex
+	users        map[string]domain.DirectUser
+	funds        map[string]domain.Fund
+	prices       map[string][]domain.FundPrice
+	transactions []domain.Transaction
+	// byID indexes transactions by ID into the transactions slice
+	byID map[string]int
+	// reversals maps the ID of each reversed transaction to the ID of its reversal
+	reversals map[string]string
+	// auditLog holds the audit entries in the order they were recorded
+	auditLog []domain.AuditEntry
+	// outbox holds the events to publish in the order they were added
+	outbox []outboxEvent
+	// idempotency holds the requests made with idempotency keys
+	idempotency map[idempotencyKey]domain.IdempotencyRecord
+	// undo holds the steps reversing the changes made by the running unit of
+	// work, oldest first. Changes are only recorded while recording is set.
+	undo      []func()
+	recording bool
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{
+		users:       make(map[string]domain.DirectUser),
+		funds:       make(map[string]domain.Fund),
+		prices:      make(map[string][]domain.FundPrice),
+		byID:        make(map[string]int),
+		reversals:   make(map[string]string),
+		idempotency: make(map[idempotencyKey]domain.IdempotencyRecord),
+	}
+}
+
+// lock takes the write lock unless ctx is already done, so a cancelled
+// request changes nothing. Inside a unit of work on the store the unit already
+// holds the lock, so none is taken and unlock does nothing.
+func (s *Store) lock(ctx context.Context) (unlock func(), err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.inUnit(ctx) {
+		return func() {}, nil
+	}
+	s.mu.Lock()
+	return s.mu.Unlock, nil
+}
+
+// rlock takes the read lock unless ctx is already done or inside a unit of
+// work on the store
+func (s *Store) rlock(ctx context.Context) (unlock func(), err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.inUnit(ctx) {
+		return func() {}, nil
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock, nil
+}
+
+// fundByName returns the fund with the given name. The caller must hold the lock.
+func (s *Store) fundByName(name domain.FundName) (domain.Fund, bool) {
+	for _, fund := range s.funds {
+		if fund.Name == name {
+			return fund, true
+		}
+	}
+	return domain.Fund{}, false
+}
+
+// hasTransactions reports whether any transaction matches. The caller must hold the lock.
+func (s *Store) hasTransactions(match func(*domain.Transaction) bool) bool {
+	for i := range s.transactions {
+		if match(&s.transactions[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyTransaction returns a copy of a transaction that shares no memory with it
+func copyTransaction(transaction *domain.Transaction) *domain.Transaction {
+	copied := *transaction
+	if transaction.ValuationDate != nil {
+		valuationDate := *transaction.ValuationDate
+		copied.ValuationDate = &valuationDate
+	}
+	return &copied
+}
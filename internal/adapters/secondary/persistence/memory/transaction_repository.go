@@ -0,0 +1,369 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// TransactionRepository implements the output.TransactionRepository interface
+// in memory. Transactions are only ever appended; the one change it makes
+// records the units allocated to a pending transaction.
+type TransactionRepository struct {
+	store *Store
+}
+
+// NewTransactionRepository creates a new in-memory transaction repository
+func NewTransactionRepository(store *Store) output.TransactionRepository {
+	return &TransactionRepository{
+		store: store,
+	}
+}
+
+// Save persists a transaction
+func (r *TransactionRepository) Save(ctx context.Context, transaction *domain.Transaction) error {
+	return r.saveChecked(ctx, transaction)
+}
+
+// SaveDebit persists a debit transaction only if the user's balance in the
+// fund covers it. The check and insert happen under the store's lock.
+func (r *TransactionRepository) SaveDebit(ctx context.Context, transaction *domain.Transaction) error {
+	return r.saveChecked(ctx, transaction)
+}
+
+// SaveDeposit persists a deposit only if it keeps the user's deposits in its
+// tax year within limit. The check and insert happen under the store's lock.
+func (r *TransactionRepository) SaveDeposit(ctx context.Context, transaction *domain.Transaction, limit decimal.Decimal) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if transaction.ID == "" {
+		transaction.ID = uuid.New().String()
+	}
+	if err := r.check(transaction, nil); err != nil {
+		return err
+	}
+
+	taxYear := domain.TaxYearFor(transaction.CreatedAt)
+	used := r.sumDeposits(transaction.UserID, taxYear.Start(), taxYear.End())
+	if used.Add(transaction.Amount).GreaterThan(limit) {
+		return &domain.AllowanceExceededError{
+			TaxYear:   taxYear,
+			Limit:     limit,
+			Used:      used,
+			Requested: transaction.Amount,
+		}
+	}
+
+	r.append(transaction)
+	return nil
+}
+
+// SaveReversal persists a reversal and, if not nil, the entry correcting the
+// reversed transaction, checking the balance for each debit. Either both are
+// saved or neither is.
+func (r *TransactionRepository) SaveReversal(ctx context.Context, reversal, correction *domain.Transaction) error {
+	if correction == nil {
+		return r.saveChecked(ctx, reversal)
+	}
+	return r.saveChecked(ctx, reversal, correction)
+}
+
+// saveChecked appends transactions in order. Every transaction is checked
+// before any is appended, and each debit must be covered by the balance left
+// by the entries before it.
+func (r *TransactionRepository) saveChecked(ctx context.Context, transactions ...*domain.Transaction) error {
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for i, transaction := range transactions {
+		if transaction.ID == "" {
+			transaction.ID = uuid.New().String()
+		}
+		if err := r.check(transaction, transactions[:i]); err != nil {
+			return err
+		}
+	}
+
+	for _, transaction := range transactions {
+		r.append(transaction)
+	}
+
+	return nil
+}
+
+// append adds a checked transaction to the store. The caller must hold the lock.
+func (r *TransactionRepository) append(transaction *domain.Transaction) {
+	setEntry(r.store, r.store.byID, transaction.ID, len(r.store.transactions))
+	appendEntry(r.store, &r.store.transactions, *copyTransaction(transaction))
+	if transaction.IsReversal() {
+		setEntry(r.store, r.store.reversals, transaction.ReversalOf, transaction.ID)
+	}
+}
+
+// check applies the rules the MySQL schema enforces to a transaction about to
+// be saved after the given, not yet saved, entries. The caller must hold the lock.
+func (r *TransactionRepository) check(transaction *domain.Transaction, before []*domain.Transaction) error {
+	if _, exists := r.store.users[transaction.UserID]; !exists {
+		return domain.ErrUserNotFound
+	}
+	if _, exists := r.store.fundByName(transaction.FundName); !exists {
+		return domain.ErrFundNotFound
+	}
+	if _, exists := r.store.byID[transaction.ID]; exists {
+		return domain.NewConflictError("transaction already exists")
+	}
+
+	if transaction.IsReversal() {
+		if _, exists := r.store.byID[transaction.ReversalOf]; !exists {
+			return domain.ErrTransactionNotFound
+		}
+		if _, reversed := r.store.reversals[transaction.ReversalOf]; reversed {
+			return domain.ErrAlreadyReversed
+		}
+	}
+
+	if transaction.IsDebit() {
+		available := r.balance(transaction.UserID, transaction.FundName)
+		for _, earlier := range before {
+			if earlier.UserID == transaction.UserID && earlier.FundName == transaction.FundName {
+				available = available.Add(earlier.SignedAmount())
+			}
+		}
+
+		if transaction.Amount.GreaterThan(available) {
+			return &domain.InsufficientBalanceError{
+				FundName:  transaction.FundName,
+				Available: available,
+				Requested: transaction.Amount,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Balance returns a user's available balance in a fund: credits less debits
+func (r *TransactionRepository) Balance(ctx context.Context, userID string, fundName domain.FundName) (decimal.Decimal, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer unlock()
+
+	return r.balance(userID, fundName), nil
+}
+
+// balance sums a user's signed amounts in a fund. The caller must hold the lock.
+func (r *TransactionRepository) balance(userID string, fundName domain.FundName) decimal.Decimal {
+	total := decimal.Zero
+	for i := range r.store.transactions {
+		transaction := &r.store.transactions[i]
+		if transaction.UserID == userID && transaction.FundName == fundName {
+			total = total.Add(transaction.SignedAmount())
+		}
+	}
+	return total
+}
+
+// FindByID retrieves a transaction by its ID, returning domain.ErrTransactionNotFound if there is none
+func (r *TransactionRepository) FindByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, exists := r.store.byID[id]
+	if !exists {
+		return nil, domain.ErrTransactionNotFound
+	}
+
+	return copyTransaction(&r.store.transactions[index]), nil
+}
+
+// FindByUserID retrieves all transactions for a user, most recent first
+func (r *TransactionRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.Transaction, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var transactions []*domain.Transaction
+	for i := len(r.store.transactions) - 1; i >= 0; i-- {
+		if transaction := &r.store.transactions[i]; transaction.UserID == userID {
+			transactions = append(transactions, copyTransaction(transaction))
+		}
+	}
+
+	return transactions, nil
+}
+
+// FindPage retrieves the page of a user's transactions the query selects
+func (r *TransactionRepository) FindPage(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var matching []*domain.Transaction
+	for i := range r.store.transactions {
+		if transaction := &r.store.transactions[i]; query.Matches(transaction) {
+			matching = append(matching, transaction)
+		}
+	}
+
+	newestFirst := query.Sort == domain.SortNewestFirst
+	sort.Slice(matching, func(i, j int) bool {
+		if newestFirst {
+			return before(cursorOf(matching[j]), cursorOf(matching[i]))
+		}
+		return before(cursorOf(matching[i]), cursorOf(matching[j]))
+	})
+
+	// Skip to the first transaction past the cursor
+	start := 0
+	if query.After != nil {
+		start = sort.Search(len(matching), func(i int) bool {
+			if newestFirst {
+				return before(cursorOf(matching[i]), *query.After)
+			}
+			return before(*query.After, cursorOf(matching[i]))
+		})
+	}
+
+	page := &domain.TransactionPage{Transactions: []*domain.Transaction{}, Total: len(matching)}
+	end := min(start+query.Limit, len(matching))
+	for i := start; i < end; i++ {
+		page.Transactions = append(page.Transactions, copyTransaction(matching[i]))
+	}
+	if end < len(matching) {
+		next := cursorOf(matching[end-1])
+		page.Next = &next
+	}
+
+	return page, nil
+}
+
+// cursorOf returns the position of a transaction in a history
+func cursorOf(transaction *domain.Transaction) domain.TransactionCursor {
+	return domain.TransactionCursor{CreatedAt: transaction.CreatedAt, ID: transaction.ID}
+}
+
+// before reports whether position a comes before b, oldest first
+func before(a, b domain.TransactionCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// FindReversal retrieves the entry reversing a transaction, nil if it has not been reversed
+func (r *TransactionRepository) FindReversal(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	reversalID, reversed := r.store.reversals[transactionID]
+	if !reversed {
+		return nil, nil
+	}
+
+	return copyTransaction(&r.store.transactions[r.store.byID[reversalID]]), nil
+}
+
+// FindUnpriced retrieves pending transactions in a fund placed before the given valuation point
+func (r *TransactionRepository) FindUnpriced(ctx context.Context, fundName domain.FundName, placedBefore time.Time) ([]*domain.Transaction, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var transactions []*domain.Transaction
+	for i := range r.store.transactions {
+		transaction := &r.store.transactions[i]
+		if transaction.FundName == fundName && !transaction.IsPriced() && transaction.CreatedAt.Before(placedBefore) {
+			transactions = append(transactions, copyTransaction(transaction))
+		}
+	}
+
+	return transactions, nil
+}
+
+// AllocateUnits records the units, unit price and valuation date of a pending transaction
+func (r *TransactionRepository) AllocateUnits(ctx context.Context, transaction *domain.Transaction) error {
+	if !transaction.IsPriced() {
+		return errors.New("transaction has not been priced")
+	}
+
+	unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Only a pending transaction may be priced, so an allocation is never overwritten
+	index, exists := r.store.byID[transaction.ID]
+	if !exists || r.store.transactions[index].IsPriced() {
+		return domain.NewConflictError("transaction not found or already priced")
+	}
+
+	valuationDate := *transaction.ValuationDate
+	stored := &r.store.transactions[index]
+	stored.Units = transaction.Units
+	stored.UnitPrice = transaction.UnitPrice
+	stored.ValuationDate = &valuationDate
+	stored.UpdatedAt = transaction.UpdatedAt
+
+	return nil
+}
+
+// SumDeposits totals a user's deposits made in the half-open interval [from, to),
+// leaving out deposits that have been reversed
+func (r *TransactionRepository) SumDeposits(ctx context.Context, userID string, from, to time.Time) (decimal.Decimal, error) {
+	unlock, err := r.store.rlock(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer unlock()
+
+	return r.sumDeposits(userID, from, to), nil
+}
+
+// sumDeposits totals a user's unreversed deposits in [from, to). The caller must hold the lock.
+func (r *TransactionRepository) sumDeposits(userID string, from, to time.Time) decimal.Decimal {
+	total := decimal.Zero
+	for i := range r.store.transactions {
+		transaction := &r.store.transactions[i]
+		if transaction.UserID != userID || transaction.Type != domain.TransactionTypeDeposit || transaction.IsReversal() {
+			continue
+		}
+		if transaction.CreatedAt.Before(from) || !transaction.CreatedAt.Before(to) {
+			continue
+		}
+		if _, reversed := r.store.reversals[transaction.ID]; reversed {
+			continue
+		}
+		total = total.Add(transaction.Amount)
+	}
+
+	return total
+}
@@ -0,0 +1,323 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFundName domain.FundName = "Cushon Equities Fund"
+
+// setupTransactionStore returns a transaction repository over a store holding a user and a fund
+func setupTransactionStore(t *testing.T) (*TransactionRepository, *domain.DirectUser) {
+	store := NewStore()
+	user := domain.NewDirectUser("John Doe", time.Now())
+	require.NoError(t, NewDirectUserRepository(store).Save(context.Background(), user))
+	require.NoError(t, NewFundRepository(store).Save(context.Background(), testFund()))
+
+	return NewTransactionRepository(store).(*TransactionRepository), user
+}
+
+func deposit(userID string, amount int64) *domain.Transaction {
+	return domain.NewTransaction(userID, domain.TransactionTypeDeposit, decimal.NewFromInt(amount), testFundName, time.Now())
+}
+
+func withdrawal(userID string, amount int64) *domain.Transaction {
+	return domain.NewTransaction(userID, domain.TransactionTypeWithdrawal, decimal.NewFromInt(amount), testFundName, time.Now())
+}
+
+func TestTransactionRepository_Save_UnknownUser(t *testing.T) {
+	repo, _ := setupTransactionStore(t)
+
+	err := repo.Save(context.Background(), deposit("unknown-user", 100))
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestTransactionRepository_Save_CancelledContext(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.Save(ctx, deposit(user.ID, 100))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// A cancelled request records nothing
+	transactions, err := repo.FindByUserID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+}
+
+func TestTransactionRepository_SaveDebit(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	require.NoError(t, repo.Save(context.Background(), deposit(user.ID, 100)))
+
+	require.NoError(t, repo.SaveDebit(context.Background(), withdrawal(user.ID, 60)))
+
+	var insufficient *domain.InsufficientBalanceError
+	err := repo.SaveDebit(context.Background(), withdrawal(user.ID, 50))
+	require.ErrorAs(t, err, &insufficient)
+	assert.True(t, decimal.NewFromInt(40).Equal(insufficient.Available))
+
+	balance, err := repo.Balance(context.Background(), user.ID, testFundName)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(40).Equal(balance))
+}
+
+func TestTransactionRepository_SaveDebit_Concurrent(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	require.NoError(t, repo.Save(context.Background(), deposit(user.ID, 100)))
+
+	// Only ten of the withdrawals fit in the balance, however they interleave
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	saved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if repo.SaveDebit(context.Background(), withdrawal(user.ID, 10)) == nil {
+				mu.Lock()
+				saved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, saved)
+	balance, err := repo.Balance(context.Background(), user.ID, testFundName)
+	require.NoError(t, err)
+	assert.True(t, balance.IsZero())
+}
+
+func TestTransactionRepository_SaveDeposit(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	limit := decimal.NewFromInt(100)
+
+	require.NoError(t, repo.SaveDeposit(context.Background(), deposit(user.ID, 60), limit))
+
+	var exceeded *domain.AllowanceExceededError
+	err := repo.SaveDeposit(context.Background(), deposit(user.ID, 50), limit)
+	require.ErrorAs(t, err, &exceeded)
+	assert.True(t, decimal.NewFromInt(60).Equal(exceeded.Used))
+	assert.True(t, limit.Equal(exceeded.Limit))
+
+	// Depositing up to the limit is allowed
+	require.NoError(t, repo.SaveDeposit(context.Background(), deposit(user.ID, 40), limit))
+}
+
+func TestTransactionRepository_SaveDeposit_Concurrent(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+
+	// Only ten of the deposits fit in the allowance, however they interleave
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	saved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if repo.SaveDeposit(context.Background(), deposit(user.ID, 10), decimal.NewFromInt(100)) == nil {
+				mu.Lock()
+				saved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, saved)
+	balance, err := repo.Balance(context.Background(), user.ID, testFundName)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(balance))
+}
+
+func TestTransactionRepository_SaveReversal(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	original := deposit(user.ID, 100)
+	require.NoError(t, repo.Save(context.Background(), original))
+
+	reversal, err := original.Reverse("Amount keyed incorrectly", "admin", time.Now())
+	require.NoError(t, err)
+	correction := deposit(user.ID, 150)
+
+	require.NoError(t, repo.SaveReversal(context.Background(), reversal, correction))
+
+	found, err := repo.FindReversal(context.Background(), original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reversal.ID, found.ID)
+
+	balance, err := repo.Balance(context.Background(), user.ID, testFundName)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(150).Equal(balance))
+
+	again, err := original.Reverse("Reversed twice", "admin", time.Now())
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.SaveReversal(context.Background(), again, nil), domain.ErrAlreadyReversed)
+}
+
+func TestTransactionRepository_SaveReversal_InsufficientBalance(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	original := deposit(user.ID, 100)
+	require.NoError(t, repo.Save(context.Background(), original))
+	require.NoError(t, repo.SaveDebit(context.Background(), withdrawal(user.ID, 80)))
+
+	reversal, err := original.Reverse("Deposit bounced", "admin", time.Now())
+	require.NoError(t, err)
+
+	var insufficient *domain.InsufficientBalanceError
+	assert.ErrorAs(t, repo.SaveReversal(context.Background(), reversal, nil), &insufficient)
+
+	// Nothing is saved when the reversal is refused
+	found, err := repo.FindReversal(context.Background(), original.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestTransactionRepository_FindByUserID(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	first := deposit(user.ID, 100)
+	second := deposit(user.ID, 200)
+	require.NoError(t, repo.Save(context.Background(), first))
+	require.NoError(t, repo.Save(context.Background(), second))
+
+	transactions, err := repo.FindByUserID(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, second.ID, transactions[0].ID)
+	assert.Equal(t, first.ID, transactions[1].ID)
+
+	// Returned transactions are copies, changing them leaves the store untouched
+	transactions[0].Amount = decimal.NewFromInt(1)
+	found, err := repo.FindByID(context.Background(), second.ID)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(200).Equal(found.Amount))
+}
+
+func TestTransactionRepository_AllocateUnits(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	transaction := deposit(user.ID, 100)
+	require.NoError(t, repo.Save(context.Background(), transaction))
+
+	pending, err := repo.FindUnpriced(context.Background(), testFundName, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	price := domain.NewNAVFundPrice("fund-id", time.Now(), decimal.NewFromInt(2))
+	require.NoError(t, pending[0].Allocate(price, time.Now()))
+	require.NoError(t, repo.AllocateUnits(context.Background(), pending[0]))
+
+	found, err := repo.FindByID(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(50).Equal(found.Units))
+
+	// An allocation is never overwritten
+	assert.ErrorIs(t, repo.AllocateUnits(context.Background(), pending[0]), domain.ErrConflict)
+}
+
+func TestTransactionRepository_SumDeposits(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	from := time.Now().Add(-time.Hour)
+	kept := deposit(user.ID, 100)
+	reversed := deposit(user.ID, 200)
+	require.NoError(t, repo.Save(context.Background(), kept))
+	require.NoError(t, repo.Save(context.Background(), reversed))
+
+	reversal, err := reversed.Reverse("Duplicate deposit", "admin", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveReversal(context.Background(), reversal, nil))
+
+	total, err := repo.SumDeposits(context.Background(), user.ID, from, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(100).Equal(total))
+}
+
+func TestTransactionRepository_FindPage(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	placed := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	var saved []*domain.Transaction
+	for i, minutes := range []int{0, 1, 1, 1, 2} {
+		// Transactions placed at the same time are ordered by ID
+		transaction := deposit(user.ID, int64((i+1)*100))
+		transaction.CreatedAt = placed.Add(time.Duration(minutes) * time.Minute)
+		require.NoError(t, repo.Save(context.Background(), transaction))
+		saved = append(saved, transaction)
+	}
+	require.NoError(t, repo.SaveDebit(context.Background(), withdrawal(user.ID, 50)))
+	slices.SortFunc(saved, func(a, b *domain.Transaction) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+
+	for _, order := range []domain.SortOrder{domain.SortOldestFirst, domain.SortNewestFirst} {
+		t.Run(string(order), func(t *testing.T) {
+			// Walking the pages visits every deposit once, in order
+			query := domain.TransactionQuery{UserID: user.ID, Type: domain.TransactionTypeDeposit, Sort: order, Limit: 2}
+			var visited []string
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 3, "expected 3 pages")
+				page, err := repo.FindPage(context.Background(), query)
+				require.NoError(t, err)
+				assert.Equal(t, 5, page.Total)
+				for _, transaction := range page.Transactions {
+					visited = append(visited, transaction.ID)
+				}
+				if page.Next == nil {
+					break
+				}
+				query.After = page.Next
+			}
+
+			var expected []string
+			for _, transaction := range saved {
+				expected = append(expected, transaction.ID)
+			}
+			if order == domain.SortNewestFirst {
+				slices.Reverse(expected)
+			}
+			assert.Equal(t, expected, visited)
+		})
+	}
+}
+
+func TestTransactionRepository_FindPage_Filters(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	from := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	earlier := deposit(user.ID, 100)
+	earlier.CreatedAt = from.Add(-time.Second)
+	require.NoError(t, repo.Save(context.Background(), earlier))
+	later := deposit(user.ID, 200)
+	later.CreatedAt = from
+	require.NoError(t, repo.Save(context.Background(), later))
+
+	page, err := repo.FindPage(context.Background(), domain.TransactionQuery{
+		UserID: user.ID,
+		From:   from,
+		Sort:   domain.SortNewestFirst,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, later.ID, page.Transactions[0].ID)
+	assert.Equal(t, 1, page.Total)
+	assert.Nil(t, page.Next)
+
+	page, err = repo.FindPage(context.Background(), domain.TransactionQuery{
+		UserID:   user.ID,
+		FundName: "Another Fund",
+		Sort:     domain.SortNewestFirst,
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, page.Transactions)
+	assert.Zero(t, page.Total)
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+
+	"cushon/internal/core/ports/output"
+)
+
+// unitKey is the context key for the store a unit of work is running on
+type unitKey struct{}
+
+// UnitOfWork implements the output.UnitOfWork interface in memory. A unit of
+// work holds the store's write lock until it finishes, so units never conflict
+// and are never retried.
+type UnitOfWork struct {
+	store *Store
+}
+
+// NewUnitOfWork creates a new in-memory unit of work
+func NewUnitOfWork(store *Store) output.UnitOfWork {
+	return &UnitOfWork{
+		store: store,
+	}
+}
+
+// Do runs fn holding the store's lock, undoing the changes fn made if it fails
+// or panics. Only the changes are recorded, so a unit of work costs nothing
+// for the data it leaves alone. Repository calls must use the context passed
+// to fn: one made with any other context waits for the lock fn holds.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if u.store.inUnit(ctx) {
+		return fn(ctx)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	u.store.recording = true
+	defer func() {
+		recovered := recover()
+		if recovered != nil || err != nil {
+			u.store.rollback()
+		}
+		u.store.undo = nil
+		u.store.recording = false
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	return fn(context.WithValue(ctx, unitKey{}, u.store))
+}
+
+// inUnit reports whether ctx is inside a unit of work on the store
+func (s *Store) inUnit(ctx context.Context) bool {
+	store, ok := ctx.Value(unitKey{}).(*Store)
+	return ok && store == s
+}
+
+// onUndo records a step reversing a change about to be made, run if the unit
+// of work making it fails. Outside a unit of work nothing is recorded. The
+// caller must hold the write lock.
+func (s *Store) onUndo(step func()) {
+	if s.recording {
+		s.undo = append(s.undo, step)
+	}
+}
+
+// rollback reverses the changes made by the running unit of work, newest
+// first. The caller must hold the write lock.
+func (s *Store) rollback() {
+	for i := len(s.undo) - 1; i >= 0; i-- {
+		s.undo[i]()
+	}
+}
+
+// setEntry sets the value stored under key, recording how to undo it
+func setEntry[K comparable, V any](s *Store, entries map[K]V, key K, value V) {
+	previous, existed := entries[key]
+	s.onUndo(func() {
+		if existed {
+			entries[key] = previous
+		} else {
+			delete(entries, key)
+		}
+	})
+	entries[key] = value
+}
+
+// deleteEntry deletes the value stored under key, recording how to undo it
+func deleteEntry[K comparable, V any](s *Store, entries map[K]V, key K) {
+	previous, existed := entries[key]
+	if !existed {
+		return
+	}
+	s.onUndo(func() {
+		entries[key] = previous
+	})
+	delete(entries, key)
+}
+
+// appendEntry appends a value to a list in the store, recording how to undo it
+func appendEntry[T any](s *Store, list *[]T, value T) {
+	length := len(*list)
+	s.onUndo(func() {
+		clear((*list)[length:])
+		*list = (*list)[:length]
+	})
+	*list = append(*list, value)
+}
+
+// replaceEntry replaces the value at index i of a list in the store,
+// recording how to undo it
+func replaceEntry[T any](s *Store, list *[]T, i int, value T) {
+	previous := (*list)[i]
+	s.onUndo(func() {
+		(*list)[i] = previous
+	})
+	(*list)[i] = value
+}
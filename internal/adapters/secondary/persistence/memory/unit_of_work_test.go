@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	unitOfWork := NewUnitOfWork(repo.store)
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		if err := repo.Save(ctx, deposit(user.ID, 100)); err != nil {
+			return err
+		}
+		return repo.SaveDebit(ctx, withdrawal(user.ID, 40))
+	})
+	require.NoError(t, err)
+
+	balance, err := repo.Balance(context.Background(), user.ID, testFundName)
+	require.NoError(t, err)
+	assert.Equal(t, "60", balance.String())
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	users := NewDirectUserRepository(repo.store)
+	unitOfWork := NewUnitOfWork(repo.store)
+	kept := deposit(user.ID, 100)
+	require.NoError(t, repo.Save(context.Background(), kept))
+
+	failure := errors.New("failed")
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		if err := repo.Save(ctx, deposit(user.ID, 50)); err != nil {
+			return err
+		}
+		if err := users.Save(ctx, domain.NewDirectUser("Jane Doe", time.Now())); err != nil {
+			return err
+		}
+		// A nested unit of work joins this one and is undone with it
+		if err := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			renamed := *user
+			renamed.Name = "Johnny Doe"
+			return users.Update(ctx, &renamed)
+		}); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	transactions, err := repo.FindByUserID(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, kept.ID, transactions[0].ID)
+
+	found, err := users.FindByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", found.Name)
+	assert.Len(t, repo.store.users, 1)
+}
+
+func TestUnitOfWork_RollsBackEveryChange(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	store := repo.store
+	prices := NewFundPriceRepository(store)
+	outbox := NewOutbox(store)
+	auditLog := NewAuditLog(store)
+	unitOfWork := NewUnitOfWork(store)
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	fund, found := store.fundByName(testFundName)
+	require.True(t, found)
+	for day := 3; day <= 5; day++ {
+		price := domain.NewFundPrice(fund.ID, now.AddDate(0, 0, day), decimal.NewFromInt(1), decimal.NewFromInt(1))
+		require.NoError(t, prices.Save(context.Background(), price))
+	}
+	pending := newTestEvent(t, now)
+	require.NoError(t, outbox.Add(context.Background(), pending))
+
+	failure := errors.New("failed")
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		// Priced before the others, so it is sorted ahead of them
+		if err := prices.Save(ctx, domain.NewFundPrice(fund.ID, now, decimal.NewFromInt(1), decimal.NewFromInt(1))); err != nil {
+			return err
+		}
+		if err := outbox.MarkPublished(ctx, pending.ID, now); err != nil {
+			return err
+		}
+		if err := outbox.Add(ctx, newTestEvent(t, now)); err != nil {
+			return err
+		}
+		entry, err := domain.NewAuditEntry("admin-1", domain.AuditActionCreate, nil, user, "", now)
+		if err != nil {
+			return err
+		}
+		if err := auditLog.Record(ctx, entry); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	saved, err := prices.FindByFundID(context.Background(), fund.ID)
+	require.NoError(t, err)
+	require.Len(t, saved, 3)
+	for i, price := range saved {
+		assert.True(t, price.ValuationDate.Equal(now.AddDate(0, 0, i+3)), "price %d is valued at %s", i, price.ValuationDate)
+	}
+
+	events, err := outbox.Claim(context.Background(), now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, pending.ID, events[0].Event.ID)
+
+	assert.Empty(t, store.auditLog)
+}
+
+func TestUnitOfWork_RollsBackOnPanic(t *testing.T) {
+	repo, user := setupTransactionStore(t)
+	unitOfWork := NewUnitOfWork(repo.store)
+
+	assert.Panics(t, func() {
+		_ = unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+			if err := repo.Save(ctx, deposit(user.ID, 100)); err != nil {
+				return err
+			}
+			panic("failed")
+		})
+	})
+
+	transactions, err := repo.FindByUserID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	// The store stops recording changes once the unit of work is over
+	require.NoError(t, repo.Save(context.Background(), deposit(user.ID, 50)))
+	assert.False(t, repo.store.recording)
+	assert.Empty(t, repo.store.undo)
+}
@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// AuditLog implements the output.AuditLog interface using MySQL
+type AuditLog struct {
+	db *sql.DB
+}
+
+// NewAuditLog creates a new MySQL audit log
+func NewAuditLog(db *sql.DB) output.AuditLog {
+	return &AuditLog{
+		db: db,
+	}
+}
+
+// Record saves an entry, in the transaction of the unit of work ctx carries if there is one
+func (l *AuditLog) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (id, actor, action, entity_type, entity_id, before_state, after_state, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, l.db).ExecContext(ctx, query,
+		entry.ID,
+		entry.Actor,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		entry.RequestID,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+// FindPage retrieves a page of the entries matching the query, oldest first.
+// The page is read past the cursor along idx_audit_log_entity, with one row
+// more than the limit to tell whether another page follows.
+func (l *AuditLog) FindPage(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	filter := "entity_type = ?"
+	args := []interface{}{query.EntityType}
+	if query.EntityID != "" {
+		filter += " AND entity_id = ?"
+		args = append(args, query.EntityID)
+	}
+	if !query.From.IsZero() {
+		filter += " AND created_at >= ?"
+		args = append(args, query.From)
+	}
+	if !query.To.IsZero() {
+		filter += " AND created_at < ?"
+		args = append(args, query.To)
+	}
+	if query.After != nil {
+		filter += " AND (created_at > ? OR (created_at = ? AND id > ?))"
+		args = append(args, query.After.CreatedAt, query.After.CreatedAt, query.After.ID)
+	}
+
+	rows, err := conn(ctx, l.db).QueryContext(ctx, `
+		SELECT id, actor, action, entity_type, entity_id, before_state, after_state, request_id, created_at
+		FROM audit_log
+		WHERE `+filter+`
+		ORDER BY created_at ASC, id ASC
+		LIMIT ?
+	`, append(args, query.Limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &domain.AuditPage{Entries: []*domain.AuditEntry{}}
+	for rows.Next() {
+		if len(page.Entries) == query.Limit {
+			last := page.Entries[len(page.Entries)-1]
+			page.Next = &domain.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+			break
+		}
+
+		var entry domain.AuditEntry
+		var before, after []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&before,
+			&after,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before = json.RawMessage(before)
+		entry.After = json.RawMessage(after)
+		page.Entries = append(page.Entries, &entry)
+	}
+
+	return page, rows.Err()
+}
+
+// nullJSON converts an empty snapshot to NULL
+func nullJSON(snapshot json.RawMessage) interface{} {
+	if len(snapshot) == 0 {
+		return nil
+	}
+	return []byte(snapshot)
+}
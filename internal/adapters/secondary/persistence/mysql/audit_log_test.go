@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditColumns = []string{"id", "actor", "action", "entity_type", "entity_id", "before_state", "after_state", "request_id", "created_at"}
+
+func setupAuditTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *AuditLog) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	log := NewAuditLog(db).(*AuditLog)
+	return db, mock, log
+}
+
+func TestAuditLog_Record(t *testing.T) {
+	db, mock, log := setupAuditTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	user := domain.NewDirectUser("John Doe", now)
+	entry, err := domain.NewAuditEntry("admin-1", domain.AuditActionCreate, nil, user, "req-1", now)
+	require.NoError(t, err)
+
+	// A creation has no before snapshot, stored as NULL
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(entry.ID, "admin-1", domain.AuditActionCreate, domain.AuditEntityDirectUser, user.ID, nil, []byte(entry.After), "req-1", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, log.Record(context.Background(), entry))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditLog_Record_InUnitOfWork(t *testing.T) {
+	db, mock, log := setupAuditTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	user := domain.NewDirectUser("John Doe", now)
+	entry, err := domain.NewAuditEntry("admin-1", domain.AuditActionDelete, user, nil, "req-1", now)
+	require.NoError(t, err)
+
+	// The entry is written in the unit's transaction and rolled back with it
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	failure := assert.AnError
+	err = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		if err := log.Record(ctx, entry); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditLog_FindPage(t *testing.T) {
+	db, mock, log := setupAuditTestDB(t)
+	defer db.Close()
+
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+	after := &domain.AuditCursor{CreatedAt: time.Date(2025, time.January, 2, 9, 0, 0, 0, time.UTC), ID: "audit-1"}
+	first := time.Date(2025, time.January, 3, 12, 0, 0, 0, time.UTC)
+	second := time.Date(2025, time.January, 4, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("WHERE entity_type = \\? AND entity_id = \\? AND created_at >= \\? AND created_at < \\? AND \\(created_at > \\? OR \\(created_at = \\? AND id > \\?\\)\\)\\s+ORDER BY created_at ASC, id ASC\\s+LIMIT \\?").
+		WithArgs(domain.AuditEntityTransaction, "txn-1", from, to, after.CreatedAt, after.CreatedAt, after.ID, 3).
+		WillReturnRows(sqlmock.NewRows(auditColumns).
+			AddRow("audit-2", "admin-1", "create", "transaction", "txn-1", nil, []byte(`{"amount":"100"}`), "req-1", first).
+			AddRow("audit-3", "admin-1", "correct", "transaction", "txn-1", []byte(`{"amount":"100"}`), []byte(`{"amount":"150"}`), "req-2", second).
+			AddRow("audit-4", "admin-1", "reverse", "transaction", "txn-1", []byte(`{"amount":"150"}`), []byte(`{"amount":"-150"}`), "req-3", second))
+
+	page, err := log.FindPage(context.Background(), domain.AuditQuery{
+		EntityType: domain.AuditEntityTransaction,
+		EntityID:   "txn-1",
+		From:       from,
+		To:         to,
+		Limit:      2,
+		After:      after,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, "audit-2", page.Entries[0].ID)
+	assert.Nil(t, page.Entries[0].Before)
+	assert.JSONEq(t, `{"amount":"150"}`, string(page.Entries[1].After))
+	assert.Equal(t, domain.AuditActionCorrect, page.Entries[1].Action)
+	assert.Equal(t, &domain.AuditCursor{CreatedAt: second, ID: "audit-3"}, page.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"database/sql"
+	"testing"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+	"cushon/internal/core/ports/output/outputtest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// newConformanceDB returns a mock database whose expectations must all be met
+// by the end of the check
+func newConformanceDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return db, mock
+}
+
+// duplicateKey is the error MySQL returns for a write breaking a unique key
+var duplicateKey = &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry for a unique key"}
+
+// expectExec scripts a write that affects the row only if it exists
+func expectExec(mock sqlmock.Sqlmock, query string, exists bool) {
+	var rowsAffected int64
+	if exists {
+		rowsAffected = 1
+	}
+	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, rowsAffected))
+}
+
+func TestDirectUserRepository_Conformance(t *testing.T) {
+	outputtest.RunDirectUserRepository(t, func(t *testing.T, op outputtest.Op, existing *domain.DirectUser) output.DirectUserRepository {
+		db, mock := newConformanceDB(t)
+
+		switch op {
+		case outputtest.OpFindByID:
+			query := mock.ExpectQuery("SELECT id, name, created_at, updated_at FROM direct_users")
+			if existing == nil {
+				query.WillReturnError(sql.ErrNoRows)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows(directUserColumns).AddRow(
+					existing.ID, existing.Name, existing.CreatedAt, existing.UpdatedAt,
+				))
+			}
+		case outputtest.OpUpdate:
+			expectExec(mock, "UPDATE direct_users", existing != nil)
+		case outputtest.OpDelete:
+			expectExec(mock, "DELETE FROM direct_users", existing != nil)
+		}
+
+		return NewDirectUserRepository(db)
+	})
+}
+
+func TestFundRepository_Conformance(t *testing.T) {
+	outputtest.RunFundRepository(t, func(t *testing.T, op outputtest.Op, existing *domain.Fund) output.FundRepository {
+		db, mock := newConformanceDB(t)
+
+		switch op {
+		case outputtest.OpFindByID, outputtest.OpFindByName:
+			query := mock.ExpectQuery("SELECT id, name, isin, asset_class, currency, risk_rating, status FROM funds")
+			if existing == nil {
+				query.WillReturnError(sql.ErrNoRows)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows(fundColumns).AddRow(
+					existing.ID, string(existing.Name), existing.ISIN, string(existing.AssetClass),
+					existing.Currency, existing.RiskRating, string(existing.Status),
+				))
+			}
+		case outputtest.OpSave:
+			mock.ExpectExec("INSERT INTO funds").WillReturnError(duplicateKey)
+		case outputtest.OpUpdate:
+			expectExec(mock, "UPDATE funds", existing != nil)
+		case outputtest.OpUpdateConflict:
+			mock.ExpectExec("INSERT INTO funds").WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec("UPDATE funds").WillReturnError(duplicateKey)
+		case outputtest.OpDelete:
+			expectExec(mock, "DELETE FROM funds", existing != nil)
+		}
+
+		return NewFundRepository(db)
+	})
+}
+
+func TestFundPriceRepository_Conformance(t *testing.T) {
+	outputtest.RunFundPriceRepository(t, func(t *testing.T, op outputtest.Op, existing *domain.FundPrice) output.FundPriceRepository {
+		db, mock := newConformanceDB(t)
+
+		query := mock.ExpectQuery("SELECT id, fund_id, valuation_date, bid, offer FROM fund_prices")
+		switch {
+		case existing != nil:
+			query.WillReturnRows(sqlmock.NewRows(fundPriceColumns).AddRow(
+				existing.ID, existing.FundID, existing.ValuationDate, existing.Bid.String(), existing.Offer.String(),
+			))
+		case op == outputtest.OpFindLatest:
+			query.WillReturnError(sql.ErrNoRows)
+		default:
+			query.WillReturnRows(sqlmock.NewRows(fundPriceColumns))
+		}
+
+		return NewFundPriceRepository(db)
+	})
+}
+
+func TestTransactionRepository_Conformance(t *testing.T) {
+	outputtest.RunTransactionRepository(t, func(t *testing.T, op outputtest.Op, existing *domain.Transaction) output.TransactionRepository {
+		db, mock := newConformanceDB(t)
+
+		if op == outputtest.OpFindPage {
+			mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectQuery("SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at FROM transactions").
+				WillReturnRows(sqlmock.NewRows(transactionColumns))
+			return NewTransactionRepository(db)
+		}
+
+		query := mock.ExpectQuery("SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at FROM transactions")
+		switch op {
+		case outputtest.OpFindByID:
+			if existing == nil {
+				query.WillReturnError(sql.ErrNoRows)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows(transactionColumns).AddRow(
+					existing.ID, existing.UserID, string(existing.Type), existing.Amount.String(),
+					string(existing.FundName), nil, nil, nil, nil, nil, nil,
+					existing.CreatedAt, existing.UpdatedAt,
+				))
+			}
+		case outputtest.OpFindReversal:
+			query.WillReturnError(sql.ErrNoRows)
+		case outputtest.OpFindByUserID:
+			query.WillReturnRows(sqlmock.NewRows(transactionColumns))
+		}
+
+		return NewTransactionRepository(db)
+	})
+}
@@ -16,9 +16,12 @@ type Config struct {
 	Database string
 }
 
-// NewConnection creates a new database connection
+// NewConnection creates a new database connection. Affected row counts include
+// rows matched but left unchanged, so an update is only reported as not found
+// when the row does not exist. The session runs in UTC so timestamps are read
+// back exactly as the application's clock wrote them.
 func NewConnection(config Config) (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&clientFoundRows=true&time_zone=%%27%%2B00%%3A00%%27",
 		config.User,
 		config.Password,
 		config.Host,
@@ -1,8 +1,8 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/output"
@@ -21,47 +21,44 @@ func NewDirectUserRepository(db *sql.DB) output.DirectUserRepository {
 }
 
 // Save persists a direct user to the database
-func (r *DirectUserRepository) Save(user *domain.DirectUser) error {
-	// Check if user already exists
-	existingUser, err := r.FindByID(user.ID)
-	if err == nil && existingUser != nil {
-		return errors.New("direct user already exists")
-	}
-
+func (r *DirectUserRepository) Save(ctx context.Context, user *domain.DirectUser) error {
 	query := `
-		INSERT INTO direct_users (id, name)
-		VALUES (?, ?)
+		INSERT INTO direct_users (id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
 	`
-	_, err = r.db.Exec(query, user.ID, user.Name)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.ID, user.Name, user.CreatedAt, user.UpdatedAt)
+	if isDuplicateKey(err) {
+		return domain.NewConflictError("direct user already exists")
+	}
 	return err
 }
 
-// FindByID retrieves a direct user by ID
-func (r *DirectUserRepository) FindByID(id string) (*domain.DirectUser, error) {
+// FindByID retrieves a direct user by ID, returning domain.ErrUserNotFound if there is none
+func (r *DirectUserRepository) FindByID(ctx context.Context, id string) (*domain.DirectUser, error) {
 	query := `
-		SELECT id, name
+		SELECT id, name, created_at, updated_at
 		FROM direct_users
 		WHERE id = ?
 	`
 	user := &domain.DirectUser{}
-	err := r.db.QueryRow(query, id).Scan(&user.ID, &user.Name)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Name, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 	return user, nil
 }
 
-// Update updates an existing direct user
-func (r *DirectUserRepository) Update(user *domain.DirectUser) error {
+// Update renames an existing direct user, keeping the time it was created
+func (r *DirectUserRepository) Update(ctx context.Context, user *domain.DirectUser) error {
 	query := `
 		UPDATE direct_users
-		SET name = ?
+		SET name = ?, updated_at = ?
 		WHERE id = ?
 	`
-	result, err := r.db.Exec(query, user.Name, user.ID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, user.Name, user.UpdatedAt, user.ID)
 	if err != nil {
 		return err
 	}
@@ -71,18 +68,34 @@ func (r *DirectUserRepository) Update(user *domain.DirectUser) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("direct user not found")
+		return domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
-// Delete removes a direct user by ID
-func (r *DirectUserRepository) Delete(id string) error {
+// Delete removes a direct user by ID. A user with transactions cannot be
+// removed, as the ledger keeps every transaction.
+func (r *DirectUserRepository) Delete(ctx context.Context, id string) error {
 	query := `
 		DELETE FROM direct_users
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, id)
-	return err
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if isReferenced(err) {
+		return domain.NewConflictError("direct user has transactions")
+	}
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
 } 
\ No newline at end of file
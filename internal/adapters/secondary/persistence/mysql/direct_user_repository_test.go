@@ -1,15 +1,20 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"cushon/internal/core/domain"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
+var directUserColumns = []string{"id", "name", "created_at", "updated_at"}
+
 func setupDirectUserTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *DirectUserRepository) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -24,15 +29,15 @@ func TestDirectUserRepository_Save(t *testing.T) {
 	db, mock, repo := setupDirectUserTestDB(t)
 	defer db.Close()
 
-	user := domain.NewDirectUser("John Doe")
+	user := domain.NewDirectUser("John Doe", time.Now())
 	expectedID := user.ID
 	expectedName := user.Name
 
 	mock.ExpectExec("INSERT INTO direct_users").
-		WithArgs(expectedID, expectedName).
+		WithArgs(expectedID, expectedName, user.CreatedAt, user.UpdatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err := repo.Save(user)
+	err := repo.Save(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -43,19 +48,23 @@ func TestDirectUserRepository_FindByID(t *testing.T) {
 
 	expectedID := "test-id"
 	expectedName := "John Doe"
+	createdAt := time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
 
-	rows := sqlmock.NewRows([]string{"id", "name"}).
-		AddRow(expectedID, expectedName)
+	rows := sqlmock.NewRows(directUserColumns).
+		AddRow(expectedID, expectedName, createdAt, updatedAt)
 
-	mock.ExpectQuery("SELECT id, name FROM direct_users").
+	mock.ExpectQuery("SELECT id, name, created_at, updated_at FROM direct_users").
 		WithArgs(expectedID).
 		WillReturnRows(rows)
 
-	user, err := repo.FindByID(expectedID)
+	user, err := repo.FindByID(context.Background(), expectedID)
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
 	assert.Equal(t, expectedID, user.ID)
 	assert.Equal(t, expectedName, user.Name)
+	assert.Equal(t, createdAt, user.CreatedAt)
+	assert.Equal(t, updatedAt, user.UpdatedAt)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -65,12 +74,12 @@ func TestDirectUserRepository_FindByID_NotFound(t *testing.T) {
 
 	expectedID := "non-existent"
 
-	mock.ExpectQuery("SELECT id, name FROM direct_users").
+	mock.ExpectQuery("SELECT id, name, created_at, updated_at FROM direct_users").
 		WithArgs(expectedID).
 		WillReturnError(sql.ErrNoRows)
 
-	user, err := repo.FindByID(expectedID)
-	assert.NoError(t, err)
+	user, err := repo.FindByID(context.Background(), expectedID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
 	assert.Nil(t, user)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -79,16 +88,16 @@ func TestDirectUserRepository_Update(t *testing.T) {
 	db, mock, repo := setupDirectUserTestDB(t)
 	defer db.Close()
 
-	user := domain.NewDirectUser("John Doe")
+	user := domain.NewDirectUser("John Doe", time.Now())
 	user.Name = "Jane Doe"
 	expectedID := user.ID
 	expectedName := user.Name
 
 	mock.ExpectExec("UPDATE direct_users").
-		WithArgs(expectedName, expectedID).
+		WithArgs(expectedName, user.UpdatedAt, expectedID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err := repo.Update(user)
+	err := repo.Update(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -103,7 +112,7 @@ func TestDirectUserRepository_Delete(t *testing.T) {
 		WithArgs(expectedID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err := repo.Delete(expectedID)
+	err := repo.Delete(context.Background(), expectedID)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -118,7 +127,22 @@ func TestDirectUserRepository_Delete_NotFound(t *testing.T) {
 		WithArgs(expectedID).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err := repo.Delete(expectedID)
-	assert.NoError(t, err)
+	err := repo.Delete(context.Background(), expectedID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
-} 
\ No newline at end of file
+}
+
+func TestDirectUserRepository_Delete_HasTransactions(t *testing.T) {
+	db, mock, repo := setupDirectUserTestDB(t)
+	defer db.Close()
+
+	expectedID := "test-id"
+
+	mock.ExpectExec("DELETE FROM direct_users").
+		WithArgs(expectedID).
+		WillReturnError(&mysqldriver.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails"})
+
+	err := repo.Delete(context.Background(), expectedID)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// FundPriceRepository implements the output.FundPriceRepository interface using MySQL
+type FundPriceRepository struct {
+	db *sql.DB
+}
+
+// NewFundPriceRepository creates a new MySQL fund price repository
+func NewFundPriceRepository(db *sql.DB) output.FundPriceRepository {
+	return &FundPriceRepository{
+		db: db,
+	}
+}
+
+// Save persists a fund price to the database
+func (r *FundPriceRepository) Save(ctx context.Context, price *domain.FundPrice) error {
+	query := `
+		INSERT INTO fund_prices (id, fund_id, valuation_date, bid, offer)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		price.ID,
+		price.FundID,
+		price.ValuationDate,
+		price.Bid,
+		price.Offer,
+	)
+	return err
+}
+
+// FindByFundID retrieves every price for a fund ordered by valuation date
+func (r *FundPriceRepository) FindByFundID(ctx context.Context, fundID string) ([]*domain.FundPrice, error) {
+	query := `
+		SELECT id, fund_id, valuation_date, bid, offer
+		FROM fund_prices
+		WHERE fund_id = ?
+		ORDER BY valuation_date
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, fundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*domain.FundPrice
+	for rows.Next() {
+		var price domain.FundPrice
+		err := rows.Scan(
+			&price.ID,
+			&price.FundID,
+			&price.ValuationDate,
+			&price.Bid,
+			&price.Offer,
+		)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, &price)
+	}
+
+	return prices, rows.Err()
+}
+
+// FindLatest retrieves the most recent price for a fund, or nil if it has never been priced
+func (r *FundPriceRepository) FindLatest(ctx context.Context, fundID string) (*domain.FundPrice, error) {
+	query := `
+		SELECT id, fund_id, valuation_date, bid, offer
+		FROM fund_prices
+		WHERE fund_id = ?
+		ORDER BY valuation_date DESC
+		LIMIT 1
+	`
+
+	var price domain.FundPrice
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, fundID).Scan(
+		&price.ID,
+		&price.FundID,
+		&price.ValuationDate,
+		&price.Bid,
+		&price.Offer,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var fundPriceColumns = []string{"id", "fund_id", "valuation_date", "bid", "offer"}
+
+func setupFundPriceTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *FundPriceRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewFundPriceRepository(db).(*FundPriceRepository)
+	return db, mock, repo
+}
+
+func TestFundPriceRepository_Save(t *testing.T) {
+	db, mock, repo := setupFundPriceTestDB(t)
+	defer db.Close()
+
+	price := domain.NewFundPrice("fund-id", time.Now(), decimal.RequireFromString("1.98"), decimal.RequireFromString("2.00"))
+
+	mock.ExpectExec("INSERT INTO fund_prices").
+		WithArgs(price.ID, price.FundID, price.ValuationDate, price.Bid.String(), price.Offer.String()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := repo.Save(context.Background(), price)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundPriceRepository_FindByFundID(t *testing.T) {
+	db, mock, repo := setupFundPriceTestDB(t)
+	defer db.Close()
+
+	valuationDate := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(fundPriceColumns).
+		AddRow("id1", "fund-id", valuationDate, "1.980000", "2.000000").
+		AddRow("id2", "fund-id", valuationDate.AddDate(0, 0, 1), "2.010000", "2.010000")
+
+	mock.ExpectQuery("SELECT id, fund_id, valuation_date, bid, offer FROM fund_prices").
+		WithArgs("fund-id").
+		WillReturnRows(rows)
+
+	prices, err := repo.FindByFundID(context.Background(), "fund-id")
+	assert.NoError(t, err)
+	assert.Len(t, prices, 2)
+	assert.False(t, prices[0].IsSinglePriced())
+	assert.True(t, prices[1].IsSinglePriced())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundPriceRepository_FindLatest(t *testing.T) {
+	db, mock, repo := setupFundPriceTestDB(t)
+	defer db.Close()
+
+	valuationDate := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(fundPriceColumns).
+		AddRow("id1", "fund-id", valuationDate, "1.980000", "2.000000")
+
+	mock.ExpectQuery("SELECT id, fund_id, valuation_date, bid, offer FROM fund_prices").
+		WithArgs("fund-id").
+		WillReturnRows(rows)
+
+	price, err := repo.FindLatest(context.Background(), "fund-id")
+	assert.NoError(t, err)
+	assert.NotNil(t, price)
+	assert.Equal(t, valuationDate, price.ValuationDate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundPriceRepository_FindLatest_NeverPriced(t *testing.T) {
+	db, mock, repo := setupFundPriceTestDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, fund_id, valuation_date, bid, offer FROM fund_prices").
+		WithArgs("fund-id").
+		WillReturnError(sql.ErrNoRows)
+
+	price, err := repo.FindLatest(context.Background(), "fund-id")
+	assert.NoError(t, err)
+	assert.Nil(t, price)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// FundRepository implements the output.FundRepository interface using MySQL
+type FundRepository struct {
+	db *sql.DB
+}
+
+// NewFundRepository creates a new MySQL fund repository
+func NewFundRepository(db *sql.DB) output.FundRepository {
+	return &FundRepository{
+		db: db,
+	}
+}
+
+// Save persists a fund to the database, returning domain.ErrFundExists if
+// its ID, name or ISIN is taken
+func (r *FundRepository) Save(ctx context.Context, fund *domain.Fund) error {
+	query := `
+		INSERT INTO funds (id, name, isin, asset_class, currency, risk_rating, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		fund.ID,
+		fund.Name,
+		fund.ISIN,
+		fund.AssetClass,
+		fund.Currency,
+		fund.RiskRating,
+		fund.Status,
+	)
+	if isDuplicateKey(err) {
+		return domain.ErrFundExists
+	}
+	return err
+}
+
+// FindByID retrieves a fund by ID, returning domain.ErrFundNotFound if there is none
+func (r *FundRepository) FindByID(ctx context.Context, id string) (*domain.Fund, error) {
+	query := `
+		SELECT id, name, isin, asset_class, currency, risk_rating, status
+		FROM funds
+		WHERE id = ?
+	`
+
+	return r.scanFund(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+}
+
+// FindByName retrieves a fund by its name, returning domain.ErrFundNotFound if there is none
+func (r *FundRepository) FindByName(ctx context.Context, name domain.FundName) (*domain.Fund, error) {
+	query := `
+		SELECT id, name, isin, asset_class, currency, risk_rating, status
+		FROM funds
+		WHERE name = ?
+	`
+
+	return r.scanFund(conn(ctx, r.db).QueryRowContext(ctx, query, name))
+}
+
+// FindAll retrieves every fund in the catalogue ordered by name
+func (r *FundRepository) FindAll(ctx context.Context) ([]*domain.Fund, error) {
+	query := `
+		SELECT id, name, isin, asset_class, currency, risk_rating, status
+		FROM funds
+		ORDER BY name
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var funds []*domain.Fund
+	for rows.Next() {
+		var fund domain.Fund
+		err := rows.Scan(
+			&fund.ID,
+			&fund.Name,
+			&fund.ISIN,
+			&fund.AssetClass,
+			&fund.Currency,
+			&fund.RiskRating,
+			&fund.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		funds = append(funds, &fund)
+	}
+
+	return funds, rows.Err()
+}
+
+// Update updates an existing fund, returning domain.ErrFundISINTaken if
+// another fund has its ISIN
+func (r *FundRepository) Update(ctx context.Context, fund *domain.Fund) error {
+	query := `
+		UPDATE funds
+		SET isin = ?, asset_class = ?, currency = ?, risk_rating = ?, status = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		fund.ISIN,
+		fund.AssetClass,
+		fund.Currency,
+		fund.RiskRating,
+		fund.Status,
+		fund.ID,
+	)
+	if isDuplicateKey(err) {
+		return domain.ErrFundISINTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrFundNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a fund by ID. A fund with prices or transactions cannot be removed.
+func (r *FundRepository) Delete(ctx context.Context, id string) error {
+	query := `
+		DELETE FROM funds
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if isReferenced(err) {
+		return domain.NewConflictError("fund has prices or transactions")
+	}
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrFundNotFound
+	}
+
+	return nil
+}
+
+// scanFund scans a single fund row, returning domain.ErrFundNotFound when no row matched
+func (r *FundRepository) scanFund(row *sql.Row) (*domain.Fund, error) {
+	var fund domain.Fund
+	err := row.Scan(
+		&fund.ID,
+		&fund.Name,
+		&fund.ISIN,
+		&fund.AssetClass,
+		&fund.Currency,
+		&fund.RiskRating,
+		&fund.Status,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrFundNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &fund, nil
+}
@@ -0,0 +1,165 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"cushon/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var fundColumns = []string{"id", "name", "isin", "asset_class", "currency", "risk_rating", "status"}
+
+func setupFundTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *FundRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewFundRepository(db).(*FundRepository)
+	return db, mock, repo
+}
+
+func TestFundRepository_Save(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	fund := domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+
+	mock.ExpectExec("INSERT INTO funds").
+		WithArgs(fund.ID, string(fund.Name), fund.ISIN, string(fund.AssetClass), fund.Currency, fund.RiskRating, string(fund.Status)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := repo.Save(context.Background(), fund)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_Save_DuplicateISIN(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	fund := domain.NewFund("Cushon Global Fund", "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+
+	mock.ExpectExec("INSERT INTO funds").
+		WillReturnError(&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'GB00B3X7QG63' for key 'funds.isin'"})
+
+	err := repo.Save(context.Background(), fund)
+	assert.ErrorIs(t, err, domain.ErrFundExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_FindByName(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(fundColumns).
+		AddRow("fund-id", "Cushon Equities Fund", "GB00B3X7QG63", "equity", "GBP", 5, "open")
+
+	mock.ExpectQuery("SELECT id, name, isin, asset_class, currency, risk_rating, status FROM funds").
+		WithArgs("Cushon Equities Fund").
+		WillReturnRows(rows)
+
+	fund, err := repo.FindByName(context.Background(), domain.CushonEquitiesFund)
+	assert.NoError(t, err)
+	assert.NotNil(t, fund)
+	assert.Equal(t, "fund-id", fund.ID)
+	assert.Equal(t, domain.AssetClassEquity, fund.AssetClass)
+	assert.Equal(t, 5, fund.RiskRating)
+	assert.True(t, fund.IsOpen())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_FindByID_NotFound(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name, isin, asset_class, currency, risk_rating, status FROM funds").
+		WithArgs("non-existent").
+		WillReturnError(sql.ErrNoRows)
+
+	fund, err := repo.FindByID(context.Background(), "non-existent")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, fund)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_FindAll(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(fundColumns).
+		AddRow("id1", "Cushon Equities Fund", "GB00B3X7QG63", "equity", "GBP", 5, "open").
+		AddRow("id2", "Cushon Global Bond Fund", "US0378331005", "bond", "GBP", 3, "closed")
+
+	mock.ExpectQuery("SELECT id, name, isin, asset_class, currency, risk_rating, status FROM funds").
+		WillReturnRows(rows)
+
+	funds, err := repo.FindAll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, funds, 2)
+	assert.False(t, funds[1].IsOpen())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_Update(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	fund := domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+	fund.Status = domain.FundStatusClosed
+
+	mock.ExpectExec("UPDATE funds").
+		WithArgs(fund.ISIN, string(fund.AssetClass), fund.Currency, fund.RiskRating, string(fund.Status), fund.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Update(context.Background(), fund)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_Update_NotFound(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	fund := domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+
+	mock.ExpectExec("UPDATE funds").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Update(context.Background(), fund)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_Update_DuplicateISIN(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	fund := domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+
+	mock.ExpectExec("UPDATE funds").
+		WillReturnError(&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'GB00B3X7QG63' for key 'funds.isin'"})
+
+	err := repo.Update(context.Background(), fund)
+	assert.ErrorIs(t, err, domain.ErrFundISINTaken)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFundRepository_Delete(t *testing.T) {
+	db, mock, repo := setupFundTestDB(t)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM funds").
+		WithArgs("fund-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Delete(context.Background(), "fund-id")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,158 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// IdempotencyRepository implements the output.IdempotencyRepository interface using MySQL
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository creates a new MySQL idempotency repository
+func NewIdempotencyRepository(db *sql.DB) output.IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve saves record unless an unexpired record already holds its key,
+// which is returned instead. The key's row is locked while it is checked, so
+// of two requests racing with the same key only one is saved; the other
+// deadlocks, is retried and finds the first.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	var existing *domain.IdempotencyRecord
+	err := inTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		existing = nil
+
+		found, err := r.find(ctx, tx, record.Principal, record.Key)
+		if err != nil {
+			return err
+		}
+		if found != nil && !found.IsExpired(record.CreatedAt) {
+			existing = found
+			return nil
+		}
+
+		if found != nil {
+			_, err = tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE principal = ? AND idempotency_key = ?`, record.Principal, record.Key)
+			if err != nil {
+				return err
+			}
+		}
+
+		query := `
+			INSERT INTO idempotency_keys (principal, idempotency_key, request_hash, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		_, err = tx.ExecContext(ctx, query,
+			record.Principal,
+			record.Key,
+			record.RequestHash,
+			record.CreatedAt,
+			record.ExpiresAt,
+		)
+		if isDuplicateKey(err) {
+			return domain.NewConflictError("idempotency key is already in use")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// find retrieves the record holding a key and locks its row, or nil if there is none
+func (r *IdempotencyRepository) find(ctx context.Context, tx *sql.Tx, principal, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+		SELECT principal, idempotency_key, request_hash, status_code, content_type, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE principal = ? AND idempotency_key = ?
+		FOR UPDATE
+	`
+
+	var record domain.IdempotencyRecord
+	var statusCode sql.NullInt32
+	err := tx.QueryRowContext(ctx, query, principal, key).Scan(
+		&record.Principal,
+		&record.Key,
+		&record.RequestHash,
+		&statusCode,
+		&record.ContentType,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record.StatusCode = int(statusCode.Int32)
+	return &record, nil
+}
+
+// Complete stores the response of a reserved request and when it expires.
+// The row is matched on the request and when it was reserved as well as the
+// key, so a request that outlived its reservation cannot overwrite the
+// reservation of a retry.
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = ?, content_type = ?, body = ?, expires_at = ?
+		WHERE principal = ? AND idempotency_key = ? AND request_hash = ? AND created_at = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		record.StatusCode,
+		record.ContentType,
+		record.Body,
+		record.ExpiresAt,
+		record.Principal,
+		record.Key,
+		record.RequestHash,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+// Release deletes the reservation record made, if it still holds the key
+func (r *IdempotencyRepository) Release(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE principal = ? AND idempotency_key = ? AND request_hash = ? AND created_at = ?
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, record.Principal, record.Key, record.RequestHash, record.CreatedAt)
+	return err
+}
+
+// DeleteExpired deletes the records expired by now
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
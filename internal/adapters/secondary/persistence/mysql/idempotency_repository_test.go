@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var idempotencyColumns = []string{"principal", "idempotency_key", "request_hash", "status_code", "content_type", "body", "created_at", "expires_at"}
+
+func setupIdempotencyTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *IdempotencyRepository) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	repo := NewIdempotencyRepository(db).(*IdempotencyRepository)
+	return db, mock, repo
+}
+
+func TestIdempotencyRepository_Reserve_NewKey(t *testing.T) {
+	db, mock, repo := setupIdempotencyTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	record := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, 24*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT principal, idempotency_key, request_hash, status_code, content_type, body, created_at, expires_at FROM idempotency_keys WHERE principal = \\? AND idempotency_key = \\? FOR UPDATE").
+		WithArgs("user-1", "key-1").
+		WillReturnRows(sqlmock.NewRows(idempotencyColumns))
+	mock.ExpectExec("INSERT INTO idempotency_keys").
+		WithArgs("user-1", "key-1", "hash-1", now, now.Add(24*time.Hour)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	existing, err := repo.Reserve(context.Background(), record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_Reserve_ExistingKey(t *testing.T) {
+	db, mock, repo := setupIdempotencyTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	record := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, 24*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT principal, idempotency_key, request_hash, status_code, content_type, body, created_at, expires_at FROM idempotency_keys").
+		WithArgs("user-1", "key-1").
+		WillReturnRows(sqlmock.NewRows(idempotencyColumns).
+			AddRow("user-1", "key-1", "hash-1", 201, "application/json; charset=utf-8", []byte(`{"id":"tx-1"}`), now.Add(-time.Minute), now.Add(time.Hour)))
+	mock.ExpectCommit()
+
+	existing, err := repo.Reserve(context.Background(), record)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, 201, existing.StatusCode)
+	assert.Equal(t, `{"id":"tx-1"}`, string(existing.Body))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_Reserve_ReplacesExpiredKey(t *testing.T) {
+	db, mock, repo := setupIdempotencyTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	record := domain.NewIdempotencyRecord("user-1", "key-1", "hash-2", now, 24*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT principal, idempotency_key, request_hash, status_code, content_type, body, created_at, expires_at FROM idempotency_keys").
+		WithArgs("user-1", "key-1").
+		WillReturnRows(sqlmock.NewRows(idempotencyColumns).
+			AddRow("user-1", "key-1", "hash-1", nil, "", nil, now.Add(-48*time.Hour), now.Add(-24*time.Hour)))
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE principal = \\? AND idempotency_key = \\?").
+		WithArgs("user-1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO idempotency_keys").
+		WithArgs("user-1", "key-1", "hash-2", now, now.Add(24*time.Hour)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	existing, err := repo.Reserve(context.Background(), record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_Complete(t *testing.T) {
+	db, mock, repo := setupIdempotencyTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	record := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, time.Minute)
+	record.Complete(201, "application/json; charset=utf-8", []byte(`{"id":"tx-1"}`), now, time.Hour)
+
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code = \\?, content_type = \\?, body = \\?, expires_at = \\?\\s+WHERE principal = \\? AND idempotency_key = \\? AND request_hash = \\? AND created_at = \\?").
+		WithArgs(201, "application/json; charset=utf-8", []byte(`{"id":"tx-1"}`), now.Add(time.Hour), "user-1", "key-1", "hash-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Complete(context.Background(), record))
+
+	mock.ExpectExec("UPDATE idempotency_keys").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Complete(context.Background(), record), domain.ErrIdempotencyKeyNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_Release(t *testing.T) {
+	db, mock, repo := setupIdempotencyTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	record := domain.NewIdempotencyRecord("user-1", "key-1", "hash-1", now, time.Minute)
+
+	// Only the reservation the record made is deleted, not a retry's made since
+	mock.ExpectExec("DELETE FROM idempotency_keys\\s+WHERE principal = \\? AND idempotency_key = \\? AND request_hash = \\? AND created_at = \\?").
+		WithArgs("user-1", "key-1", "hash-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, repo.Release(context.Background(), record))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	db, mock, repo := setupIdempotencyTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE expires_at <= \\?").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,409 @@
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock names the MySQL user lock held while migrating, so two
+// instances starting together do not migrate at the same time
+const migrationLock = "cushon_schema_migrations"
+
+// migrationFileName matches migration files such as 0001_create_schema.up.sql
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change to the database schema
+type Migration struct {
+	Version int
+	Name    string
+	// Up applies the change and Down reverts it
+	Up   string
+	Down string
+	// Checksum identifies the content of Up, so a change to a migration that
+	// has already been applied can be detected
+	Checksum string
+}
+
+// AppliedMigration records a migration applied to the database
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	// AppliedAt is nil if the migration is pending
+	AppliedAt *time.Time
+}
+
+// Migrator applies the migrations embedded in the binary to a MySQL database.
+// Applied migrations are recorded in the schema_migrations table. MySQL
+// cannot roll back schema changes, so a migration that fails part way
+// through must be repaired by hand before migrating again.
+type Migrator struct {
+	db          *sql.DB
+	migrations  []Migration
+	lockTimeout time.Duration
+}
+
+// NewMigrator creates a migrator for the migrations embedded in the binary
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:          db,
+		migrations:  migrations,
+		lockTimeout: 30 * time.Second,
+	}, nil
+}
+
+// LatestVersion returns the version of the newest migration the binary holds
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the version of the newest migration applied to the database, 0 if none
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	var version int
+	err := m.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if isNoSuchTable(err) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// CheckVersion returns an error if the database is missing migrations the
+// binary expects. A database ahead of the binary passes, since during a
+// rolling deploy instances of the previous release still run against the
+// migrated schema.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if latest := m.LatestVersion(); version < latest {
+		return fmt.Errorf("database is at migration %d, expected %d", version, latest)
+	}
+	return nil
+}
+
+// Up applies every pending migration in version order, returning those applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+
+			if err := execScript(ctx, conn, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+
+			if _, err := conn.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)`,
+				migration.Version, migration.Name, migration.Checksum,
+			); err != nil {
+				return fmt.Errorf("failed to record migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the given number of most recently applied migrations, returning those reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+
+			if err := execScript(ctx, conn, migration.Down); err != nil {
+				return fmt.Errorf("reverting migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+
+			if _, err := conn.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version); err != nil {
+				return fmt.Errorf("failed to record reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists every migration the binary holds and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	done, err := m.verify(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if applied, ok := done[migration.Version]; ok {
+			appliedAt := applied.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Conn
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// verify checks the migrations applied to the database are the ones the
+// binary holds, unchanged, and returns them by version
+func (m *Migrator) verify(ctx context.Context, db queryer) (map[int]AppliedMigration, error) {
+	done := make(map[int]AppliedMigration)
+
+	rows, err := db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if isNoSuchTable(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for rows.Next() {
+		var applied AppliedMigration
+		if err := rows.Scan(&applied.Version, &applied.Name, &applied.Checksum, &applied.AppliedAt); err != nil {
+			return nil, err
+		}
+
+		migration, ok := known[applied.Version]
+		if !ok {
+			return nil, fmt.Errorf("database has migration %d_%s applied, which this binary does not know; it may be older than the database", applied.Version, applied.Name)
+		}
+		if migration.Checksum != applied.Checksum {
+			return nil, fmt.Errorf("migration %d_%s has changed since it was applied; add a new migration instead of editing an applied one", migration.Version, migration.Name)
+		}
+
+		done[applied.Version] = applied
+	}
+
+	return done, rows.Err()
+}
+
+// withLock runs fn on a single connection holding the migration lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	// MySQL user locks belong to a session, so everything runs on one connection
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, migrationLock, int(m.lockTimeout.Seconds())).Scan(&acquired); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("timed out after %s waiting for another instance to finish migrating", m.lockTimeout)
+	}
+	defer func() {
+		if _, releaseErr := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, migrationLock); releaseErr != nil && err == nil {
+			err = fmt.Errorf("failed to release migration lock: %w", releaseErr)
+		}
+	}()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// ensureMigrationsTable creates the table recording applied migrations if it does not exist
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// execScript runs each statement of a migration script in turn
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	statements, err := splitStatements(script)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitStatements splits a script into statements. Statements end with a
+// semicolon at the end of a line, or with the delimiter set by a DELIMITER
+// line, as in the mysql client, so statements such as triggers can contain
+// semicolons and scripts can also be run by hand.
+func splitStatements(script string) ([]string, error) {
+	var statements []string
+	var current strings.Builder
+	delimiter := ";"
+
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if current.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+
+		if fields := strings.Fields(trimmed); len(fields) > 0 && strings.EqualFold(fields[0], "DELIMITER") {
+			if current.Len() > 0 {
+				return nil, fmt.Errorf("DELIMITER inside an unterminated statement: %q", current.String())
+			}
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("DELIMITER needs exactly one delimiter: %q", trimmed)
+			}
+			delimiter = fields[1]
+			continue
+		}
+
+		if strings.HasSuffix(trimmed, delimiter) {
+			current.WriteString(strings.TrimSuffix(trimmed, delimiter))
+			if statement := strings.TrimSpace(current.String()); statement != "" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		return nil, fmt.Errorf("statement is missing its delimiter %q: %q", delimiter, statement)
+	}
+
+	return statements, nil
+}
+
+// loadMigrations reads the migration files in dir, each version needing both
+// an up and a down script, and returns them in version order
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s must be named <version>_<name>.up.sql or <version>_<name>.down.sql", entry.Name())
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,214 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var migrationColumns = []string{"version", "name", "checksum", "applied_at"}
+
+// setupMigratorTestDB returns a migrator holding two migrations
+func setupMigratorTestDB(t *testing.T) (sqlmock.Sqlmock, *Migrator) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := loadMigrations(fstest.MapFS{
+		"migrations/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);\n")},
+		"migrations/0001_create_users.down.sql": {Data: []byte("DROP TABLE users;\n")},
+		"migrations/0002_create_funds.up.sql":   {Data: []byte("CREATE TABLE funds (id INT);\nCREATE INDEX idx ON funds (id);\n")},
+		"migrations/0002_create_funds.down.sql": {Data: []byte("DROP TABLE funds;\n")},
+	}, "migrations")
+	require.NoError(t, err)
+
+	return mock, &Migrator{db: db, migrations: migrations, lockTimeout: time.Second}
+}
+
+func expectLock(mock sqlmock.Sqlmock, acquired int) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, ?)")).
+		WithArgs(migrationLock, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(acquired))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	mock, migrator := setupMigratorTestDB(t)
+	first := migrator.migrations[0]
+
+	expectLock(mock, 1)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows(migrationColumns).AddRow(first.Version, first.Name, first.Checksum, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE funds (id INT)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX idx ON funds (id)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(2, "create_funds", migrator.migrations[1].Checksum).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_ChangedMigration(t *testing.T) {
+	mock, migrator := setupMigratorTestDB(t)
+
+	expectLock(mock, 1)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows(migrationColumns).AddRow(1, "create_users", "edited", time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := migrator.Up(context.Background())
+	assert.ErrorContains(t, err, "migration 1_create_users has changed since it was applied")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_UnknownMigration(t *testing.T) {
+	mock, migrator := setupMigratorTestDB(t)
+
+	expectLock(mock, 1)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows(migrationColumns).AddRow(3, "create_prices", "checksum", time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := migrator.Up(context.Background())
+	assert.ErrorContains(t, err, "which this binary does not know")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_Locked(t *testing.T) {
+	mock, migrator := setupMigratorTestDB(t)
+
+	expectLock(mock, 0)
+
+	_, err := migrator.Up(context.Background())
+	assert.ErrorContains(t, err, "waiting for another instance to finish migrating")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	mock, migrator := setupMigratorTestDB(t)
+
+	rows := sqlmock.NewRows(migrationColumns)
+	for _, migration := range migrator.migrations {
+		rows.AddRow(migration.Version, migration.Name, migration.Checksum, time.Now())
+	}
+
+	expectLock(mock, 1)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").WillReturnRows(rows)
+	mock.ExpectExec("DROP TABLE funds").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs(migrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := migrator.Down(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Version_NeverMigrated(t *testing.T) {
+	mock, migrator := setupMigratorTestDB(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM schema_migrations")).
+		WillReturnError(&mysqldriver.MySQLError{Number: 1146, Message: "Table 'cushon.schema_migrations' doesn't exist"})
+
+	version, err := migrator.Version(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+	assert.Equal(t, 2, migrator.LatestVersion())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_CheckVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		wantErr string
+	}{
+		{name: "up to date", version: 2},
+		{name: "ahead of the binary", version: 3},
+		{name: "behind the binary", version: 1, wantErr: "database is at migration 1, expected 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, migrator := setupMigratorTestDB(t)
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM schema_migrations")).
+				WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(tt.version))
+
+			err := migrator.CheckVersion(context.Background())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- Triggers contain semicolons, so they need another delimiter
+DROP TRIGGER IF EXISTS no_delete;
+
+DELIMITER $$
+
+CREATE TRIGGER no_delete BEFORE DELETE ON transactions
+FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000';
+END$$
+
+DELIMITER ;
+`
+
+	statements, err := splitStatements(script)
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	assert.Equal(t, "DROP TRIGGER IF EXISTS no_delete", statements[0])
+	assert.Contains(t, statements[1], "SIGNAL SQLSTATE '45000';\nEND")
+}
+
+func TestSplitStatements_Unterminated(t *testing.T) {
+	_, err := splitStatements("CREATE TABLE users (id INT)\n")
+	assert.ErrorContains(t, err, "missing its delimiter")
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migrations are numbered from 1 without gaps")
+
+		for _, script := range []string{migration.Up, migration.Down} {
+			_, err := splitStatements(script)
+			assert.NoError(t, err, "migration %d_%s", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestLoadMigrations_MissingDown(t *testing.T) {
+	_, err := loadMigrations(fstest.MapFS{
+		"migrations/0001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id INT);\n")},
+	}, "migrations")
+	assert.ErrorContains(t, err, "needs both an up and a down script")
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS fund_prices;
+DROP TABLE IF EXISTS funds;
+DROP TABLE IF EXISTS direct_users;
@@ -0,0 +1,60 @@
+CREATE TABLE direct_users (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE funds (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    isin CHAR(12) NOT NULL UNIQUE,
+    asset_class VARCHAR(32) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    risk_rating TINYINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT valid_asset_class CHECK (asset_class IN ('equity', 'bond', 'multi-asset', 'property', 'cash')),
+    CONSTRAINT valid_risk_rating CHECK (risk_rating BETWEEN 1 AND 7),
+    CONSTRAINT valid_fund_status CHECK (status IN ('open', 'closed'))
+);
+
+INSERT INTO funds (id, name, isin, asset_class, currency, risk_rating, status)
+VALUES ('7d1c1e0a-4a4e-4f7e-9a51-1f3b0c6d2e01', 'Cushon Equities Fund', 'GB00B3X7QG63', 'equity', 'GBP', 5, 'open');
+
+CREATE TABLE fund_prices (
+    id VARCHAR(36) PRIMARY KEY,
+    fund_id VARCHAR(36) NOT NULL,
+    valuation_date DATETIME NOT NULL,
+    bid DECIMAL(19,6) NOT NULL,
+    offer DECIMAL(19,6) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (fund_id) REFERENCES funds(id),
+    UNIQUE KEY uniq_fund_valuation_date (fund_id, valuation_date),
+    CONSTRAINT valid_prices CHECK (bid > 0 AND offer >= bid)
+);
+
+CREATE TABLE transactions (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    type VARCHAR(16) NOT NULL DEFAULT 'deposit',
+    amount DECIMAL(19,4) NOT NULL,
+    fund_name VARCHAR(255) NOT NULL,
+    units DECIMAL(19,6) NULL,
+    unit_price DECIMAL(19,6) NULL,
+    valuation_date DATETIME NULL,
+    reversal_of VARCHAR(36) NULL,
+    reason VARCHAR(255) NULL,
+    actor VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES direct_users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (fund_name) REFERENCES funds(name),
+    FOREIGN KEY (reversal_of) REFERENCES transactions(id) ON DELETE RESTRICT,
+    UNIQUE KEY uq_transactions_reversal_of (reversal_of),
+    INDEX idx_transactions_pending (fund_name, valuation_date, created_at),
+    INDEX idx_transactions_balance (user_id, fund_name),
+    CONSTRAINT valid_transaction_type CHECK (type IN ('deposit', 'withdrawal', 'fee', 'interest', 'transfer-in', 'transfer-out')),
+    CONSTRAINT positive_amount CHECK (amount > 0)
+);
@@ -0,0 +1,29 @@
+-- The ledger is append-only: corrections are made with reversing entries.
+-- The only update allowed records the units allocated to a pending transaction.
+DROP TRIGGER IF EXISTS transactions_no_update;
+DROP TRIGGER IF EXISTS transactions_no_delete;
+
+DELIMITER $$
+
+CREATE TRIGGER transactions_no_update BEFORE UPDATE ON transactions
+FOR EACH ROW
+BEGIN
+    IF OLD.valuation_date IS NOT NULL
+        OR NOT (NEW.user_id <=> OLD.user_id)
+        OR NOT (NEW.type <=> OLD.type)
+        OR NOT (NEW.amount <=> OLD.amount)
+        OR NOT (NEW.fund_name <=> OLD.fund_name)
+        OR NOT (NEW.reversal_of <=> OLD.reversal_of)
+        OR NOT (NEW.reason <=> OLD.reason)
+        OR NOT (NEW.actor <=> OLD.actor) THEN
+        SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'transactions are immutable, record a reversal instead';
+    END IF;
+END$$
+
+CREATE TRIGGER transactions_no_delete BEFORE DELETE ON transactions
+FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'transactions are immutable, record a reversal instead';
+END$$
+
+DELIMITER ;
@@ -0,0 +1,3 @@
+-- A user's transaction history is paged by the time each transaction was
+-- recorded, with the ID breaking ties, so each page is one range scan.
+CREATE INDEX idx_transactions_history ON transactions (user_id, created_at, id);
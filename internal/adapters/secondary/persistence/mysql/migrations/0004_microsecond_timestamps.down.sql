@@ -0,0 +1,7 @@
+ALTER TABLE transactions
+    MODIFY created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
+
+ALTER TABLE direct_users
+    MODIFY created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    MODIFY updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
@@ -0,0 +1,16 @@
+-- Requests sent with an Idempotency-Key header, with the response to replay
+-- when the request is retried. Keys are scoped to the principal that sent
+-- them, and may be used again once expired.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    principal VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    -- The response, NULL until the request completes
+    status_code SMALLINT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    body MEDIUMBLOB NULL,
+    created_at TIMESTAMP(6) NOT NULL,
+    expires_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (principal, idempotency_key),
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+);
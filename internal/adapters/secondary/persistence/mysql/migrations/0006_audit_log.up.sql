@@ -0,0 +1,36 @@
+-- Who changed which direct user or transaction, in which request, and the
+-- entity before and after. Entries are written in the same transaction as
+-- the change they record and are never changed or deleted.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id VARCHAR(36) PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    -- Snapshots of the entity, NULL before a creation and after a deletion
+    before_state JSON NULL,
+    after_state JSON NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(6) NOT NULL,
+    INDEX idx_audit_log_entity (entity_type, entity_id, created_at, id),
+    INDEX idx_audit_log_entity_type (entity_type, created_at, id)
+);
+
+DROP TRIGGER IF EXISTS audit_log_no_update;
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+
+DELIMITER $$
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'the audit log is append-only';
+END$$
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'the audit log is append-only';
+END$$
+
+DELIMITER ;
@@ -0,0 +1,16 @@
+-- Domain events waiting to be published to downstream systems. Events are
+-- written in the same transaction as the change they describe and marked
+-- published once the publisher has accepted them.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP(6) NOT NULL,
+    -- NULL until the event is published
+    published_at TIMESTAMP(6) NULL,
+    -- Failed attempts to publish the event, and the reason for the latest
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    INDEX idx_outbox_events_unpublished (published_at, occurred_at, id)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE outbox_events
+    DROP INDEX idx_outbox_events_aggregate,
+    DROP COLUMN dead_lettered_at,
+    DROP COLUMN claimed_until,
+    DROP COLUMN next_attempt_at;
@@ -0,0 +1,11 @@
+-- A failed event is retried after a delay and given up on after too many
+-- attempts, and a relay claims the events it publishes so that instances
+-- relaying at once do not publish the same events.
+ALTER TABLE outbox_events
+    -- NULL until the event fails, then the earliest it is tried again
+    ADD COLUMN next_attempt_at TIMESTAMP(6) NULL,
+    -- Set while a relay is publishing the event
+    ADD COLUMN claimed_until TIMESTAMP(6) NULL,
+    -- Set when the event has failed too often to try again
+    ADD COLUMN dead_lettered_at TIMESTAMP(6) NULL,
+    ADD INDEX idx_outbox_events_aggregate (aggregate_id, published_at, dead_lettered_at, occurred_at, id);
@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// Outbox implements the output.Outbox interface using MySQL
+type Outbox struct {
+	db *sql.DB
+}
+
+// NewOutbox creates a new MySQL outbox
+func NewOutbox(db *sql.DB) output.Outbox {
+	return &Outbox{
+		db: db,
+	}
+}
+
+// Add stores an event to publish, in the transaction of the unit of work ctx carries if there is one
+func (o *Outbox) Add(ctx context.Context, event *domain.Event) error {
+	query := `
+		INSERT INTO outbox_events (id, type, aggregate_id, payload, occurred_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, o.db).ExecContext(ctx, query,
+		event.ID,
+		event.Type,
+		event.AggregateID,
+		[]byte(event.Payload),
+		event.OccurredAt,
+	)
+	return err
+}
+
+// Claim takes up to limit events due to be published at now, oldest first,
+// holding them until the given time. The rows are locked as they are read,
+// skipping those another relay is claiming, and an event is offered only
+// once no earlier event of its aggregate is waiting, found along
+// idx_outbox_events_aggregate.
+func (o *Outbox) Claim(ctx context.Context, now time.Time, until time.Time, limit int) ([]*domain.PendingEvent, error) {
+	query := `
+		SELECT e.id, e.type, e.aggregate_id, e.payload, e.occurred_at, e.attempts
+		FROM outbox_events e
+		WHERE e.published_at IS NULL AND e.dead_lettered_at IS NULL
+			AND (e.next_attempt_at IS NULL OR e.next_attempt_at <= ?)
+			AND (e.claimed_until IS NULL OR e.claimed_until <= ?)
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events earlier
+				WHERE earlier.aggregate_id = e.aggregate_id
+					AND earlier.published_at IS NULL AND earlier.dead_lettered_at IS NULL
+					AND (earlier.occurred_at < e.occurred_at OR (earlier.occurred_at = e.occurred_at AND earlier.id < e.id))
+			)
+		ORDER BY e.occurred_at ASC, e.id ASC
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	events := []*domain.PendingEvent{}
+	err := inTx(ctx, o.db, func(ctx context.Context, tx *sql.Tx) error {
+		events = events[:0]
+		rows, err := tx.QueryContext(ctx, query, now, now, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var event domain.Event
+			var payload []byte
+			var attempts int
+			if err := rows.Scan(&event.ID, &event.Type, &event.AggregateID, &payload, &event.OccurredAt, &attempts); err != nil {
+				return err
+			}
+			event.Payload = payload
+			events = append(events, &domain.PendingEvent{Event: &event, Attempts: attempts})
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		args := []interface{}{until}
+		for _, pending := range events {
+			args = append(args, pending.Event.ID)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(events)), ", ")
+		_, err = tx.ExecContext(ctx, `UPDATE outbox_events SET claimed_until = ? WHERE id IN (`+placeholders+`)`, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// MarkPublished records that an event was published at the given time
+func (o *Outbox) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return o.update(ctx, `UPDATE outbox_events SET published_at = ? WHERE id = ?`, publishedAt, id)
+}
+
+// RecordFailure counts a failed attempt to publish an event, keeping the
+// reason, and releases it to be claimed again from retryAt
+func (o *Outbox) RecordFailure(ctx context.Context, id string, reason string, retryAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = ?, next_attempt_at = ?, claimed_until = NULL
+		WHERE id = ?
+	`
+	return o.update(ctx, query, reason, retryAt, id)
+}
+
+// DeadLetter counts a failed attempt to publish an event, keeping the reason,
+// and gives up on it
+func (o *Outbox) DeadLetter(ctx context.Context, id string, reason string, at time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = ?, dead_lettered_at = ?, claimed_until = NULL
+		WHERE id = ?
+	`
+	return o.update(ctx, query, reason, at, id)
+}
+
+// update runs a statement changing one event, ErrEventNotFound if it changes none
+func (o *Outbox) update(ctx context.Context, query string, args ...interface{}) error {
+	result, err := conn(ctx, o.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return domain.ErrEventNotFound
+	}
+
+	return nil
+}
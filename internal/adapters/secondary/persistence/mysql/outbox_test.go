@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var outboxColumns = []string{"id", "type", "aggregate_id", "payload", "occurred_at", "attempts"}
+
+func setupOutboxTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *Outbox) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+
+	outbox := NewOutbox(db).(*Outbox)
+	return db, mock, outbox
+}
+
+func TestOutbox_Add_InUnitOfWork(t *testing.T) {
+	db, mock, outbox := setupOutboxTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	user := domain.NewDirectUser("John Doe", now)
+	event, err := domain.NewDirectUserCreatedEvent(user, now)
+	require.NoError(t, err)
+
+	// The event is written in the unit's transaction, committed with the change
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(event.ID, domain.EventDirectUserCreated, user.ID, []byte(event.Payload), now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		return outbox.Add(ctx, event)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutbox_Claim(t *testing.T) {
+	db, mock, outbox := setupOutboxTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	until := now.Add(time.Minute)
+
+	// The events are locked as they are read, skipping any another relay is
+	// claiming, and held until the claim runs out
+	mock.ExpectBegin()
+	mock.ExpectQuery("NOT EXISTS \\(.*earlier.aggregate_id = e.aggregate_id.*\\)\\s+ORDER BY e.occurred_at ASC, e.id ASC\\s+LIMIT \\?\\s+FOR UPDATE SKIP LOCKED").
+		WithArgs(now, now, 2).
+		WillReturnRows(sqlmock.NewRows(outboxColumns).
+			AddRow("event-1", "transaction.created", "txn-1", []byte(`{"amount":"100"}`), now, 0).
+			AddRow("event-2", "direct-user.created", "user-1", []byte(`{"name":"John"}`), now.Add(time.Minute), 3))
+	mock.ExpectExec("UPDATE outbox_events SET claimed_until = \\? WHERE id IN \\(\\?, \\?\\)").
+		WithArgs(until, "event-1", "event-2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	events, err := outbox.Claim(context.Background(), now, until, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "event-1", events[0].Event.ID)
+	assert.Equal(t, domain.EventTransactionCreated, events[0].Event.Type)
+	assert.JSONEq(t, `{"amount":"100"}`, string(events[0].Event.Payload))
+	assert.Equal(t, domain.EventDirectUserCreated, events[1].Event.Type)
+	assert.Equal(t, 3, events[1].Attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutbox_Claim_NothingDue(t *testing.T) {
+	db, mock, outbox := setupOutboxTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM outbox_events e").
+		WithArgs(now, now, 10).
+		WillReturnRows(sqlmock.NewRows(outboxColumns))
+	mock.ExpectCommit()
+
+	events, err := outbox.Claim(context.Background(), now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutbox_MarkPublishedAndRecordFailure(t *testing.T) {
+	db, mock, outbox := setupOutboxTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	retryAt := now.Add(5 * time.Second)
+	mock.ExpectExec("SET attempts = attempts \\+ 1, last_error = \\?, next_attempt_at = \\?, claimed_until = NULL\\s+WHERE id = \\?").
+		WithArgs("webhook unavailable", retryAt, "event-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox_events SET published_at = \\? WHERE id = \\?").
+		WithArgs(now, "event-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox_events SET published_at = \\? WHERE id = \\?").
+		WithArgs(now, "missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SET attempts = attempts \\+ 1, last_error = \\?, dead_lettered_at = \\?, claimed_until = NULL\\s+WHERE id = \\?").
+		WithArgs("rejected", now, "event-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, outbox.RecordFailure(context.Background(), "event-1", "webhook unavailable", retryAt))
+	assert.NoError(t, outbox.MarkPublished(context.Background(), "event-1", now))
+	assert.ErrorIs(t, outbox.MarkPublished(context.Background(), "missing", now), domain.ErrEventNotFound)
+	assert.NoError(t, outbox.DeadLetter(context.Background(), "event-2", "rejected", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
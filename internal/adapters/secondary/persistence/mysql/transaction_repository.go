@@ -1,16 +1,22 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/output"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
-// TransactionRepository implements the transaction repository interface
+// TransactionRepository implements the transaction repository interface. Rows
+// are only ever inserted; the one update it makes records the units allocated
+// to a pending transaction.
 type TransactionRepository struct {
 	db *sql.DB
 }
@@ -21,111 +27,469 @@ func NewTransactionRepository(db *sql.DB) output.TransactionRepository {
 }
 
 // Save persists a transaction to the database
-func (r *TransactionRepository) Save(transaction *domain.Transaction) error {
+func (r *TransactionRepository) Save(ctx context.Context, transaction *domain.Transaction) error {
 	if transaction.ID == "" {
 		transaction.ID = uuid.New().String()
 	}
 
-	query := `
-		INSERT INTO transactions (id, user_id, amount, fund_name, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`
+	return insertTransaction(ctx, conn(ctx, r.db), transaction)
+}
 
-	now := time.Now()
-	_, err := r.db.Exec(query,
-		transaction.ID,
-		transaction.UserID,
-		transaction.Amount,
-		transaction.FundName,
-		now,
-		now,
-	)
+// SaveDebit persists a debit transaction only if the user's balance in the fund covers it.
+// The user's row is locked for the duration of the check so concurrent debits are serialised.
+func (r *TransactionRepository) SaveDebit(ctx context.Context, transaction *domain.Transaction) error {
+	if transaction.ID == "" {
+		transaction.ID = uuid.New().String()
+	}
+
+	return r.saveChecked(ctx, transaction.UserID, transaction)
+}
+
+// SaveDeposit persists a deposit only if it keeps the user's deposits in its tax year
+// within limit. The user's row is locked for the duration of the check so concurrent
+// deposits are serialised, and the deposits are summed with a locking read so the
+// check sees those committed while it waited for the lock.
+func (r *TransactionRepository) SaveDeposit(ctx context.Context, transaction *domain.Transaction, limit decimal.Decimal) error {
+	if transaction.ID == "" {
+		transaction.ID = uuid.New().String()
+	}
+
+	return inTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockUser(ctx, tx, transaction.UserID); err != nil {
+			return err
+		}
+
+		taxYear := domain.TaxYearFor(transaction.CreatedAt)
+		used, err := sumDeposits(ctx, tx, transaction.UserID, taxYear.Start(), taxYear.End(), true)
+		if err != nil {
+			return err
+		}
+		if used.Add(transaction.Amount).GreaterThan(limit) {
+			return &domain.AllowanceExceededError{
+				TaxYear:   taxYear,
+				Limit:     limit,
+				Used:      used,
+				Requested: transaction.Amount,
+			}
+		}
+
+		return insertTransaction(ctx, tx, transaction)
+	})
+}
+
+// SaveReversal persists a reversal and, if not nil, the entry correcting the reversed
+// transaction in a single database transaction, checking the balance for each debit
+func (r *TransactionRepository) SaveReversal(ctx context.Context, reversal, correction *domain.Transaction) error {
+	transactions := []*domain.Transaction{reversal}
+	if correction != nil {
+		transactions = append(transactions, correction)
+	}
+
+	err := r.saveChecked(ctx, reversal.UserID, transactions...)
+	if isDuplicateKey(err) {
+		return domain.ErrAlreadyReversed
+	}
+	return err
+}
+
+// saveChecked inserts transactions in order while holding a lock on the user's row,
+// in the caller's unit of work if there is one. Each debit must be covered by
+// the balance left by the entries before it.
+func (r *TransactionRepository) saveChecked(ctx context.Context, userID string, transactions ...*domain.Transaction) error {
+	return inTx(ctx, r.db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := lockUser(ctx, tx, userID); err != nil {
+			return err
+		}
 
+		for _, transaction := range transactions {
+			if transaction.IsDebit() {
+				available, err := balance(ctx, tx, transaction.UserID, transaction.FundName, true)
+				if err != nil {
+					return err
+				}
+
+				if transaction.Amount.GreaterThan(available) {
+					return &domain.InsufficientBalanceError{
+						FundName:  transaction.FundName,
+						Available: available,
+						Requested: transaction.Amount,
+					}
+				}
+			}
+
+			if err := insertTransaction(ctx, tx, transaction); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// lockUser locks the user's row until tx ends, serialising changes to their
+// account, returning domain.ErrUserNotFound if there is no such user
+func lockUser(ctx context.Context, tx *sql.Tx, userID string) error {
+	var lockedID string
+	err := tx.QueryRowContext(ctx, `SELECT id FROM direct_users WHERE id = ? FOR UPDATE`, userID).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return domain.ErrUserNotFound
+	}
 	return err
 }
 
-// FindByID retrieves a transaction by its ID
-func (r *TransactionRepository) FindByID(id string) (*domain.Transaction, error) {
+// Balance returns a user's available balance in a fund: credits less debits
+func (r *TransactionRepository) Balance(ctx context.Context, userID string, fundName domain.FundName) (decimal.Decimal, error) {
+	return balance(ctx, conn(ctx, r.db), userID, fundName, false)
+}
+
+// FindByID retrieves a transaction by its ID, returning domain.ErrTransactionNotFound if there is none
+func (r *TransactionRepository) FindByID(ctx context.Context, id string) (*domain.Transaction, error) {
 	query := `
-		SELECT id, user_id, amount, fund_name
+		SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at
 		FROM transactions
 		WHERE id = ?
 	`
 
-	var transaction domain.Transaction
-	err := r.db.QueryRow(query, id).Scan(
-		&transaction.ID,
-		&transaction.UserID,
-		&transaction.Amount,
-		&transaction.FundName,
-	)
-
+	transaction, err := scanTransaction(conn(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, domain.ErrTransactionNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &transaction, nil
+	return transaction, nil
 }
 
 // FindByUserID retrieves all transactions for a user
-func (r *TransactionRepository) FindByUserID(userID string) ([]*domain.Transaction, error) {
+func (r *TransactionRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, user_id, amount, fund_name
+		SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at
 		FROM transactions
 		WHERE user_id = ?
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	return r.queryTransactions(ctx, query, userID)
+}
+
+// FindPage retrieves the page of a user's transactions the query selects.
+// The page is read past the cursor along idx_transactions_history, with one
+// row more than the limit to tell whether another page follows.
+func (r *TransactionRepository) FindPage(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error) {
+	filter := "user_id = ?"
+	args := []interface{}{query.UserID}
+	if !query.From.IsZero() {
+		filter += " AND created_at >= ?"
+		args = append(args, query.From)
+	}
+	if !query.To.IsZero() {
+		filter += " AND created_at < ?"
+		args = append(args, query.To)
+	}
+	if query.FundName != "" {
+		filter += " AND fund_name = ?"
+		args = append(args, query.FundName)
+	}
+	if query.Type != "" {
+		filter += " AND type = ?"
+		args = append(args, query.Type)
+	}
+
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM transactions WHERE "+filter, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	direction, past := "ASC", ">"
+	if query.Sort == domain.SortNewestFirst {
+		direction, past = "DESC", "<"
+	}
+	if query.After != nil {
+		filter += " AND (created_at " + past + " ? OR (created_at = ? AND id " + past + " ?))"
+		args = append(args, query.After.CreatedAt, query.After.CreatedAt, query.After.ID)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at
+		FROM transactions
+		WHERE `+filter+`
+		ORDER BY created_at `+direction+`, id `+direction+`
+		LIMIT ?
+	`, append(args, query.Limit+1)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var transactions []*domain.Transaction
+	page := &domain.TransactionPage{Transactions: []*domain.Transaction{}, Total: total}
 	for rows.Next() {
-		var transaction domain.Transaction
-
-		err := rows.Scan(
-			&transaction.ID,
-			&transaction.UserID,
-			&transaction.Amount,
-			&transaction.FundName,
-		)
+		if len(page.Transactions) == query.Limit {
+			last := page.Transactions[len(page.Transactions)-1]
+			page.Next = &domain.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+			break
+		}
+
+		transaction, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
+		page.Transactions = append(page.Transactions, transaction)
+	}
+
+	return page, rows.Err()
+}
 
-		transactions = append(transactions, &transaction)
+// FindReversal retrieves the entry reversing a transaction, nil if it has not been reversed
+func (r *TransactionRepository) FindReversal(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	query := `
+		SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at
+		FROM transactions
+		WHERE reversal_of = ?
+	`
+
+	transaction, err := scanTransaction(conn(ctx, r.db).QueryRowContext(ctx, query, transactionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// FindUnpriced retrieves pending transactions in a fund placed before the given valuation point
+func (r *TransactionRepository) FindUnpriced(ctx context.Context, fundName domain.FundName, placedBefore time.Time) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at
+		FROM transactions
+		WHERE fund_name = ? AND valuation_date IS NULL AND created_at < ?
+		ORDER BY created_at
+	`
 
-	return transactions, nil
+	return r.queryTransactions(ctx, query, fundName, placedBefore)
 }
 
-// Update modifies an existing transaction
-func (r *TransactionRepository) Update(transaction *domain.Transaction) error {
+// AllocateUnits records the units, unit price and valuation date of a pending transaction
+func (r *TransactionRepository) AllocateUnits(ctx context.Context, transaction *domain.Transaction) error {
+	if !transaction.IsPriced() {
+		return errors.New("transaction has not been priced")
+	}
+
+	// Only a pending transaction may be priced, so an allocation is never overwritten
 	query := `
 		UPDATE transactions
-		SET amount = ?, fund_name = ?, updated_at = ?
-		WHERE id = ?
+		SET units = ?, unit_price = ?, valuation_date = ?, updated_at = ?
+		WHERE id = ? AND valuation_date IS NULL
 	`
 
-	_, err := r.db.Exec(query,
-		transaction.Amount,
-		transaction.FundName,
-		time.Now(),
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		transaction.Units,
+		transaction.UnitPrice,
+		*transaction.ValuationDate,
+		transaction.UpdatedAt,
 		transaction.ID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.NewConflictError("transaction not found or already priced")
+	}
+
+	return nil
+}
+
+// SumDeposits totals a user's deposits made in the half-open interval [from, to),
+// leaving out deposits that have been reversed
+func (r *TransactionRepository) SumDeposits(ctx context.Context, userID string, from, to time.Time) (decimal.Decimal, error) {
+	return sumDeposits(ctx, conn(ctx, r.db), userID, from, to, false)
+}
+
+// sumDeposits totals a user's unreversed deposits in [from, to). A locking read
+// sees the latest committed rows rather than the transaction's snapshot.
+func sumDeposits(ctx context.Context, db dbConn, userID string, from, to time.Time, locking bool) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(d.amount), 0)
+		FROM transactions d
+		WHERE d.user_id = ? AND d.type = 'deposit' AND d.reversal_of IS NULL
+			AND d.created_at >= ? AND d.created_at < ?
+			AND NOT EXISTS (SELECT 1 FROM transactions r WHERE r.reversal_of = d.id)
+	`
+	if locking {
+		query = `
+		SELECT COALESCE(SUM(d.amount), 0)
+		FROM transactions d
+		WHERE d.user_id = ? AND d.type = 'deposit' AND d.reversal_of IS NULL
+			AND d.created_at >= ? AND d.created_at < ?
+			AND NOT EXISTS (SELECT 1 FROM transactions r WHERE r.reversal_of = d.id FOR SHARE)
+		FOR SHARE
+	`
+	}
+
+	var total decimal.Decimal
+	if err := db.QueryRowContext(ctx, query, userID, from, to).Scan(&total); err != nil {
+		return decimal.Zero, err
+	}
+
+	return total, nil
+}
+
+// queryTransactions runs a query returning transaction rows
+func (r *TransactionRepository) queryTransactions(ctx context.Context, query string, args ...interface{}) ([]*domain.Transaction, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
 }
 
-// Delete removes a transaction from the database
-func (r *TransactionRepository) Delete(id string) error {
-	query := `DELETE FROM transactions WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+// dbConn is satisfied by both *sql.DB and *sql.Tx
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// insertTransaction writes a new transaction row. Pricing fields are only
+// written for a transaction that is already priced, such as the reversal of a
+// priced transaction.
+func insertTransaction(ctx context.Context, db dbConn, transaction *domain.Transaction) error {
+	query := `
+		INSERT INTO transactions (id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var units, unitPrice decimal.NullDecimal
+	var valuationDate sql.NullTime
+	if transaction.IsPriced() {
+		units = decimal.NewNullDecimal(transaction.Units)
+		unitPrice = decimal.NewNullDecimal(transaction.UnitPrice)
+		valuationDate = sql.NullTime{Time: *transaction.ValuationDate, Valid: true}
+	}
+
+	_, err := db.ExecContext(ctx, query,
+		transaction.ID,
+		transaction.UserID,
+		transaction.Type,
+		transaction.Amount,
+		transaction.FundName,
+		units,
+		unitPrice,
+		valuationDate,
+		nullString(transaction.ReversalOf),
+		nullString(transaction.Reason),
+		nullString(transaction.Actor),
+		transaction.CreatedAt,
+		transaction.UpdatedAt,
+	)
 	return err
-} 
\ No newline at end of file
+}
+
+// nullString maps an empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// isDuplicateKey reports whether err is a MySQL unique key violation
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// isNoSuchTable reports whether err is a MySQL error for a table that does not exist
+func isNoSuchTable(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1146
+}
+
+// isReferenced reports whether err is a MySQL foreign key violation from
+// removing a row other rows still reference
+func isReferenced(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1451
+}
+
+// balance sums a user's credits less debits in a fund. A reversal has the
+// opposite effect to the type it carries. A locking read sees the latest
+// committed rows rather than the transaction's snapshot, which may predate
+// the lock on the user's row.
+func balance(ctx context.Context, db dbConn, userID string, fundName domain.FundName, locking bool) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(CASE WHEN type IN ('withdrawal', 'fee', 'transfer-out') XOR reversal_of IS NOT NULL THEN -amount ELSE amount END), 0)
+		FROM transactions
+		WHERE user_id = ? AND fund_name = ?
+	`
+	if locking {
+		query += "FOR SHARE\n"
+	}
+
+	var total decimal.Decimal
+	if err := db.QueryRowContext(ctx, query, userID, fundName).Scan(&total); err != nil {
+		return decimal.Zero, err
+	}
+
+	return total, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTransaction scans a transaction row, leaving pricing fields empty for pending transactions
+func scanTransaction(row rowScanner) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	var units, unitPrice decimal.NullDecimal
+	var valuationDate sql.NullTime
+	var reversalOf, reason, actor sql.NullString
+
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.UserID,
+		&transaction.Type,
+		&transaction.Amount,
+		&transaction.FundName,
+		&units,
+		&unitPrice,
+		&valuationDate,
+		&reversalOf,
+		&reason,
+		&actor,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.ReversalOf = reversalOf.String
+	transaction.Reason = reason.String
+	transaction.Actor = actor.String
+
+	if valuationDate.Valid {
+		transaction.Units = units.Decimal
+		transaction.UnitPrice = unitPrice.Decimal
+		transaction.ValuationDate = &valuationDate.Time
+	}
+
+	return &transaction, nil
+}
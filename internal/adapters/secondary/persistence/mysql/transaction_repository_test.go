@@ -1,16 +1,24 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"cushon/internal/core/domain"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
+var transactionColumns = []string{"id", "user_id", "type", "amount", "fund_name", "units", "unit_price", "valuation_date", "reversal_of", "reason", "actor", "created_at", "updated_at"}
+
+// recordedAt is when the scripted transaction rows were recorded
+var recordedAt = time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+
 func setupTransactionTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *TransactionRepository) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -26,21 +34,154 @@ func TestTransactionRepository_Save(t *testing.T) {
 	defer db.Close()
 
 	amount := decimal.NewFromFloat(25000.0)
-	transaction := domain.NewTransaction("user123", amount, domain.FundName("Cushon Equities Fund"))
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, amount, domain.FundName("Cushon Equities Fund"), time.Now())
 	expectedID := transaction.ID
 	expectedUserID := transaction.UserID
 	expectedAmount := transaction.Amount.String()
 	expectedFundName := string(transaction.FundName)
 
 	mock.ExpectExec("INSERT INTO transactions").
-		WithArgs(expectedID, expectedUserID, expectedAmount, expectedFundName, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(expectedID, expectedUserID, "deposit", expectedAmount, expectedFundName, nil, nil, nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := repo.Save(context.Background(), transaction)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_SaveDebit(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(600), domain.CushonEquitiesFund, time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type IN").
+		WithArgs("user123", "Cushon Equities Fund").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("1000.0000"))
+	mock.ExpectExec("INSERT INTO transactions").
+		WithArgs(transaction.ID, "user123", "withdrawal", "600", "Cushon Equities Fund", nil, nil, nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.SaveDebit(context.Background(), transaction)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_SaveDebit_LocksTheBalance(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(600), domain.CushonEquitiesFund, time.Now())
+
+	// A read earlier in the unit of work fixes its snapshot before the user's
+	// row is locked, so the balance is a locking read to see debits committed
+	// while it waited
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type IN .+ WHERE user_id = \\? AND fund_name = \\?\\s+FOR SHARE").
+		WithArgs("user123", "Cushon Equities Fund").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("1000.0000"))
+	mock.ExpectExec("INSERT INTO transactions").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err := repo.Save(transaction)
+	err := NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		return repo.SaveDebit(ctx, transaction)
+	})
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTransactionRepository_SaveDebit_InsufficientBalance(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(1500), domain.CushonEquitiesFund, time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type IN").
+		WithArgs("user123", "Cushon Equities Fund").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("1000.0000"))
+	mock.ExpectRollback()
+
+	err := repo.SaveDebit(context.Background(), transaction)
+	var balanceErr *domain.InsufficientBalanceError
+	assert.ErrorAs(t, err, &balanceErr)
+	assert.True(t, decimal.NewFromInt(1000).Equal(balanceErr.Available))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_SaveDeposit(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC)
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(5000), domain.CushonEquitiesFund, createdAt)
+	taxYear := domain.TaxYearFor(createdAt)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(d.amount\\), 0\\).+FOR SHARE\\)\\s+FOR SHARE").
+		WithArgs("user123", taxYear.Start(), taxYear.End()).
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow("15000.0000"))
+	mock.ExpectExec("INSERT INTO transactions").
+		WithArgs(transaction.ID, "user123", "deposit", "5000", "Cushon Equities Fund", nil, nil, nil, nil, nil, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.SaveDeposit(context.Background(), transaction, decimal.NewFromInt(20000))
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_SaveDeposit_AllowanceExceeded(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.RequireFromString("5000.01"), domain.CushonEquitiesFund, time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(d.amount\\), 0\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow("15000.0000"))
+	mock.ExpectRollback()
+
+	err := repo.SaveDeposit(context.Background(), transaction, decimal.NewFromInt(20000))
+	var allowanceErr *domain.AllowanceExceededError
+	assert.ErrorAs(t, err, &allowanceErr)
+	assert.True(t, decimal.NewFromInt(15000).Equal(allowanceErr.Used))
+	assert.Equal(t, domain.TaxYearFor(transaction.CreatedAt), allowanceErr.TaxYear)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_Balance(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type IN").
+		WithArgs("user123", "Cushon Equities Fund").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("400.0000"))
+
+	balance, err := repo.Balance(context.Background(), "user123", domain.CushonEquitiesFund)
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(400).Equal(balance))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTransactionRepository_FindByID(t *testing.T) {
 	db, mock, repo := setupTransactionTestDB(t)
 	defer db.Close()
@@ -50,21 +191,25 @@ func TestTransactionRepository_FindByID(t *testing.T) {
 	expectedAmount, err := decimal.NewFromString("25000.0000")
 	assert.NoError(t, err)
 	expectedFundName := "Cushon Equities Fund"
+	createdAt := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
 
-	rows := sqlmock.NewRows([]string{"id", "user_id", "amount", "fund_name"}).
-		AddRow(expectedID, expectedUserID, expectedAmount.String(), expectedFundName)
+	rows := sqlmock.NewRows(transactionColumns).
+		AddRow(expectedID, expectedUserID, "deposit", expectedAmount.String(), expectedFundName, nil, nil, nil, nil, nil, nil, createdAt, updatedAt)
 
-	mock.ExpectQuery("SELECT id, user_id, amount, fund_name FROM transactions").
+	mock.ExpectQuery("SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at FROM transactions").
 		WithArgs(expectedID).
 		WillReturnRows(rows)
 
-	transaction, err := repo.FindByID(expectedID)
+	transaction, err := repo.FindByID(context.Background(), expectedID)
 	assert.NoError(t, err)
 	assert.NotNil(t, transaction)
 	assert.Equal(t, expectedID, transaction.ID)
 	assert.Equal(t, expectedUserID, transaction.UserID)
 	assert.True(t, expectedAmount.Equal(transaction.Amount))
 	assert.Equal(t, domain.FundName(expectedFundName), transaction.FundName)
+	assert.Equal(t, createdAt, transaction.CreatedAt)
+	assert.Equal(t, updatedAt, transaction.UpdatedAt)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -74,12 +219,12 @@ func TestTransactionRepository_FindByID_NotFound(t *testing.T) {
 
 	expectedID := "non-existent"
 
-	mock.ExpectQuery("SELECT id, user_id, amount, fund_name FROM transactions").
+	mock.ExpectQuery("SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at FROM transactions").
 		WithArgs(expectedID).
 		WillReturnError(sql.ErrNoRows)
 
-	transaction, err := repo.FindByID(expectedID)
-	assert.NoError(t, err)
+	transaction, err := repo.FindByID(context.Background(), expectedID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
 	assert.Nil(t, transaction)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -90,19 +235,104 @@ func TestTransactionRepository_FindByUserID(t *testing.T) {
 
 	expectedUserID := "user123"
 
-	rows := sqlmock.NewRows([]string{"id", "user_id", "amount", "fund_name"}).
-		AddRow("id1", expectedUserID, "25000.0000", "Cushon Equities Fund").
-		AddRow("id2", expectedUserID, "15000.0000", "Cushon Growth Fund")
+	rows := sqlmock.NewRows(transactionColumns).
+		AddRow("id1", expectedUserID, "deposit", "25000.0000", "Cushon Equities Fund", "12500.000000", "2.000000", time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC), nil, nil, nil, recordedAt, recordedAt).
+		AddRow("id2", expectedUserID, "withdrawal", "15000.0000", "Cushon Growth Fund", nil, nil, nil, nil, nil, nil, recordedAt, recordedAt)
 
-	mock.ExpectQuery("SELECT id, user_id, amount, fund_name FROM transactions").
+	mock.ExpectQuery("SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at FROM transactions").
 		WithArgs(expectedUserID).
 		WillReturnRows(rows)
 
-	transactions, err := repo.FindByUserID(expectedUserID)
+	transactions, err := repo.FindByUserID(context.Background(), expectedUserID)
 	assert.NoError(t, err)
 	assert.Len(t, transactions, 2)
 	assert.Equal(t, expectedUserID, transactions[0].UserID)
 	assert.Equal(t, expectedUserID, transactions[1].UserID)
+	assert.True(t, transactions[0].IsPriced())
+	assert.True(t, decimal.NewFromInt(12500).Equal(transactions[0].Units))
+	assert.False(t, transactions[1].IsPriced())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_FindByUserID_DeadlineExceeded(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM transactions").
+		WithArgs("user123").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows(transactionColumns))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := repo.FindByUserID(ctx, "user123")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "the query should be abandoned at the deadline")
+}
+
+func TestTransactionRepository_SaveDebit_CancelledContext(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// No transaction is begun for a request that has already gone away
+	err := repo.SaveDebit(ctx, domain.NewTransaction("user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(100), domain.CushonEquitiesFund, time.Now()))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_FindUnpriced(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	valuationDate := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(transactionColumns).
+		AddRow("id1", "user123", "deposit", "1000.0000", "Cushon Equities Fund", nil, nil, nil, nil, nil, nil, recordedAt, recordedAt)
+
+	mock.ExpectQuery("SELECT (.+) FROM transactions WHERE fund_name = \\? AND valuation_date IS NULL").
+		WithArgs("Cushon Equities Fund", valuationDate).
+		WillReturnRows(rows)
+
+	transactions, err := repo.FindUnpriced(context.Background(), domain.CushonEquitiesFund, valuationDate)
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 1)
+	assert.False(t, transactions[0].IsPriced())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_AllocateUnits(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund, time.Now())
+	price := domain.NewNAVFundPrice("fund-id", time.Now(), decimal.NewFromInt(2))
+	transaction.Allocate(price, time.Now())
+
+	mock.ExpectExec("UPDATE transactions SET units = \\?, unit_price = \\?, valuation_date = \\?").
+		WithArgs(transaction.Units.String(), transaction.UnitPrice.String(), price.ValuationDate, sqlmock.AnyArg(), transaction.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.AllocateUnits(context.Background(), transaction)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_AllocateUnits_AlreadyPriced(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund, time.Now())
+	transaction.Allocate(domain.NewNAVFundPrice("fund-id", time.Now(), decimal.NewFromInt(2)), time.Now())
+
+	mock.ExpectExec("UPDATE transactions SET units").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.AllocateUnits(context.Background(), transaction)
+	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -112,64 +342,150 @@ func TestTransactionRepository_FindByUserID_Empty(t *testing.T) {
 
 	expectedUserID := "user123"
 
-	rows := sqlmock.NewRows([]string{"id", "user_id", "amount", "fund_name"})
+	rows := sqlmock.NewRows(transactionColumns)
 
-	mock.ExpectQuery("SELECT id, user_id, amount, fund_name FROM transactions").
+	mock.ExpectQuery("SELECT id, user_id, type, amount, fund_name, units, unit_price, valuation_date, reversal_of, reason, actor, created_at, updated_at FROM transactions").
 		WithArgs(expectedUserID).
 		WillReturnRows(rows)
 
-	transactions, err := repo.FindByUserID(expectedUserID)
+	transactions, err := repo.FindByUserID(context.Background(), expectedUserID)
 	assert.NoError(t, err)
 	assert.Empty(t, transactions)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestTransactionRepository_Update(t *testing.T) {
+func TestTransactionRepository_SumDeposits(t *testing.T) {
 	db, mock, repo := setupTransactionTestDB(t)
 	defer db.Close()
 
-	amount := decimal.NewFromFloat(25000.0)
-	transaction := domain.NewTransaction("user123", amount, domain.FundName("Cushon Equities Fund"))
-	transaction.FundName = domain.FundName("Cushon Growth Fund")
-	expectedID := transaction.ID
-	expectedAmount := transaction.Amount.String()
-	expectedFundName := string(transaction.FundName)
+	taxYear := domain.TaxYear(2024)
+	rows := sqlmock.NewRows([]string{"total"}).AddRow("12500.5000")
 
-	mock.ExpectExec("UPDATE transactions").
-		WithArgs(expectedAmount, expectedFundName, sqlmock.AnyArg(), expectedID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(d.amount\\), 0\\) FROM transactions d").
+		WithArgs("user123", taxYear.Start(), taxYear.End()).
+		WillReturnRows(rows)
 
-	err := repo.Update(transaction)
+	total, err := repo.SumDeposits(context.Background(), "user123", taxYear.Start(), taxYear.End())
 	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(12500.5).Equal(total))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestTransactionRepository_Delete(t *testing.T) {
+func TestTransactionRepository_SaveReversal(t *testing.T) {
 	db, mock, repo := setupTransactionTestDB(t)
 	defer db.Close()
 
-	expectedID := "test-id"
-
-	mock.ExpectExec("DELETE FROM transactions").
-		WithArgs(expectedID).
+	valuationDate := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	original := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund, time.Now())
+	original.Allocate(domain.NewNAVFundPrice("fund-id", valuationDate, decimal.NewFromInt(2)), time.Now())
+	reversal, _ := original.Reverse("wrong amount", "ops", time.Now())
+	correction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund, time.Now())
+	correction.Reason = "wrong amount"
+	correction.Actor = "ops"
+
+	// The reversal of a deposit is a debit, so it is checked against the balance
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type IN").
+		WithArgs("user123", "Cushon Equities Fund").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("1000.0000"))
+	mock.ExpectExec("INSERT INTO transactions").
+		WithArgs(reversal.ID, "user123", "deposit", "1000", "Cushon Equities Fund", "-500", "2", valuationDate, original.ID, "wrong amount", "ops", sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO transactions").
+		WithArgs(correction.ID, "user123", "deposit", "100", "Cushon Equities Fund", nil, nil, nil, nil, "wrong amount", "ops", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err := repo.Delete(expectedID)
+	err := repo.SaveReversal(context.Background(), reversal, correction)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestTransactionRepository_Delete_NotFound(t *testing.T) {
+func TestTransactionRepository_SaveReversal_AlreadyReversed(t *testing.T) {
 	db, mock, repo := setupTransactionTestDB(t)
 	defer db.Close()
 
-	expectedID := "non-existent"
+	original := domain.NewTransaction("user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(100), domain.CushonEquitiesFund, time.Now())
+	reversal, _ := original.Reverse("entered in error", "ops", time.Now())
 
-	mock.ExpectExec("DELETE FROM transactions").
-		WithArgs(expectedID).
-		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectExec("INSERT INTO transactions").
+		WillReturnError(&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry for key 'uq_transactions_reversal_of'"})
+	mock.ExpectRollback()
+
+	err := repo.SaveReversal(context.Background(), reversal, nil)
+	assert.EqualError(t, err, "transaction already reversed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_FindReversal(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
 
-	err := repo.Delete(expectedID)
+	rows := sqlmock.NewRows(transactionColumns).
+		AddRow("id2", "user123", "deposit", "1000.0000", "Cushon Equities Fund", nil, nil, nil, "id1", "wrong amount", "ops", recordedAt, recordedAt)
+
+	mock.ExpectQuery("SELECT (.+) FROM transactions WHERE reversal_of = \\?").
+		WithArgs("id1").
+		WillReturnRows(rows)
+
+	reversal, err := repo.FindReversal(context.Background(), "id1")
+	assert.NoError(t, err)
+	assert.NotNil(t, reversal)
+	assert.Equal(t, "id1", reversal.ReversalOf)
+	assert.True(t, reversal.IsDebit())
+	assert.Equal(t, "ops", reversal.Actor)
+
+	mock.ExpectQuery("SELECT (.+) FROM transactions WHERE reversal_of = \\?").
+		WithArgs("id2").
+		WillReturnError(sql.ErrNoRows)
+
+	reversal, err = repo.FindReversal(context.Background(), "id2")
+	assert.NoError(t, err)
+	assert.Nil(t, reversal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRepository_FindPage(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+
+	from := time.Date(2024, 4, 6, 0, 0, 0, 0, time.UTC)
+	after := &domain.TransactionCursor{CreatedAt: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), ID: "txn-3"}
+	first := time.Date(2024, 4, 30, 12, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 4, 20, 12, 0, 0, 0, time.UTC)
+
+	// The total ignores the cursor; the page is read past it, one row over the limit
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM transactions WHERE user_id = \\? AND created_at >= \\? AND type = \\?").
+		WithArgs("user123", from, "deposit").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery("WHERE user_id = \\? AND created_at >= \\? AND type = \\? AND \\(created_at < \\? OR \\(created_at = \\? AND id < \\?\\)\\)\\s+ORDER BY created_at DESC, id DESC\\s+LIMIT \\?").
+		WithArgs("user123", from, "deposit", after.CreatedAt, after.CreatedAt, after.ID, 3).
+		WillReturnRows(sqlmock.NewRows(transactionColumns).
+			AddRow("txn-2", "user123", "deposit", "100", "Cushon Equities Fund", nil, nil, nil, nil, nil, nil, first, first).
+			AddRow("txn-1", "user123", "deposit", "200", "Cushon Equities Fund", nil, nil, nil, nil, nil, nil, second, second).
+			AddRow("txn-0", "user123", "deposit", "300", "Cushon Equities Fund", nil, nil, nil, nil, nil, nil, second, second))
+
+	page, err := repo.FindPage(context.Background(), domain.TransactionQuery{
+		UserID: "user123",
+		From:   from,
+		Type:   domain.TransactionTypeDeposit,
+		Sort:   domain.SortNewestFirst,
+		Limit:  2,
+		After:  after,
+	})
 	assert.NoError(t, err)
+	assert.Equal(t, 5, page.Total)
+	if assert.Len(t, page.Transactions, 2) {
+		assert.Equal(t, "txn-2", page.Transactions[0].ID)
+		assert.Equal(t, "txn-1", page.Transactions[1].ID)
+	}
+	assert.Equal(t, &domain.TransactionCursor{CreatedAt: second, ID: "txn-1"}, page.Next)
 	assert.NoError(t, mock.ExpectationsWereMet())
-} 
\ No newline at end of file
+}
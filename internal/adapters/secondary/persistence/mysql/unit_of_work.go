@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"cushon/internal/core/ports/output"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+const (
+	// maxTxAttempts is how many times a transaction is tried when it deadlocks
+	maxTxAttempts = 3
+	// txRetryBackoff is the wait before the first retry, growing with each attempt
+	txRetryBackoff = 20 * time.Millisecond
+)
+
+// txKey is the context key for the transaction a unit of work is running in
+type txKey struct{}
+
+// UnitOfWork implements the output.UnitOfWork interface with a database
+// transaction. Repositories given a context carrying the transaction run
+// their queries in it.
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a new MySQL unit of work
+func NewUnitOfWork(db *sql.DB) output.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction, committing it if fn succeeds and rolling it
+// back otherwise. A transaction that deadlocks or times out waiting for a
+// lock is rolled back and fn run again in a new one, up to maxTxAttempts times.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTx(ctx, u.db, func(ctx context.Context, _ *sql.Tx) error {
+		return fn(ctx)
+	})
+}
+
+// conn returns the transaction ctx carries, or db if it carries none, so a
+// repository call takes part in any unit of work it is made in
+func conn(ctx context.Context, db *sql.DB) dbConn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx runs fn in the transaction ctx carries. If it carries none, fn runs in
+// a new transaction, retried if it deadlocks and committed if fn succeeds.
+func inTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx, tx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, fn)
+		if !isRetryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		// Back off with jitter so the transactions that collided don't collide again
+		wait := time.Duration(attempt)*txRetryBackoff + rand.N(txRetryBackoff)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// runTx runs fn in a new transaction, rolling it back if fn fails or panics
+func runTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			tx.Rollback()
+			panic(recovered)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx), tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isRetryable reports whether err is a MySQL deadlock or lock wait timeout,
+// after which the transaction can be run again from the start
+func isRetryable(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && (mysqlErr.Number == 1213 || mysqlErr.Number == 1205)
+}
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+	unitOfWork := NewUnitOfWork(db)
+
+	first := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund, time.Now())
+	second := domain.NewTransaction("user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(40), domain.CushonEquitiesFund, time.Now())
+
+	// Both saves, and the debit's own transaction, run in one database transaction
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transactions").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT id FROM direct_users WHERE id = \\? FOR UPDATE").
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user123"))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(CASE WHEN type IN").
+		WithArgs("user123", "Cushon Equities Fund").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("100"))
+	mock.ExpectExec("INSERT INTO transactions").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		if err := repo.Save(ctx, first); err != nil {
+			return err
+		}
+		return repo.SaveDebit(ctx, second)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+	unitOfWork := NewUnitOfWork(db)
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund, time.Now())
+	failure := errors.New("failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transactions").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		if err := repo.Save(ctx, transaction); err != nil {
+			return err
+		}
+		// A nested unit of work joins the outer one rather than beginning another
+		return unitOfWork.Do(ctx, func(ctx context.Context) error {
+			return failure
+		})
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_RetriesDeadlock(t *testing.T) {
+	db, mock, repo := setupTransactionTestDB(t)
+	defer db.Close()
+	unitOfWork := NewUnitOfWork(db)
+
+	transaction := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund, time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transactions").
+		WillReturnError(&mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transactions").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	attempts := 0
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return repo.Save(ctx, transaction)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnitOfWork_GivesUpAfterMaxAttempts(t *testing.T) {
+	db, mock, _ := setupTransactionTestDB(t)
+	defer db.Close()
+	unitOfWork := NewUnitOfWork(db)
+
+	lockWait := &mysqldriver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+	for i := 0; i < maxTxAttempts; i++ {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	attempts := 0
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return lockWait
+	})
+	assert.ErrorIs(t, err, lockWait)
+	assert.Equal(t, maxTxAttempts, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// File implements the output.EventPublisher interface by appending each
+// event to a file as a line of JSON. The file is synced before an event is
+// reported published, so a crash cannot lose it.
+type File struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFile creates a publisher appending events to the file at path, created if it does not exist
+func NewFile(path string) output.EventPublisher {
+	return &File{
+		path: path,
+	}
+}
+
+// Publish appends the event to the file
+func (p *File) Publish(ctx context.Context, event *domain.Event) error {
+	encoded, err := encode(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	file, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(encoded, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
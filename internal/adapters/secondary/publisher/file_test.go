@@ -0,0 +1,35 @@
+package publisher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	publisher := NewFile(path)
+
+	// Each event is appended as a line
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(t)))
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(t)))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.JSONEq(t, testMessage, line)
+	}
+}
+
+func TestFile_Publish_Fails(t *testing.T) {
+	publisher := NewFile(filepath.Join(t.TempDir(), "missing", "events.jsonl"))
+
+	assert.Error(t, publisher.Publish(context.Background(), testEvent(t)))
+}
@@ -0,0 +1,33 @@
+package publisher
+
+import (
+	"context"
+	"log"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// Log implements the output.EventPublisher interface by writing each event
+// to a logger, for development and for running without a downstream system
+type Log struct {
+	logger *log.Logger
+}
+
+// NewLog creates a publisher writing events to logger
+func NewLog(logger *log.Logger) output.EventPublisher {
+	return &Log{
+		logger: logger,
+	}
+}
+
+// Publish writes the event to the log
+func (p *Log) Publish(ctx context.Context, event *domain.Event) error {
+	encoded, err := encode(event)
+	if err != nil {
+		return err
+	}
+
+	p.logger.Printf("Event %s: %s", event.Type, encoded)
+	return nil
+}
@@ -0,0 +1,23 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewLog(log.New(&buf, "", 0))
+
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(t)))
+
+	line, found := strings.CutPrefix(strings.TrimSpace(buf.String()), "Event direct-user.created: ")
+	require.True(t, found, "unexpected log line %q", buf.String())
+	assert.JSONEq(t, testMessage, line)
+}
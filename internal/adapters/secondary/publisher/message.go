@@ -0,0 +1,31 @@
+// Package publisher implements the output.EventPublisher port: to the
+// process log, to an HTTP webhook, or to a file of JSON lines. Every
+// publisher writes an event in the same JSON form.
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	"cushon/internal/core/domain"
+)
+
+// message is an event as the publishers write it
+type message struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// encode writes an event as JSON
+func encode(event *domain.Event) ([]byte, error) {
+	return json.Marshal(message{
+		ID:          event.ID,
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID,
+		OccurredAt:  event.OccurredAt.UTC(),
+		Payload:     event.Payload,
+	})
+}
@@ -0,0 +1,37 @@
+package publisher
+
+import (
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testEvent returns a direct user created event with fixed IDs
+func testEvent(t *testing.T) *domain.Event {
+	t.Helper()
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	user := domain.NewDirectUser("John Doe", now)
+	user.ID = "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e"
+
+	event, err := domain.NewDirectUserCreatedEvent(user, now)
+	require.NoError(t, err)
+	event.ID = "4c2b1a09-8f7e-4d6c-b5a4-3f2e1d0c9b8a"
+	return event
+}
+
+// testMessage is testEvent as the publishers write it
+const testMessage = `{
+	"id": "4c2b1a09-8f7e-4d6c-b5a4-3f2e1d0c9b8a",
+	"type": "direct-user.created",
+	"aggregate_id": "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+	"occurred_at": "2025-01-02T12:00:00Z",
+	"payload": {
+		"id": "c3ac8a0a-e6f5-451b-a4c6-37e023fe209e",
+		"name": "John Doe",
+		"created_at": "2025-01-02T12:00:00Z",
+		"updated_at": "2025-01-02T12:00:00Z"
+	}
+}`
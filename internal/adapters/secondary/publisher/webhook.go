@@ -0,0 +1,66 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// Headers sent with each event, so a receiver can route and deduplicate
+// events without reading the body
+const (
+	EventIDHeader   = "X-Event-ID"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// Webhook implements the output.EventPublisher interface by POSTing each
+// event as JSON to a URL. An event is published once the receiver responds
+// with a 2xx status; any other response, or none within the timeout, fails
+// the event so it is sent again.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a publisher POSTing events to url, giving up on a request after timeout
+func NewWebhook(url string, timeout time.Duration) output.EventPublisher {
+	return &Webhook{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish sends the event to the webhook
+func (p *Webhook) Publish(ctx context.Context, event *domain.Event) error {
+	encoded, err := encode(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, event.ID)
+	req.Header.Set(EventTypeHeader, string(event.Type))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package publisher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Publish(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhook(server.URL, time.Second)
+	require.NoError(t, publisher.Publish(context.Background(), testEvent(t)))
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "4c2b1a09-8f7e-4d6c-b5a4-3f2e1d0c9b8a", received.Header.Get(EventIDHeader))
+	assert.Equal(t, "direct-user.created", received.Header.Get(EventTypeHeader))
+	assert.JSONEq(t, testMessage, string(body))
+}
+
+func TestWebhook_Publish_Fails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// A response other than 2xx fails the event
+	err := NewWebhook(server.URL, time.Second).Publish(context.Background(), testEvent(t))
+	assert.ErrorContains(t, err, "503")
+
+	// So does no response within the timeout
+	err = NewWebhook(server.URL+"/slow", 50*time.Millisecond).Publish(context.Background(), testEvent(t))
+	assert.Error(t, err)
+}
@@ -0,0 +1,587 @@
+// Package config loads the settings the binaries need to run: where data is
+// stored, how to reach MySQL, and how the HTTP server listens, how long it
+// waits on clients and which origins it serves, how requests are
+// authenticated, the ISA allowance for each tax year, how long idempotency
+// keys are kept, where domain events are published, and how readiness is
+// checked.
+//
+// Settings come from, in increasing order of precedence, the defaults, an
+// optional YAML or TOML file, and environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cushon/internal/adapters/primary/http"
+	"cushon/internal/adapters/secondary/persistence/mysql"
+	"cushon/internal/core/domain"
+	"cushon/internal/core/services"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// Storage backends the API can keep its data in
+const (
+	StorageMySQL  = "mysql"
+	StorageMemory = "memory"
+)
+
+// Publishers domain events can be delivered through
+const (
+	PublisherLog     = "log"
+	PublisherWebhook = "webhook"
+	PublisherFile    = "file"
+)
+
+// Config holds every setting of the API server
+type Config struct {
+	// Storage is where data is kept, StorageMySQL or StorageMemory
+	Storage  string         `yaml:"storage" toml:"storage"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	CORS     CORSConfig     `yaml:"cors" toml:"cors"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Health   HealthConfig   `yaml:"health" toml:"health"`
+
+	Allowance   AllowanceConfig   `yaml:"allowance" toml:"allowance"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+	Events      EventsConfig      `yaml:"events" toml:"events"`
+}
+
+// DatabaseConfig holds the MySQL connection settings
+type DatabaseConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`
+	// MigrateOnStart applies pending migrations when the API server starts
+	MigrateOnStart bool `yaml:"migrate_on_start" toml:"migrate_on_start"`
+}
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	// Addr is the TCP address the server listens on, such as ":8080"
+	Addr string `yaml:"addr" toml:"addr"`
+	// ReadTimeout limits reading a whole request, ReadHeaderTimeout just its headers
+	ReadTimeout       Duration `yaml:"read_timeout" toml:"read_timeout"`
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	// WriteTimeout limits handling a request and writing its response
+	WriteTimeout Duration `yaml:"write_timeout" toml:"write_timeout"`
+	// IdleTimeout limits how long a keep-alive connection waits for the next request
+	IdleTimeout Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// RequestTimeout is the deadline for the database work of each request,
+	// shorter than WriteTimeout so a timed out request still gets a response
+	RequestTimeout Duration `yaml:"request_timeout" toml:"request_timeout"`
+	// ShutdownDelay is how long the server keeps serving, while reporting not
+	// ready, once it is asked to stop
+	ShutdownDelay Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	// ShutdownTimeout limits how long in-flight requests may run once the
+	// server stops accepting connections
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+// HealthConfig holds the readiness check settings
+type HealthConfig struct {
+	// CheckTimeout limits how long each readiness check may take
+	CheckTimeout Duration `yaml:"check_timeout" toml:"check_timeout"`
+	// PriceMaxAge is how old an open fund's latest price may be before the
+	// instance reports itself degraded
+	PriceMaxAge Duration `yaml:"price_max_age" toml:"price_max_age"`
+}
+
+// Duration is a time.Duration written as a string such as "30s" or "1m30s"
+type Duration time.Duration
+
+// UnmarshalText parses a duration from a config file
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalText writes the duration as it is read
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// CORSConfig holds the cross-origin resource sharing settings
+type CORSConfig struct {
+	// AllowOrigins lists the origins browsers may call the API from
+	AllowOrigins []string `yaml:"allow_origins" toml:"allow_origins"`
+}
+
+// AuthConfig holds the settings for authenticating requests with bearer JWTs
+type AuthConfig struct {
+	// JWKSFile is the path of the JSON Web Key Set holding the keys tokens are signed with
+	JWKSFile string `yaml:"jwks_file" toml:"jwks_file"`
+	// Issuer and Audience, if set, must match a token's iss and aud claims
+	Issuer   string `yaml:"issuer" toml:"issuer"`
+	Audience string `yaml:"audience" toml:"audience"`
+	// Leeway allows for clock skew between the token issuer and the server
+	Leeway Duration `yaml:"leeway" toml:"leeway"`
+}
+
+// IdempotencyConfig holds the settings for requests sent with an Idempotency-Key header
+type IdempotencyConfig struct {
+	// TTL is how long a key's response is replayed for, after which the key may be used again
+	TTL Duration `yaml:"ttl" toml:"ttl"`
+	// PendingTTL is how long a key is held while its request runs, so a
+	// request cut off by the server stopping does not hold it for the whole TTL
+	PendingTTL Duration `yaml:"pending_ttl" toml:"pending_ttl"`
+}
+
+// AllowanceConfig holds the ISA subscription limit for each tax year
+type AllowanceConfig struct {
+	// Default is the limit, in pounds, for any tax year not in Limits
+	Default decimal.Decimal `yaml:"default" toml:"default"`
+	// Limits overrides the default for tax years named like "2024/25"
+	Limits map[string]decimal.Decimal `yaml:"limits" toml:"limits"`
+}
+
+// EventsConfig holds the settings for publishing domain events from the outbox
+type EventsConfig struct {
+	// Publisher is where events are delivered: PublisherLog, PublisherWebhook or PublisherFile
+	Publisher string `yaml:"publisher" toml:"publisher"`
+	// WebhookURL receives each event as a POST when Publisher is PublisherWebhook
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url"`
+	// WebhookTimeout limits how long the webhook may take to accept an event
+	WebhookTimeout Duration `yaml:"webhook_timeout" toml:"webhook_timeout"`
+	// File is appended to when Publisher is PublisherFile
+	File string `yaml:"file" toml:"file"`
+	// RelayInterval is how often the outbox is checked for events to publish
+	RelayInterval Duration `yaml:"relay_interval" toml:"relay_interval"`
+	// BatchSize is how many events are claimed from the outbox at a time
+	BatchSize int `yaml:"batch_size" toml:"batch_size"`
+	// ClaimTTL is how long a relay holds the events it claims before another
+	// instance may take them, so it must outlast publishing a batch
+	ClaimTTL Duration `yaml:"claim_ttl" toml:"claim_ttl"`
+	// MaxAttempts is how many times an event is tried before it is dead-lettered
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts"`
+	// RetryBackoff is how long an event waits after its first failure,
+	// doubling after each one after that up to MaxRetryBackoff
+	RetryBackoff    Duration `yaml:"retry_backoff" toml:"retry_backoff"`
+	MaxRetryBackoff Duration `yaml:"max_retry_backoff" toml:"max_retry_backoff"`
+}
+
+// Default returns the configuration used for anything not set elsewhere,
+// suitable for running locally against a MySQL on the same machine
+func Default() *Config {
+	return &Config{
+		Storage: StorageMySQL,
+		Database: DatabaseConfig{
+			Host: "localhost",
+			Port: 3306,
+			User: "root",
+			Name: "cushon",
+		},
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ReadTimeout:       Duration(15 * time.Second),
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			WriteTimeout:      Duration(30 * time.Second),
+			IdleTimeout:       Duration(2 * time.Minute),
+			RequestTimeout:    Duration(10 * time.Second),
+			ShutdownTimeout:   Duration(20 * time.Second),
+		},
+		Health: HealthConfig{
+			CheckTimeout: Duration(2 * time.Second),
+			// Long enough to span a bank holiday weekend without a valuation point
+			PriceMaxAge: Duration(96 * time.Hour),
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"http://localhost:3000"},
+		},
+		Auth: AuthConfig{
+			Leeway: Duration(30 * time.Second),
+		},
+		Allowance: AllowanceConfig{
+			Default: domain.DefaultISAAllowance,
+		},
+		Idempotency: IdempotencyConfig{
+			// Long enough for a mobile client to retry after a day offline
+			TTL:        Duration(24 * time.Hour),
+			PendingTTL: Duration(time.Minute),
+		},
+		Events: EventsConfig{
+			Publisher:      PublisherLog,
+			WebhookTimeout: Duration(5 * time.Second),
+			RelayInterval:  Duration(time.Second),
+			BatchSize:      100,
+			ClaimTTL:       Duration(5 * time.Minute),
+			// Retried for about three quarters of an hour before it is given up on
+			MaxAttempts:     10,
+			RetryBackoff:    Duration(5 * time.Second),
+			MaxRetryBackoff: Duration(time.Hour),
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the file at path and the
+// environment, then validates it. If path is empty the CONFIG_FILE
+// environment variable names the file; if that is empty too no file is read.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile overlays the settings in a YAML or TOML file, chosen by its extension
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv overlays the settings given by environment variables
+func (c *Config) loadEnv() error {
+	setString := func(name string, target *string) {
+		if value, ok := os.LookupEnv(name); ok {
+			*target = value
+		}
+	}
+
+	setString("STORAGE", &c.Storage)
+	setString("DB_HOST", &c.Database.Host)
+	setString("DB_USER", &c.Database.User)
+	setString("DB_PASSWORD", &c.Database.Password)
+	setString("DB_NAME", &c.Database.Name)
+	setString("HTTP_ADDR", &c.Server.Addr)
+	setString("AUTH_JWKS_FILE", &c.Auth.JWKSFile)
+	setString("AUTH_ISSUER", &c.Auth.Issuer)
+	setString("AUTH_AUDIENCE", &c.Auth.Audience)
+	setString("EVENTS_PUBLISHER", &c.Events.Publisher)
+	setString("EVENTS_WEBHOOK_URL", &c.Events.WebhookURL)
+	setString("EVENTS_FILE", &c.Events.File)
+
+	if value, ok := os.LookupEnv("DB_PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("DB_PORT must be a number: %w", err)
+		}
+		c.Database.Port = port
+	}
+
+	if value, ok := os.LookupEnv("ALLOWANCE_DEFAULT"); ok {
+		limit, err := decimal.NewFromString(value)
+		if err != nil {
+			return fmt.Errorf("ALLOWANCE_DEFAULT must be an amount such as 20000: %w", err)
+		}
+		c.Allowance.Default = limit
+	}
+
+	if value, ok := os.LookupEnv("DB_MIGRATE_ON_START"); ok {
+		migrate, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("DB_MIGRATE_ON_START must be true or false: %w", err)
+		}
+		c.Database.MigrateOnStart = migrate
+	}
+
+	for name, target := range map[string]*Duration{
+		"HTTP_READ_TIMEOUT":        &c.Server.ReadTimeout,
+		"HTTP_READ_HEADER_TIMEOUT": &c.Server.ReadHeaderTimeout,
+		"HTTP_WRITE_TIMEOUT":       &c.Server.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        &c.Server.IdleTimeout,
+		"HTTP_REQUEST_TIMEOUT":     &c.Server.RequestTimeout,
+		"HTTP_SHUTDOWN_DELAY":      &c.Server.ShutdownDelay,
+		"HTTP_SHUTDOWN_TIMEOUT":    &c.Server.ShutdownTimeout,
+		"HEALTH_CHECK_TIMEOUT":     &c.Health.CheckTimeout,
+		"HEALTH_PRICE_MAX_AGE":     &c.Health.PriceMaxAge,
+		"AUTH_LEEWAY":              &c.Auth.Leeway,
+		"IDEMPOTENCY_TTL":          &c.Idempotency.TTL,
+		"IDEMPOTENCY_PENDING_TTL":  &c.Idempotency.PendingTTL,
+		"EVENTS_WEBHOOK_TIMEOUT":   &c.Events.WebhookTimeout,
+		"EVENTS_RELAY_INTERVAL":    &c.Events.RelayInterval,
+		"EVENTS_CLAIM_TTL":         &c.Events.ClaimTTL,
+		"EVENTS_RETRY_BACKOFF":     &c.Events.RetryBackoff,
+		"EVENTS_MAX_RETRY_BACKOFF": &c.Events.MaxRetryBackoff,
+	} {
+		if value, ok := os.LookupEnv(name); ok {
+			if err := target.UnmarshalText([]byte(value)); err != nil {
+				return fmt.Errorf("%s must be a duration such as 30s: %w", name, err)
+			}
+		}
+	}
+
+	if value, ok := os.LookupEnv("CORS_ALLOW_ORIGINS"); ok {
+		c.CORS.AllowOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				c.CORS.AllowOrigins = append(c.CORS.AllowOrigins, origin)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate checks every setting, reporting all the problems found
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.Storage {
+	case StorageMySQL:
+		errs = append(errs, c.Database.validate()...)
+	case StorageMemory:
+	default:
+		errs = append(errs, fmt.Errorf("storage must be %s or %s, got %q", StorageMySQL, StorageMemory, c.Storage))
+	}
+
+	errs = append(errs, c.Server.validate()...)
+	errs = append(errs, c.Health.validate()...)
+	errs = append(errs, c.Auth.validate()...)
+	if c.Idempotency.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("idempotency.ttl must be positive, got %s", time.Duration(c.Idempotency.TTL)))
+	}
+	// A key freed while its request still runs could let a retry repeat it
+	if c.Idempotency.PendingTTL <= c.Server.RequestTimeout {
+		errs = append(errs, fmt.Errorf("idempotency.pending_ttl must be longer than server.request_timeout, got %s and %s", time.Duration(c.Idempotency.PendingTTL), time.Duration(c.Server.RequestTimeout)))
+	}
+	errs = append(errs, c.Allowance.validate()...)
+	errs = append(errs, c.Events.validate()...)
+
+	for _, origin := range c.CORS.AllowOrigins {
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("cors.allow_origins: %q is not an origin such as https://example.com", origin))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// validate checks the settings needed to connect to MySQL
+func (d DatabaseConfig) validate() []error {
+	var errs []error
+	if d.Host == "" {
+		errs = append(errs, errors.New("database.host is required"))
+	}
+	if d.Port < 1 || d.Port > 65535 {
+		errs = append(errs, fmt.Errorf("database.port must be between 1 and 65535, got %d", d.Port))
+	}
+	if d.User == "" {
+		errs = append(errs, errors.New("database.user is required"))
+	}
+	if d.Name == "" {
+		errs = append(errs, errors.New("database.name is required"))
+	}
+	return errs
+}
+
+// validate checks the HTTP server settings
+func (s ServerConfig) validate() []error {
+	var errs []error
+	if s.Addr == "" {
+		errs = append(errs, errors.New("server.addr is required"))
+	}
+	for _, timeout := range []struct {
+		name  string
+		value Duration
+	}{
+		{"server.read_timeout", s.ReadTimeout},
+		{"server.read_header_timeout", s.ReadHeaderTimeout},
+		{"server.write_timeout", s.WriteTimeout},
+		{"server.idle_timeout", s.IdleTimeout},
+		{"server.request_timeout", s.RequestTimeout},
+		{"server.shutdown_timeout", s.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", timeout.name, time.Duration(timeout.value)))
+		}
+	}
+	if s.RequestTimeout >= s.WriteTimeout && s.WriteTimeout > 0 {
+		errs = append(errs, fmt.Errorf("server.request_timeout must be shorter than server.write_timeout, got %s and %s", time.Duration(s.RequestTimeout), time.Duration(s.WriteTimeout)))
+	}
+	if s.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("server.shutdown_delay cannot be negative, got %s", time.Duration(s.ShutdownDelay)))
+	}
+	return errs
+}
+
+// validate checks the readiness check settings
+func (h HealthConfig) validate() []error {
+	var errs []error
+	if h.CheckTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("health.check_timeout must be positive, got %s", time.Duration(h.CheckTimeout)))
+	}
+	if h.PriceMaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("health.price_max_age must be positive, got %s", time.Duration(h.PriceMaxAge)))
+	}
+	return errs
+}
+
+// validate checks the authentication settings. The key set is not required
+// here, so tools that only reach the database need not have one; the API
+// server refuses to start without it.
+func (a AuthConfig) validate() []error {
+	var errs []error
+	if a.Leeway < 0 {
+		errs = append(errs, fmt.Errorf("auth.leeway cannot be negative, got %s", time.Duration(a.Leeway)))
+	}
+	return errs
+}
+
+// validate checks every limit is a positive amount and every tax year is named correctly
+func (a AllowanceConfig) validate() []error {
+	var errs []error
+	if !a.Default.IsPositive() {
+		errs = append(errs, fmt.Errorf("allowance.default must be positive, got %s", a.Default))
+	}
+	for year, limit := range a.Limits {
+		if _, err := domain.ParseTaxYear(year); err != nil {
+			errs = append(errs, fmt.Errorf("allowance.limits: %w", err))
+		}
+		if !limit.IsPositive() {
+			errs = append(errs, fmt.Errorf("allowance.limits: the limit for %s must be positive, got %s", year, limit))
+		}
+	}
+	return errs
+}
+
+// validate checks the event publishing settings
+func (e EventsConfig) validate() []error {
+	var errs []error
+	switch e.Publisher {
+	case PublisherLog:
+	case PublisherWebhook:
+		if u, err := url.Parse(e.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("events.webhook_url must be an http or https URL, got %q", e.WebhookURL))
+		}
+		if e.WebhookTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("events.webhook_timeout must be positive, got %s", time.Duration(e.WebhookTimeout)))
+		} else if e.ClaimTTL <= e.WebhookTimeout {
+			errs = append(errs, fmt.Errorf("events.claim_ttl (%s) must be longer than events.webhook_timeout (%s)", time.Duration(e.ClaimTTL), time.Duration(e.WebhookTimeout)))
+		}
+	case PublisherFile:
+		if e.File == "" {
+			errs = append(errs, errors.New("events.file is required"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("events.publisher must be %s, %s or %s, got %q", PublisherLog, PublisherWebhook, PublisherFile, e.Publisher))
+	}
+	if e.RelayInterval <= 0 {
+		errs = append(errs, fmt.Errorf("events.relay_interval must be positive, got %s", time.Duration(e.RelayInterval)))
+	}
+	if e.BatchSize < 1 {
+		errs = append(errs, fmt.Errorf("events.batch_size must be at least 1, got %d", e.BatchSize))
+	}
+	if e.ClaimTTL <= 0 {
+		errs = append(errs, fmt.Errorf("events.claim_ttl must be positive, got %s", time.Duration(e.ClaimTTL)))
+	}
+	if e.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("events.max_attempts must be at least 1, got %d", e.MaxAttempts))
+	}
+	if e.RetryBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("events.retry_backoff must be positive, got %s", time.Duration(e.RetryBackoff)))
+	} else if e.MaxRetryBackoff < e.RetryBackoff {
+		errs = append(errs, fmt.Errorf("events.max_retry_backoff (%s) must be at least events.retry_backoff (%s)", time.Duration(e.MaxRetryBackoff), time.Duration(e.RetryBackoff)))
+	}
+	return errs
+}
+
+// HTTP returns the settings for the HTTP server
+func (s ServerConfig) HTTP() http.ServerConfig {
+	return http.ServerConfig{
+		Addr:              s.Addr,
+		ReadTimeout:       time.Duration(s.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(s.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(s.WriteTimeout),
+		IdleTimeout:       time.Duration(s.IdleTimeout),
+		ShutdownDelay:     time.Duration(s.ShutdownDelay),
+		ShutdownTimeout:   time.Duration(s.ShutdownTimeout),
+	}
+}
+
+// JWT returns the token verification settings for the HTTP adapter
+func (a AuthConfig) JWT() http.JWTConfig {
+	return http.JWTConfig{
+		JWKSFile: a.JWKSFile,
+		Issuer:   a.Issuer,
+		Audience: a.Audience,
+		Leeway:   time.Duration(a.Leeway),
+	}
+}
+
+// Policy returns the allowance policy for the transaction service. The
+// configuration must have been validated.
+func (a AllowanceConfig) Policy() domain.AllowancePolicy {
+	policy := domain.AllowancePolicy{
+		DefaultLimit: a.Default,
+		Limits:       make(map[domain.TaxYear]decimal.Decimal, len(a.Limits)),
+	}
+	for year, limit := range a.Limits {
+		if taxYear, err := domain.ParseTaxYear(year); err == nil {
+			policy.Limits[taxYear] = limit
+		}
+	}
+	return policy
+}
+
+// Relay returns the settings for the outbox relay
+func (e EventsConfig) Relay() services.OutboxRelayConfig {
+	return services.OutboxRelayConfig{
+		BatchSize:       e.BatchSize,
+		ClaimTTL:        time.Duration(e.ClaimTTL),
+		MaxAttempts:     e.MaxAttempts,
+		RetryBackoff:    time.Duration(e.RetryBackoff),
+		MaxRetryBackoff: time.Duration(e.MaxRetryBackoff),
+	}
+}
+
+// MySQL returns the connection settings for the MySQL adapter
+func (d DatabaseConfig) MySQL() mysql.Config {
+	return mysql.Config{
+		Host:     d.Host,
+		Port:     d.Port,
+		User:     d.User,
+		Password: d.Password,
+		Database: d.Name,
+	}
+}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/services"
+
+	"github.com/shopspring/decimal"
+)
+
+// writeFile writes a config file into a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+// clearEnv unsets the variables Load reads for the duration of a test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"CONFIG_FILE", "STORAGE", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_MIGRATE_ON_START", "HTTP_ADDR", "HTTP_READ_TIMEOUT", "HTTP_READ_HEADER_TIMEOUT", "HTTP_WRITE_TIMEOUT", "HTTP_IDLE_TIMEOUT", "HTTP_REQUEST_TIMEOUT", "HTTP_SHUTDOWN_DELAY", "HTTP_SHUTDOWN_TIMEOUT", "CORS_ALLOW_ORIGINS", "HEALTH_CHECK_TIMEOUT", "HEALTH_PRICE_MAX_AGE", "AUTH_JWKS_FILE", "AUTH_ISSUER", "AUTH_AUDIENCE", "AUTH_LEEWAY", "IDEMPOTENCY_TTL", "IDEMPOTENCY_PENDING_TTL", "ALLOWANCE_DEFAULT", "EVENTS_PUBLISHER", "EVENTS_WEBHOOK_URL", "EVENTS_WEBHOOK_TIMEOUT", "EVENTS_FILE", "EVENTS_RELAY_INTERVAL", "EVENTS_CLAIM_TTL", "EVENTS_RETRY_BACKOFF", "EVENTS_MAX_RETRY_BACKOFF"} {
+		if value, ok := os.LookupEnv(name); ok {
+			os.Unsetenv(name)
+			t.Cleanup(func() { os.Setenv(name, value) })
+		}
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want the defaults %+v", cfg, Default())
+	}
+}
+
+func TestLoad_YAML(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+database:
+  host: db.staging.internal
+  port: 3307
+  name: cushon_staging
+server:
+  addr: ":9090"
+  write_timeout: 1m
+cors:
+  allow_origins:
+    - https://staging.cushon.co.uk
+health:
+  price_max_age: 48h
+auth:
+  jwks_file: /etc/cushon/jwks.json
+  issuer: https://auth.cushon.co.uk
+  audience: isa-api
+allowance:
+  limits:
+    "2026/27": 25000
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Database.Host != "db.staging.internal" || cfg.Database.Port != 3307 || cfg.Database.Name != "cushon_staging" {
+		t.Errorf("Database = %+v, want the file's settings", cfg.Database)
+	}
+	if cfg.Database.User != "root" {
+		t.Errorf("Database.User = %q, want the default root", cfg.Database.User)
+	}
+	if cfg.Server.Addr != ":9090" {
+		t.Errorf("Server.Addr = %q, want :9090", cfg.Server.Addr)
+	}
+	if got := cfg.Server.HTTP().WriteTimeout; got != time.Minute {
+		t.Errorf("Server.WriteTimeout = %s, want 1m", got)
+	}
+	if want := []string{"https://staging.cushon.co.uk"}; !reflect.DeepEqual(cfg.CORS.AllowOrigins, want) {
+		t.Errorf("CORS.AllowOrigins = %v, want %v", cfg.CORS.AllowOrigins, want)
+	}
+	if got := time.Duration(cfg.Health.PriceMaxAge); got != 48*time.Hour {
+		t.Errorf("Health.PriceMaxAge = %s, want 48h", got)
+	}
+	if got := cfg.Auth.JWT(); got.JWKSFile != "/etc/cushon/jwks.json" || got.Issuer != "https://auth.cushon.co.uk" || got.Audience != "isa-api" || got.Leeway != 30*time.Second {
+		t.Errorf("Auth.JWT() = %+v, want the file's settings and the default leeway", got)
+	}
+	policy := cfg.Allowance.Policy()
+	if got := policy.LimitFor(domain.TaxYear(2026)); !got.Equal(decimal.NewFromInt(25000)) {
+		t.Errorf("Allowance limit for 2026/27 = %s, want 25000", got)
+	}
+	if got := policy.LimitFor(domain.TaxYear(2025)); !got.Equal(domain.DefaultISAAllowance) {
+		t.Errorf("Allowance limit for 2025/26 = %s, want the default", got)
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.toml", `
+storage = "memory"
+
+[server]
+addr = ":9090"
+shutdown_timeout = "45s"
+
+[idempotency]
+ttl = "48h"
+
+[events]
+publisher = "webhook"
+webhook_url = "https://events.cushon.co.uk/isa"
+batch_size = 20
+max_attempts = 5
+retry_backoff = "30s"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Storage != StorageMemory || cfg.Server.Addr != ":9090" {
+		t.Errorf("Load() = %+v, want the file's settings", cfg)
+	}
+	if got := cfg.Server.HTTP().ShutdownTimeout; got != 45*time.Second {
+		t.Errorf("Server.ShutdownTimeout = %s, want 45s", got)
+	}
+	if got := time.Duration(cfg.Idempotency.TTL); got != 48*time.Hour {
+		t.Errorf("Idempotency.TTL = %s, want 48h", got)
+	}
+	if got := cfg.Events; got.Publisher != PublisherWebhook || got.WebhookURL != "https://events.cushon.co.uk/isa" || got.BatchSize != 20 {
+		t.Errorf("Events = %+v, want the file's settings", got)
+	}
+	if got := time.Duration(cfg.Events.WebhookTimeout); got != 5*time.Second {
+		t.Errorf("Events.WebhookTimeout = %s, want the default 5s", got)
+	}
+	want := services.OutboxRelayConfig{BatchSize: 20, ClaimTTL: 5 * time.Minute, MaxAttempts: 5, RetryBackoff: 30 * time.Second, MaxRetryBackoff: time.Hour}
+	if got := cfg.Events.Relay(); got != want {
+		t.Errorf("Events.Relay() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_EnvironmentOverridesFile(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+database:
+  host: db.staging.internal
+  port: 3307
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("DB_HOST", "db.production.internal")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_MIGRATE_ON_START", "true")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "90s")
+	t.Setenv("CORS_ALLOW_ORIGINS", "https://cushon.co.uk, https://www.cushon.co.uk")
+	t.Setenv("AUTH_JWKS_FILE", "/run/secrets/jwks.json")
+	t.Setenv("AUTH_LEEWAY", "1m")
+	t.Setenv("ALLOWANCE_DEFAULT", "21000")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	mysqlConfig := cfg.Database.MySQL()
+	if mysqlConfig.Host != "db.production.internal" || mysqlConfig.Port != 3307 || mysqlConfig.Password != "secret" {
+		t.Errorf("Database.MySQL() = %+v, want the environment over the file", mysqlConfig)
+	}
+	if !cfg.Database.MigrateOnStart {
+		t.Error("Database.MigrateOnStart = false, want true from the environment")
+	}
+	if got := cfg.Server.HTTP().IdleTimeout; got != 90*time.Second {
+		t.Errorf("Server.IdleTimeout = %s, want 90s from the environment", got)
+	}
+	if want := []string{"https://cushon.co.uk", "https://www.cushon.co.uk"}; !reflect.DeepEqual(cfg.CORS.AllowOrigins, want) {
+		t.Errorf("CORS.AllowOrigins = %v, want %v", cfg.CORS.AllowOrigins, want)
+	}
+	if got := cfg.Auth.JWT(); got.JWKSFile != "/run/secrets/jwks.json" || got.Leeway != time.Minute {
+		t.Errorf("Auth.JWT() = %+v, want the key set and leeway from the environment", got)
+	}
+	if got := cfg.Allowance.Policy().DefaultLimit; !got.Equal(decimal.NewFromInt(21000)) {
+		t.Errorf("Allowance.Default = %s, want 21000 from the environment", got)
+	}
+}
+
+func TestLoad_InvalidAllowance(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+allowance:
+  default: 0
+  limits:
+    "2026": 25000
+    "2027/28": -1
+`)
+
+	_, err := Load(path)
+	for _, want := range []string{"allowance.default must be positive", `"2026" is not a tax year`, "the limit for 2027/28 must be positive"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() error = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		file    string
+		wantErr string
+	}{
+		{
+			name:    "unknown storage",
+			env:     map[string]string{"STORAGE": "mongodb"},
+			wantErr: "storage must be mysql or memory",
+		},
+		{
+			name:    "port not a number",
+			env:     map[string]string{"DB_PORT": "mysql"},
+			wantErr: "DB_PORT must be a number",
+		},
+		{
+			name:    "migrate on start not a boolean",
+			env:     map[string]string{"DB_MIGRATE_ON_START": "sometimes"},
+			wantErr: "DB_MIGRATE_ON_START must be true or false",
+		},
+		{
+			name:    "timeout not a duration",
+			env:     map[string]string{"HTTP_WRITE_TIMEOUT": "30"},
+			wantErr: "HTTP_WRITE_TIMEOUT must be a duration",
+		},
+		{
+			name:    "timeout not positive",
+			env:     map[string]string{"HTTP_SHUTDOWN_TIMEOUT": "0s"},
+			wantErr: "server.shutdown_timeout must be positive",
+		},
+		{
+			name:    "request timeout outlasting the write timeout",
+			env:     map[string]string{"HTTP_REQUEST_TIMEOUT": "30s"},
+			wantErr: "server.request_timeout must be shorter than server.write_timeout",
+		},
+		{
+			name:    "negative shutdown delay",
+			env:     map[string]string{"HTTP_SHUTDOWN_DELAY": "-5s"},
+			wantErr: "server.shutdown_delay cannot be negative",
+		},
+		{
+			name:    "port out of range",
+			env:     map[string]string{"DB_PORT": "70000"},
+			wantErr: "database.port must be between 1 and 65535",
+		},
+		{
+			name:    "missing database name",
+			env:     map[string]string{"DB_NAME": ""},
+			wantErr: "database.name is required",
+		},
+		{
+			name:    "origin without a scheme",
+			env:     map[string]string{"CORS_ALLOW_ORIGINS": "localhost:3000"},
+			wantErr: "is not an origin",
+		},
+		{
+			name:    "negative leeway",
+			env:     map[string]string{"AUTH_LEEWAY": "-1s"},
+			wantErr: "auth.leeway cannot be negative",
+		},
+		{
+			name:    "idempotency keys never kept",
+			env:     map[string]string{"IDEMPOTENCY_TTL": "0s"},
+			wantErr: "idempotency.ttl must be positive",
+		},
+		{
+			name:    "idempotency key freed before its request times out",
+			env:     map[string]string{"IDEMPOTENCY_PENDING_TTL": "10s"},
+			wantErr: "idempotency.pending_ttl must be longer than server.request_timeout",
+		},
+		{
+			name:    "allowance not an amount",
+			env:     map[string]string{"ALLOWANCE_DEFAULT": "twenty thousand"},
+			wantErr: "ALLOWANCE_DEFAULT must be an amount",
+		},
+		{
+			name:    "unknown publisher",
+			env:     map[string]string{"EVENTS_PUBLISHER": "kafka"},
+			wantErr: "events.publisher must be log, webhook or file",
+		},
+		{
+			name:    "webhook without a URL",
+			env:     map[string]string{"EVENTS_PUBLISHER": "webhook"},
+			wantErr: "events.webhook_url must be an http or https URL",
+		},
+		{
+			name:    "file publisher without a path",
+			env:     map[string]string{"EVENTS_PUBLISHER": "file"},
+			wantErr: "events.file is required",
+		},
+		{
+			name:    "relay never runs",
+			env:     map[string]string{"EVENTS_RELAY_INTERVAL": "0s"},
+			wantErr: "events.relay_interval must be positive",
+		},
+		{
+			name:    "claim outlasted by the webhook",
+			env:     map[string]string{"EVENTS_PUBLISHER": "webhook", "EVENTS_WEBHOOK_URL": "https://events.cushon.co.uk/isa", "EVENTS_CLAIM_TTL": "5s"},
+			wantErr: "events.claim_ttl (5s) must be longer than events.webhook_timeout (5s)",
+		},
+		{
+			name:    "backoff capped below its start",
+			env:     map[string]string{"EVENTS_RETRY_BACKOFF": "1m", "EVENTS_MAX_RETRY_BACKOFF": "30s"},
+			wantErr: "events.max_retry_backoff (30s) must be at least events.retry_backoff (1m0s)",
+		},
+		{
+			name:    "unsupported file format",
+			file:    "config.json",
+			wantErr: "must be .yaml, .yml or .toml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			path := ""
+			if tt.file != "" {
+				path = writeFile(t, tt.file, "{}")
+			}
+
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_MemoryStorageNeedsNoDatabase(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("STORAGE", StorageMemory)
+	t.Setenv("DB_HOST", "")
+
+	if _, err := Load(""); err != nil {
+		t.Errorf("Load() error = %v, want the database settings ignored", err)
+	}
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultISAAllowance is the annual ISA subscription limit in pounds
+var DefaultISAAllowance = decimal.NewFromInt(20000)
+
+// ukLocation is the timezone tax years are measured in, falling back to UTC
+// when the timezone database is unavailable
+var ukLocation = func() *time.Location {
+	location, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}()
+
+// TaxYear identifies a UK tax year by the calendar year it starts in,
+// e.g. TaxYear(2024) runs from 6 April 2024 to 5 April 2025 inclusive
+type TaxYear int
+
+// TaxYearFor returns the tax year containing the given instant
+func TaxYearFor(t time.Time) TaxYear {
+	local := t.In(ukLocation)
+	year := local.Year()
+	if local.Before(time.Date(year, time.April, 6, 0, 0, 0, 0, ukLocation)) {
+		year--
+	}
+	return TaxYear(year)
+}
+
+// Start returns the first instant of the tax year
+func (y TaxYear) Start() time.Time {
+	return time.Date(int(y), time.April, 6, 0, 0, 0, 0, ukLocation)
+}
+
+// End returns the first instant after the tax year, i.e. the start of the next one
+func (y TaxYear) End() time.Time {
+	return (y + 1).Start()
+}
+
+// String returns the tax year in the conventional "2024/25" form
+func (y TaxYear) String() string {
+	return fmt.Sprintf("%d/%02d", int(y), (int(y)+1)%100)
+}
+
+// ParseTaxYear parses a tax year in the "2024/25" form String returns
+func ParseTaxYear(s string) (TaxYear, error) {
+	start, err := strconv.Atoi(s[:min(4, len(s))])
+	if err != nil || start < 1000 || TaxYear(start).String() != s {
+		return 0, fmt.Errorf("%q is not a tax year such as 2024/25", s)
+	}
+	return TaxYear(start), nil
+}
+
+// AllowancePolicy holds the ISA subscription limit for each tax year
+type AllowancePolicy struct {
+	// DefaultLimit applies to any tax year without an explicit limit
+	DefaultLimit decimal.Decimal
+	// Limits overrides the default for specific tax years
+	Limits map[TaxYear]decimal.Decimal
+}
+
+// DefaultAllowancePolicy returns a policy applying the default ISA allowance to every tax year
+func DefaultAllowancePolicy() AllowancePolicy {
+	return AllowancePolicy{
+		DefaultLimit: DefaultISAAllowance,
+		Limits:       map[TaxYear]decimal.Decimal{},
+	}
+}
+
+// LimitFor returns the subscription limit for the given tax year
+func (p AllowancePolicy) LimitFor(year TaxYear) decimal.Decimal {
+	if limit, exists := p.Limits[year]; exists {
+		return limit
+	}
+	return p.DefaultLimit
+}
+
+// Allowance summarises a user's ISA subscriptions for a tax year
+type Allowance struct {
+	UserID    string
+	TaxYear   string
+	Limit     decimal.Decimal
+	Used      decimal.Decimal
+	Remaining decimal.Decimal
+}
+
+// NewAllowance creates an allowance summary, never reporting a negative remainder
+func NewAllowance(userID string, year TaxYear, limit, used decimal.Decimal) *Allowance {
+	remaining := limit.Sub(used)
+	if remaining.IsNegative() {
+		remaining = decimal.Zero
+	}
+
+	return &Allowance{
+		UserID:    userID,
+		TaxYear:   year.String(),
+		Limit:     limit,
+		Used:      used,
+		Remaining: remaining,
+	}
+}
+
+// AllowanceExceededError is returned when a deposit would take a user over their ISA allowance
+type AllowanceExceededError struct {
+	TaxYear   TaxYear
+	Limit     decimal.Decimal
+	Used      decimal.Decimal
+	Requested decimal.Decimal
+}
+
+// Error implements the error interface
+func (e *AllowanceExceededError) Error() string {
+	return fmt.Sprintf("deposit of %s exceeds the %s ISA allowance: %s of %s already used",
+		e.Requested.StringFixed(2),
+		e.TaxYear,
+		e.Used.StringFixed(2),
+		e.Limit.StringFixed(2),
+	)
+}
+
+// Is reports whether target is ErrAllowanceExceeded
+func (e *AllowanceExceededError) Is(target error) bool {
+	return target == ErrAllowanceExceeded
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTaxYearFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		instant  time.Time
+		expected TaxYear
+	}{
+		{
+			name:     "day before tax year starts",
+			instant:  time.Date(2025, time.April, 5, 12, 0, 0, 0, time.UTC),
+			expected: TaxYear(2024),
+		},
+		{
+			name:     "first day of tax year",
+			instant:  time.Date(2025, time.April, 6, 12, 0, 0, 0, time.UTC),
+			expected: TaxYear(2025),
+		},
+		{
+			name:     "january belongs to previous tax year",
+			instant:  time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC),
+			expected: TaxYear(2025),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TaxYearFor(tt.instant); got != tt.expected {
+				t.Errorf("Expected tax year %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTaxYear_Bounds(t *testing.T) {
+	year := TaxYear(2024)
+
+	if year.String() != "2024/25" {
+		t.Errorf("Expected 2024/25, got %s", year.String())
+	}
+
+	if TaxYearFor(year.Start()) != year {
+		t.Error("Expected start of tax year to belong to the tax year")
+	}
+
+	if TaxYearFor(year.End()) != year+1 {
+		t.Error("Expected end of tax year to belong to the next tax year")
+	}
+
+	if TaxYearFor(year.End().Add(-time.Nanosecond)) != year {
+		t.Error("Expected instant before end of tax year to belong to the tax year")
+	}
+}
+
+func TestParseTaxYear(t *testing.T) {
+	year, err := ParseTaxYear("2024/25")
+	if err != nil || year != TaxYear(2024) {
+		t.Errorf("Expected 2024, got %d (%v)", year, err)
+	}
+	if year, err := ParseTaxYear("1999/00"); err != nil || year != TaxYear(1999) {
+		t.Errorf("Expected 1999, got %d (%v)", year, err)
+	}
+
+	for _, invalid := range []string{"", "2024", "2024/26", "2024/2025", "24/25", "2024/25x"} {
+		if _, err := ParseTaxYear(invalid); err == nil {
+			t.Errorf("Expected an error parsing %q", invalid)
+		}
+	}
+}
+
+func TestAllowancePolicy_LimitFor(t *testing.T) {
+	policy := DefaultAllowancePolicy()
+	policy.Limits[TaxYear(2030)] = decimal.NewFromInt(25000)
+
+	if !policy.LimitFor(TaxYear(2024)).Equal(DefaultISAAllowance) {
+		t.Errorf("Expected default limit, got %s", policy.LimitFor(TaxYear(2024)))
+	}
+
+	if !policy.LimitFor(TaxYear(2030)).Equal(decimal.NewFromInt(25000)) {
+		t.Errorf("Expected overridden limit, got %s", policy.LimitFor(TaxYear(2030)))
+	}
+}
+
+func TestNewAllowance(t *testing.T) {
+	allowance := NewAllowance("user123", TaxYear(2024), decimal.NewFromInt(20000), decimal.NewFromInt(25000))
+
+	if !allowance.Remaining.IsZero() {
+		t.Errorf("Expected remaining allowance to floor at zero, got %s", allowance.Remaining)
+	}
+
+	if allowance.TaxYear != "2024/25" {
+		t.Errorf("Expected tax year 2024/25, got %s", allowance.TaxYear)
+	}
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AuditAction is the kind of change an audit entry records
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionCorrect records a transaction replaced by a corrected entry
+	AuditActionCorrect AuditAction = "correct"
+	// AuditActionReverse records a transaction cancelled by a reversing entry
+	AuditActionReverse AuditAction = "reverse"
+	// AuditActionAllocateUnits records units allocated to a transaction when its fund was priced
+	AuditActionAllocateUnits AuditAction = "allocate-units"
+)
+
+// AuditEntityType is the kind of entity an audit entry is about
+type AuditEntityType string
+
+const (
+	AuditEntityDirectUser  AuditEntityType = "direct-user"
+	AuditEntityTransaction AuditEntityType = "transaction"
+)
+
+// IsValid checks if the entity type is one the audit log records
+func (t AuditEntityType) IsValid() bool {
+	return t == AuditEntityDirectUser || t == AuditEntityTransaction
+}
+
+// Auditable is an entity whose changes are recorded in the audit log
+type Auditable interface {
+	// auditRef returns the kind of entity and its ID
+	auditRef() (AuditEntityType, string)
+	// snapshot returns the entity as the audit log and events record it
+	snapshot() any
+}
+
+// AuditEntry records a change to an entity: who made it, in which request,
+// and the entity before and after
+type AuditEntry struct {
+	ID string
+	// Actor is the subject of the principal that made the change
+	Actor      string
+	Action     AuditAction
+	EntityType AuditEntityType
+	EntityID   string
+	// Before and After are JSON snapshots of the entity, Before nil for a
+	// creation and After nil for a deletion. A transaction corrected or
+	// reversed is left as it was, so After is the entry recorded against it.
+	Before json.RawMessage
+	After  json.RawMessage
+	// RequestID identifies the request that made the change, empty if it was not made by a request
+	RequestID string
+	CreatedAt time.Time
+}
+
+// NewAuditEntry creates the entry for a change the actor made at now. The
+// entry is about the entity as it was before, or the one created if there
+// was none.
+func NewAuditEntry(actor string, action AuditAction, before, after Auditable, requestID string, now time.Time) (*AuditEntry, error) {
+	subject := before
+	if subject == nil {
+		subject = after
+	}
+	entityType, entityID := subject.auditRef()
+
+	entry := &AuditEntry{
+		ID:         uuid.New().String(),
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		RequestID:  requestID,
+		CreatedAt:  now,
+	}
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before.snapshot()); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after.snapshot()); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// directUserSnapshot is a direct user as the audit log and events record it
+type directUserSnapshot struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (u *DirectUser) auditRef() (AuditEntityType, string) {
+	return AuditEntityDirectUser, u.ID
+}
+
+func (u *DirectUser) snapshot() any {
+	return directUserSnapshot{ID: u.ID, Name: u.Name, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt}
+}
+
+// transactionSnapshot is a transaction as the audit log and events record it
+type transactionSnapshot struct {
+	ID            string           `json:"id"`
+	UserID        string           `json:"user_id"`
+	Type          TransactionType  `json:"type"`
+	Amount        decimal.Decimal  `json:"amount"`
+	FundName      FundName         `json:"fund_name"`
+	Units         *decimal.Decimal `json:"units"`
+	UnitPrice     *decimal.Decimal `json:"unit_price"`
+	ValuationDate *time.Time       `json:"valuation_date"`
+	ReversalOf    string           `json:"reversal_of,omitempty"`
+	Reason        string           `json:"reason,omitempty"`
+	Actor         string           `json:"actor,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+func (t *Transaction) auditRef() (AuditEntityType, string) {
+	return AuditEntityTransaction, t.ID
+}
+
+func (t *Transaction) snapshot() any {
+	snapshot := transactionSnapshot{
+		ID:            t.ID,
+		UserID:        t.UserID,
+		Type:          t.Type,
+		Amount:        t.Amount,
+		FundName:      t.FundName,
+		ValuationDate: t.ValuationDate,
+		ReversalOf:    t.ReversalOf,
+		Reason:        t.Reason,
+		Actor:         t.Actor,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+	if t.IsPriced() {
+		snapshot.Units = &t.Units
+		snapshot.UnitPrice = &t.UnitPrice
+	}
+	return snapshot
+}
+
+// requestIDKey is the context key the request ID is stored under
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the ID of the request it serves
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID ctx carries, empty if it carries none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
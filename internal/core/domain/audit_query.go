@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+const (
+	// DefaultAuditPageSize is the number of audit entries in a page when no limit is given
+	DefaultAuditPageSize = 100
+	// MaxAuditPageSize is the most audit entries a page can hold
+	MaxAuditPageSize = 1000
+)
+
+// AuditCursor marks the last entry of a page of the audit log. Entries are
+// ordered by the time they were recorded and then by ID.
+type AuditCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// AuditQuery selects a page of the audit log, oldest entry first. Zero valued
+// filters match every entry.
+type AuditQuery struct {
+	EntityType AuditEntityType
+	// EntityID restricts the log to the changes made to one entity
+	EntityID string
+	// From and To bound the time the changes were made to the half-open interval [From, To)
+	From  time.Time
+	To    time.Time
+	Limit int
+	// After is the cursor of the previous page, nil for the first page
+	After *AuditCursor
+}
+
+// Validate checks the query is complete and consistent, after defaults are applied
+func (q *AuditQuery) Validate() error {
+	if !q.EntityType.IsValid() {
+		return NewValidationError("entity_type", "entity type must be direct-user or transaction")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return NewValidationError("to", "to must be after from")
+	}
+	if q.Limit < 1 || q.Limit > MaxAuditPageSize {
+		return NewValidationError("limit", "limit must be between 1 and 1000")
+	}
+	return nil
+}
+
+// Matches reports whether an entry passes the query's filters. The cursor is not considered.
+func (q *AuditQuery) Matches(entry *AuditEntry) bool {
+	return entry.EntityType == q.EntityType &&
+		(q.EntityID == "" || entry.EntityID == q.EntityID) &&
+		(q.From.IsZero() || !entry.CreatedAt.Before(q.From)) &&
+		(q.To.IsZero() || entry.CreatedAt.Before(q.To))
+}
+
+// AuditPage is one page of the audit log
+type AuditPage struct {
+	Entries []*AuditEntry
+	// Next is the cursor for the following page, nil on the last page
+	Next *AuditCursor
+}
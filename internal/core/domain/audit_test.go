@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	transaction := NewTransaction("user123", TransactionTypeDeposit, decimal.NewFromInt(100), CushonEquitiesFund, now)
+
+	entry, err := NewAuditEntry("ops@cushon.co.uk", AuditActionCreate, nil, transaction, "req-1", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if entry.ID == "" || entry.EntityType != AuditEntityTransaction || entry.EntityID != transaction.ID {
+		t.Errorf("Expected an entry about transaction %s, got %+v", transaction.ID, entry)
+	}
+	if entry.Before != nil {
+		t.Errorf("Expected no snapshot before a creation, got %s", entry.Before)
+	}
+
+	var snapshot map[string]any
+	if err := json.Unmarshal(entry.After, &snapshot); err != nil {
+		t.Fatalf("Failed to decode snapshot: %v", err)
+	}
+	if snapshot["amount"] != "100" || snapshot["user_id"] != "user123" {
+		t.Errorf("Expected the transaction's fields in the snapshot, got %v", snapshot)
+	}
+	// A pending transaction has no units yet
+	if units, present := snapshot["units"]; !present || units != nil {
+		t.Errorf("Expected null units for a pending transaction, got %v", units)
+	}
+
+	user := NewDirectUser("John Doe", now)
+	entry, err = NewAuditEntry("ops@cushon.co.uk", AuditActionDelete, user, nil, "", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if entry.EntityType != AuditEntityDirectUser || entry.EntityID != user.ID || entry.After != nil {
+		t.Errorf("Expected the deletion of direct user %s, got %+v", user.ID, entry)
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Errorf("Expected no request ID, got %q", got)
+	}
+	if got := RequestIDFromContext(ContextWithRequestID(context.Background(), "req-1")); got != "req-1" {
+		t.Errorf("Expected req-1, got %q", got)
+	}
+}
+
+func TestAuditQuery_Validate(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query AuditQuery
+		valid bool
+	}{
+		{"entity type only", AuditQuery{EntityType: AuditEntityTransaction, Limit: 10}, true},
+		{"missing entity type", AuditQuery{Limit: 10}, false},
+		{"unknown entity type", AuditQuery{EntityType: "fund", Limit: 10}, false},
+		{"to before from", AuditQuery{EntityType: AuditEntityDirectUser, From: from, To: from, Limit: 10}, false},
+		{"limit too large", AuditQuery{EntityType: AuditEntityDirectUser, Limit: MaxAuditPageSize + 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected the query to be valid, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected ErrValidation, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuditQuery_Matches(t *testing.T) {
+	recorded := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entry := &AuditEntry{EntityType: AuditEntityDirectUser, EntityID: "user123", CreatedAt: recorded}
+
+	tests := []struct {
+		name     string
+		query    AuditQuery
+		expected bool
+	}{
+		{"entity type", AuditQuery{EntityType: AuditEntityDirectUser}, true},
+		{"another entity type", AuditQuery{EntityType: AuditEntityTransaction}, false},
+		{"same entity", AuditQuery{EntityType: AuditEntityDirectUser, EntityID: "user123"}, true},
+		{"another entity", AuditQuery{EntityType: AuditEntityDirectUser, EntityID: "user456"}, false},
+		{"from is inclusive", AuditQuery{EntityType: AuditEntityDirectUser, From: recorded}, true},
+		{"to is exclusive", AuditQuery{EntityType: AuditEntityDirectUser, To: recorded}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(entry); got != tt.expected {
+				t.Errorf("Expected Matches to be %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -2,19 +2,25 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 // DirectUser represents a direct user in the system
 type DirectUser struct {
-	ID   string
-	Name string
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
-// NewDirectUser creates a new direct user instance
-func NewDirectUser(name string) *DirectUser {
+// NewDirectUser creates a new direct user instance created at now
+func NewDirectUser(name string, now time.Time) *DirectUser {
 	return &DirectUser{
-		ID:   uuid.New().String(),
-		Name: name,
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 } 
\ No newline at end of file
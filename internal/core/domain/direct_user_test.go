@@ -2,11 +2,12 @@ package domain
 
 import (
 	"testing"
+	"time"
 )
 
 func TestNewDirectUser(t *testing.T) {
 	name := "John Doe"
-	user := NewDirectUser(name)
+	user := NewDirectUser(name, time.Now())
 
 	// Test name is set correctly
 	if user.Name != name {
@@ -0,0 +1,99 @@
+package domain
+
+import "errors"
+
+// Sentinel errors classify failures so adapters can react to the kind of
+// error with errors.Is rather than matching on its message
+var (
+	// ErrNotFound is returned when a requested entity does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrValidation is returned when input breaks a validation rule
+	ErrValidation = errors.New("validation failed")
+	// ErrConflict is returned when a change conflicts with the current state
+	ErrConflict = errors.New("conflict")
+	// ErrAllowanceExceeded is returned when a deposit would exceed the ISA allowance
+	ErrAllowanceExceeded = errors.New("allowance exceeded")
+	// ErrInsufficientBalance is returned when a debit exceeds the available balance
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrUnauthenticated is returned when a request does not say who is making it
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned when the principal making a request may not do what it asks
+	ErrForbidden = errors.New("forbidden")
+)
+
+var (
+	// ErrUserNotFound is returned when a direct user does not exist
+	ErrUserNotFound = NewNotFoundError("direct user not found")
+	// ErrTransactionNotFound is returned when a transaction does not exist
+	ErrTransactionNotFound = NewNotFoundError("transaction not found")
+	// ErrFundNotFound is returned when a fund does not exist
+	ErrFundNotFound = NewNotFoundError("fund not found")
+	// ErrFundExists is returned when a fund with the same name or ISIN is already in the catalogue
+	ErrFundExists = NewConflictError("fund already exists")
+	// ErrFundISINTaken is returned when a fund is updated to the ISIN of another fund
+	ErrFundISINTaken = NewConflictError("another fund has the same ISIN")
+	// ErrAlreadyReversed is returned when a transaction has already been reversed
+	ErrAlreadyReversed = NewConflictError("transaction already reversed")
+	// ErrIdempotencyKeyNotFound is returned when no request is recorded under an idempotency key
+	ErrIdempotencyKeyNotFound = NewNotFoundError("idempotency key not found")
+	// ErrEventNotFound is returned when an event is not in the outbox
+	ErrEventNotFound = NewNotFoundError("event not found")
+)
+
+// kindError is an error with its own message that is a kind of one of the sentinel errors
+type kindError struct {
+	kind    error
+	message string
+}
+
+// Error implements the error interface
+func (e *kindError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the sentinel error the error is a kind of
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// NewNotFoundError creates an error matching ErrNotFound
+func NewNotFoundError(message string) error {
+	return &kindError{kind: ErrNotFound, message: message}
+}
+
+// NewConflictError creates an error matching ErrConflict
+func NewConflictError(message string) error {
+	return &kindError{kind: ErrConflict, message: message}
+}
+
+// NewUnauthenticatedError creates an error matching ErrUnauthenticated
+func NewUnauthenticatedError(message string) error {
+	return &kindError{kind: ErrUnauthenticated, message: message}
+}
+
+// NewForbiddenError creates an error matching ErrForbidden
+func NewForbiddenError(message string) error {
+	return &kindError{kind: ErrForbidden, message: message}
+}
+
+// ValidationError is returned when a field fails validation. It matches ErrValidation.
+type ValidationError struct {
+	// Field is the name of the invalid field, empty if the error is not about a single field
+	Field   string
+	Message string
+}
+
+// NewValidationError creates a validation error for a field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
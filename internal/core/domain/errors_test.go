@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{name: "user not found", err: ErrUserNotFound, kind: ErrNotFound},
+		{name: "wrapped not found", err: fmt.Errorf("loading portfolio: %w", ErrFundNotFound), kind: ErrNotFound},
+		{name: "fund exists", err: ErrFundExists, kind: ErrConflict},
+		{name: "validation", err: NewValidationError("amount", "amount must be positive"), kind: ErrValidation},
+		{name: "allowance exceeded", err: &AllowanceExceededError{Limit: decimal.NewFromInt(1)}, kind: ErrAllowanceExceeded},
+		{name: "insufficient balance", err: &InsufficientBalanceError{}, kind: ErrInsufficientBalance},
+		{name: "forbidden", err: NewForbiddenError("not your account"), kind: ErrForbidden},
+	}
+
+	kinds := []error{ErrNotFound, ErrValidation, ErrConflict, ErrAllowanceExceeded, ErrInsufficientBalance, ErrUnauthenticated, ErrForbidden}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, kind := range kinds {
+				if errors.Is(tt.err, kind) != (kind == tt.kind) {
+					t.Errorf("Expected errors.Is(%v, %v) to be %v", tt.err, kind, kind == tt.kind)
+				}
+			}
+		})
+	}
+
+	if ErrUserNotFound.Error() != "direct user not found" {
+		t.Errorf("Expected message to be kept, got %q", ErrUserNotFound.Error())
+	}
+}
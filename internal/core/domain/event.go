@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType names a kind of domain event. Downstream systems subscribe by type.
+type EventType string
+
+const (
+	EventTransactionCreated  EventType = "transaction.created"
+	EventTransactionReversed EventType = "transaction.reversed"
+	EventDirectUserCreated   EventType = "direct-user.created"
+	EventDirectUserDeleted   EventType = "direct-user.deleted"
+)
+
+// Event records something that happened to a direct user or transaction, for
+// systems outside the API to act on. Events are stored in the outbox with the
+// change they describe and published once it is committed, at least once:
+// a consumer may see an event again and should recognise it by its ID.
+type Event struct {
+	ID   string
+	Type EventType
+	// AggregateID is the ID of the direct user or transaction the event is about
+	AggregateID string
+	// Payload is the JSON snapshot of the entity the event is about
+	Payload    json.RawMessage
+	OccurredAt time.Time
+}
+
+// newEvent creates an event of the given type with payload encoded as JSON
+func newEvent(eventType EventType, aggregateID string, payload any, now time.Time) (*Event, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		ID:          uuid.New().String(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     encoded,
+		OccurredAt:  now,
+	}, nil
+}
+
+// NewTransactionCreatedEvent creates the event for a deposit or withdrawal being placed
+func NewTransactionCreatedEvent(transaction *Transaction, now time.Time) (*Event, error) {
+	return newEvent(EventTransactionCreated, transaction.ID, transaction.snapshot(), now)
+}
+
+// reversalPayload is a reversed transaction as its event records it
+type reversalPayload struct {
+	Original   any `json:"original"`
+	Reversal   any `json:"reversal"`
+	Correction any `json:"correction,omitempty"`
+}
+
+// NewTransactionReversedEvent creates the event for a transaction being
+// reversed, or corrected, in which case the payload holds the corrected
+// entry as well. The event is about the original transaction.
+func NewTransactionReversedEvent(reversal *Reversal, now time.Time) (*Event, error) {
+	payload := reversalPayload{
+		Original: reversal.Original.snapshot(),
+		Reversal: reversal.Reversal.snapshot(),
+	}
+	if reversal.Correction != nil {
+		payload.Correction = reversal.Correction.snapshot()
+	}
+	return newEvent(EventTransactionReversed, reversal.Original.ID, payload, now)
+}
+
+// NewDirectUserCreatedEvent creates the event for a direct user being onboarded
+func NewDirectUserCreatedEvent(user *DirectUser, now time.Time) (*Event, error) {
+	return newEvent(EventDirectUserCreated, user.ID, user.snapshot(), now)
+}
+
+// NewDirectUserDeletedEvent creates the event for a direct user being deleted,
+// its payload the user as they were
+func NewDirectUserDeletedEvent(user *DirectUser, now time.Time) (*Event, error) {
+	return newEvent(EventDirectUserDeleted, user.ID, user.snapshot(), now)
+}
+
+// PendingEvent is an event claimed from the outbox to be published
+type PendingEvent struct {
+	Event *Event
+	// Attempts is how many times publishing the event has failed so far
+	Attempts int
+}
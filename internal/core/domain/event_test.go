@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewTransactionReversedEvent(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	original := NewTransaction("user123", TransactionTypeDeposit, decimal.NewFromInt(100), CushonEquitiesFund, now)
+	reversal, err := original.Reverse("wrong amount", "ops@cushon.co.uk", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	correction := NewTransaction("user123", TransactionTypeDeposit, decimal.NewFromInt(150), CushonEquitiesFund, now.Add(time.Hour))
+
+	tests := []struct {
+		name           string
+		reversal       *Reversal
+		wantCorrection bool
+	}{
+		{"reversal", &Reversal{Original: original, Reversal: reversal}, false},
+		{"correction", &Reversal{Original: original, Reversal: reversal, Correction: correction}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewTransactionReversedEvent(tt.reversal, now.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if event.ID == "" || event.Type != EventTransactionReversed || event.AggregateID != original.ID {
+				t.Errorf("Expected a reversed event about %s, got %+v", original.ID, event)
+			}
+
+			var payload map[string]map[string]any
+			if err := json.Unmarshal(event.Payload, &payload); err != nil {
+				t.Fatalf("Failed to decode payload: %v", err)
+			}
+			if payload["original"]["id"] != original.ID || payload["reversal"]["reversal_of"] != original.ID {
+				t.Errorf("Expected the original and its reversal in the payload, got %s", event.Payload)
+			}
+			if _, present := payload["correction"]; present != tt.wantCorrection {
+				t.Errorf("Expected correction present to be %v, got %s", tt.wantCorrection, event.Payload)
+			}
+		})
+	}
+}
+
+func TestNewDirectUserEvents(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := NewDirectUser("John Doe", now)
+
+	for eventType, newEvent := range map[EventType]func(*DirectUser, time.Time) (*Event, error){
+		EventDirectUserCreated: NewDirectUserCreatedEvent,
+		EventDirectUserDeleted: NewDirectUserDeletedEvent,
+	} {
+		event, err := newEvent(user, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if event.Type != eventType || event.AggregateID != user.ID || !event.OccurredAt.Equal(now) {
+			t.Errorf("Expected %s about %s, got %+v", eventType, user.ID, event)
+		}
+
+		var payload map[string]any
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			t.Fatalf("Failed to decode payload: %v", err)
+		}
+		if payload["name"] != "John Doe" {
+			t.Errorf("Expected the user in the %s payload, got %s", eventType, event.Payload)
+		}
+	}
+}
@@ -1,24 +1,191 @@
 package domain
 
-// FundName represents the available fund types
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// FundName represents the name of a fund in the catalogue
 type FundName string
 
 const (
-	// CushonEquitiesFund represents the Cushon Equities Fund
+	// CushonEquitiesFund represents the Cushon Equities Fund, seeded into the catalogue by the schema
 	CushonEquitiesFund FundName = "Cushon Equities Fund"
 )
 
-// IsValid checks if the fund name is valid
+// IsValid checks if the fund name is well formed. Whether the fund actually
+// exists is decided by the fund catalogue, not by this check.
 func (f FundName) IsValid() bool {
-	switch f {
-	case CushonEquitiesFund:
+	return strings.TrimSpace(string(f)) != ""
+}
+
+// String returns the string representation of the fund name
+func (f FundName) String() string {
+	return string(f)
+}
+
+// FundStatus represents whether a fund is accepting new investments
+type FundStatus string
+
+const (
+	// FundStatusOpen marks a fund that accepts new investments
+	FundStatusOpen FundStatus = "open"
+	// FundStatusClosed marks a fund that no longer accepts new investments
+	FundStatusClosed FundStatus = "closed"
+)
+
+// IsValid checks if the fund status is a known status
+func (s FundStatus) IsValid() bool {
+	switch s {
+	case FundStatusOpen, FundStatusClosed:
 		return true
 	default:
 		return false
 	}
 }
 
-// String returns the string representation of the fund name
-func (f FundName) String() string {
-	return string(f)
-} 
\ No newline at end of file
+// AssetClass represents the broad type of assets a fund invests in
+type AssetClass string
+
+const (
+	AssetClassEquity     AssetClass = "equity"
+	AssetClassBond       AssetClass = "bond"
+	AssetClassMultiAsset AssetClass = "multi-asset"
+	AssetClassProperty   AssetClass = "property"
+	AssetClassCash       AssetClass = "cash"
+)
+
+// IsValid checks if the asset class is a known asset class
+func (a AssetClass) IsValid() bool {
+	switch a {
+	case AssetClassEquity, AssetClassBond, AssetClassMultiAsset, AssetClassProperty, AssetClassCash:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MinRiskRating is the lowest synthetic risk and reward indicator (SRRI)
+	MinRiskRating = 1
+	// MaxRiskRating is the highest synthetic risk and reward indicator (SRRI)
+	MaxRiskRating = 7
+)
+
+// Fund represents a fund in the catalogue that users can invest in
+type Fund struct {
+	ID         string
+	Name       FundName
+	ISIN       string
+	AssetClass AssetClass
+	Currency   string
+	RiskRating int
+	Status     FundStatus
+}
+
+// NewFund creates a new fund instance, open for investment
+func NewFund(name FundName, isin string, assetClass AssetClass, currency string, riskRating int) *Fund {
+	fund := &Fund{
+		ID:         uuid.New().String(),
+		Name:       name,
+		ISIN:       isin,
+		AssetClass: assetClass,
+		Currency:   currency,
+		RiskRating: riskRating,
+		Status:     FundStatusOpen,
+	}
+	fund.Normalize()
+	return fund
+}
+
+// Normalize upper-cases the ISIN and currency code, which are accepted in either case
+func (f *Fund) Normalize() {
+	f.ISIN = strings.ToUpper(f.ISIN)
+	f.Currency = strings.ToUpper(f.Currency)
+}
+
+// IsOpen reports whether the fund accepts new investments
+func (f *Fund) IsOpen() bool {
+	return f.Status == FundStatusOpen
+}
+
+// Validate checks the fund's fields against the catalogue rules
+func (f *Fund) Validate() error {
+	if !f.Name.IsValid() {
+		return NewValidationError("name", "fund name is required")
+	}
+	if !IsValidISIN(f.ISIN) {
+		return NewValidationError("isin", "invalid ISIN")
+	}
+	if !f.AssetClass.IsValid() {
+		return NewValidationError("asset_class", "invalid asset class")
+	}
+	if !isCurrencyCode(f.Currency) {
+		return NewValidationError("currency", "invalid currency")
+	}
+	if f.RiskRating < MinRiskRating || f.RiskRating > MaxRiskRating {
+		return NewValidationError("risk_rating", "risk rating must be between 1 and 7")
+	}
+	if !f.Status.IsValid() {
+		return NewValidationError("status", "invalid fund status")
+	}
+	return nil
+}
+
+// IsValidISIN checks the format and check digit of an ISIN (ISO 6166)
+func IsValidISIN(isin string) bool {
+	if len(isin) != 12 {
+		return false
+	}
+	for i := 0; i < 2; i++ {
+		if isin[i] < 'A' || isin[i] > 'Z' {
+			return false
+		}
+	}
+
+	// Expand letters to two digits (A=10 ... Z=35) and apply the Luhn algorithm
+	var digits []int
+	for i := 0; i < 11; i++ {
+		c := isin[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, int(c-'0'))
+		case c >= 'A' && c <= 'Z':
+			v := int(c-'A') + 10
+			digits = append(digits, v/10, v%10)
+		default:
+			return false
+		}
+	}
+	check := isin[11]
+	if check < '0' || check > '9' {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == int(check-'0')
+}
+
+// isCurrencyCode checks the value looks like an ISO 4217 alphabetic code
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
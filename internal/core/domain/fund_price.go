@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// UnitPrecision is the number of decimal places units are allocated to
+const UnitPrecision = 6
+
+// FundPrice represents a fund's unit price at a valuation point. Single priced
+// funds quote the net asset value (NAV) as both bid and offer.
+type FundPrice struct {
+	ID            string
+	FundID        string
+	ValuationDate time.Time
+	Bid           decimal.Decimal
+	Offer         decimal.Decimal
+}
+
+// NewFundPrice creates a new dual priced fund price instance
+func NewFundPrice(fundID string, valuationDate time.Time, bid, offer decimal.Decimal) *FundPrice {
+	return &FundPrice{
+		ID:            uuid.New().String(),
+		FundID:        fundID,
+		ValuationDate: valuationDate.UTC(),
+		Bid:           bid,
+		Offer:         offer,
+	}
+}
+
+// NewNAVFundPrice creates a new single priced fund price instance
+func NewNAVFundPrice(fundID string, valuationDate time.Time, nav decimal.Decimal) *FundPrice {
+	return NewFundPrice(fundID, valuationDate, nav, nav)
+}
+
+// IsSinglePriced reports whether the price is a single NAV rather than a bid/offer spread
+func (p *FundPrice) IsSinglePriced() bool {
+	return p.Bid.Equal(p.Offer)
+}
+
+// Validate checks the price is usable for dealing
+func (p *FundPrice) Validate() error {
+	if p.FundID == "" {
+		return NewValidationError("fund_id", "fund ID is required")
+	}
+	if p.ValuationDate.IsZero() {
+		return NewValidationError("valuation_date", "valuation date is required")
+	}
+	if !p.Bid.IsPositive() || !p.Offer.IsPositive() {
+		return NewValidationError("prices", "prices must be positive")
+	}
+	if p.Offer.LessThan(p.Bid) {
+		return NewValidationError("offer", "offer price cannot be below bid price")
+	}
+	return nil
+}
+
+// PriceImport summarises the outcome of importing fund prices
+type PriceImport struct {
+	FundID             string
+	PricesImported     int
+	TransactionsPriced int
+}
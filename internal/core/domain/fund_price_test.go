@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewNAVFundPrice(t *testing.T) {
+	nav := decimal.RequireFromString("1.2345")
+	price := NewNAVFundPrice("fund-id", time.Now(), nav)
+
+	if price.ID == "" {
+		t.Error("Expected ID to be generated, got empty string")
+	}
+
+	if !price.IsSinglePriced() {
+		t.Error("Expected NAV price to be single priced")
+	}
+
+	if err := price.Validate(); err != nil {
+		t.Errorf("Expected price to be valid, got %v", err)
+	}
+}
+
+func TestFundPrice_Validate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		price *FundPrice
+	}{
+		{name: "missing fund", price: NewNAVFundPrice("", now, decimal.NewFromInt(1))},
+		{name: "missing valuation date", price: NewNAVFundPrice("fund-id", time.Time{}, decimal.NewFromInt(1))},
+		{name: "zero price", price: NewNAVFundPrice("fund-id", now, decimal.Zero)},
+		{name: "offer below bid", price: NewFundPrice("fund-id", now, decimal.NewFromInt(2), decimal.NewFromInt(1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.price.Validate(); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
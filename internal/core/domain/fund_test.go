@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestNewFund(t *testing.T) {
+	fund := NewFund("Cushon Global Bond Fund", "gb00b3x7qg63", AssetClassBond, "gbp", 3)
+
+	if fund.ID == "" {
+		t.Error("Expected ID to be generated, got empty string")
+	}
+
+	if fund.ISIN != "GB00B3X7QG63" {
+		t.Errorf("Expected ISIN to be upper-cased, got %s", fund.ISIN)
+	}
+
+	if fund.Currency != "GBP" {
+		t.Errorf("Expected currency to be upper-cased, got %s", fund.Currency)
+	}
+
+	if !fund.IsOpen() {
+		t.Error("Expected new fund to be open")
+	}
+
+	if err := fund.Validate(); err != nil {
+		t.Errorf("Expected new fund to be valid, got %v", err)
+	}
+}
+
+func TestFund_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(f *Fund)
+	}{
+		{name: "empty name", modify: func(f *Fund) { f.Name = " " }},
+		{name: "invalid ISIN", modify: func(f *Fund) { f.ISIN = "GB00B3X7QG64" }},
+		{name: "invalid asset class", modify: func(f *Fund) { f.AssetClass = "crypto" }},
+		{name: "invalid currency", modify: func(f *Fund) { f.Currency = "POUNDS" }},
+		{name: "risk rating too low", modify: func(f *Fund) { f.RiskRating = 0 }},
+		{name: "risk rating too high", modify: func(f *Fund) { f.RiskRating = 8 }},
+		{name: "invalid status", modify: func(f *Fund) { f.Status = "suspended" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fund := NewFund(CushonEquitiesFund, "GB00B3X7QG63", AssetClassEquity, "GBP", 5)
+			tt.modify(fund)
+
+			if err := fund.Validate(); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestIsValidISIN(t *testing.T) {
+	tests := []struct {
+		isin     string
+		expected bool
+	}{
+		{isin: "US0378331005", expected: true},
+		{isin: "GB0002634946", expected: true},
+		{isin: "GB00B3X7QG63", expected: true},
+		{isin: "US0378331006", expected: false},
+		{isin: "US037833100", expected: false},
+		{isin: "0S0378331005", expected: false},
+		{isin: "us0378331005", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isin, func(t *testing.T) {
+			if got := IsValidISIN(tt.isin); got != tt.expected {
+				t.Errorf("Expected IsValidISIN(%s) to be %v, got %v", tt.isin, tt.expected, got)
+			}
+		})
+	}
+}
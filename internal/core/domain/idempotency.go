@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord is what is kept of a request sent with an idempotency key,
+// so a retry of the request gets the first response rather than repeating it
+type IdempotencyRecord struct {
+	// Principal is the subject of the principal that sent the request. Keys are
+	// scoped to it, so two clients choosing the same key cannot collide.
+	Principal string
+	// Key is the idempotency key the client chose
+	Key string
+	// RequestHash identifies the request, so a key reused for another request is caught
+	RequestHash string
+	// StatusCode, ContentType and Body are the response, unset until the request completes
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	// ExpiresAt is when the key may be used again for a new request: the end
+	// of the pending TTL while the request runs, then of the TTL its response
+	// is replayed for
+	ExpiresAt time.Time
+}
+
+// NewIdempotencyRecord creates the record of a request that has not completed
+// yet. Until it completes the key is held for pendingTTL, so a request that
+// never completes, because the server stopped, frees the key soon after.
+func NewIdempotencyRecord(principal, key, requestHash string, now time.Time, pendingTTL time.Duration) *IdempotencyRecord {
+	return &IdempotencyRecord{
+		Principal:   principal,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(pendingTTL),
+	}
+}
+
+// Complete records the response to the request, to be replayed until ttl after now
+func (r *IdempotencyRecord) Complete(statusCode int, contentType string, body []byte, now time.Time, ttl time.Duration) {
+	r.StatusCode = statusCode
+	r.ContentType = contentType
+	r.Body = body
+	r.ExpiresAt = now.Add(ttl)
+}
+
+// IsCompleted reports whether the request has a response to replay
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
+
+// SameReservation reports whether r and other record the same reservation of
+// a key, rather than one made after the other expired or was released
+func (r *IdempotencyRecord) SameReservation(other *IdempotencyRecord) bool {
+	return r.Principal == other.Principal && r.Key == other.Key &&
+		r.RequestHash == other.RequestHash && r.CreatedAt.Equal(other.CreatedAt)
+}
+
+// IsExpired reports whether the key is free to use again at now
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
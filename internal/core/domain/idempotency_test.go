@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdempotencyRecord(t *testing.T) {
+	now := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	record := NewIdempotencyRecord("user-1", "key-1", "hash", now, time.Minute)
+
+	if record.IsCompleted() {
+		t.Error("Expected a new record not to be completed")
+	}
+	if record.IsExpired(now.Add(59 * time.Second)) {
+		t.Error("Expected the record not to have expired within its pending TTL")
+	}
+	if !record.IsExpired(now.Add(time.Minute)) {
+		t.Error("Expected a request that never completed to free its key at the end of its pending TTL")
+	}
+
+	completedAt := now.Add(30 * time.Second)
+	record.Complete(201, "application/json", []byte(`{}`), completedAt, time.Hour)
+	if !record.IsCompleted() {
+		t.Error("Expected the record to be completed once it has a response")
+	}
+	if record.IsExpired(completedAt.Add(59 * time.Minute)) {
+		t.Error("Expected the record not to have expired within its TTL")
+	}
+	if !record.IsExpired(completedAt.Add(time.Hour)) {
+		t.Error("Expected the record to have expired at the end of its TTL")
+	}
+	// Completing a reservation keeps it the same reservation; a retry's is another
+	if !record.SameReservation(NewIdempotencyRecord("user-1", "key-1", "hash", now, time.Minute)) {
+		t.Error("Expected the completed record to be the same reservation")
+	}
+	if record.SameReservation(NewIdempotencyRecord("user-1", "key-1", "hash", now.Add(time.Minute), time.Minute)) {
+		t.Error("Expected a later reservation of the key not to be the same")
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Holding represents a user's position in a single fund
+type Holding struct {
+	FundName FundName
+	// Units is the number of units held across all priced transactions
+	Units decimal.Decimal
+	// BookCost is the net cash invested in priced transactions
+	BookCost decimal.Decimal
+	// Price is the latest fund price, nil if the fund has never been priced
+	Price *FundPrice
+	// MarketValue is the units held valued at the latest bid price
+	MarketValue decimal.Decimal
+	// PendingAmount is the net cash awaiting the next valuation point
+	PendingAmount decimal.Decimal
+}
+
+// Portfolio represents a user's holdings across every fund they have invested in
+type Portfolio struct {
+	UserID        string
+	Holdings      []*Holding
+	BookCost      decimal.Decimal
+	MarketValue   decimal.Decimal
+	PendingAmount decimal.Decimal
+	ValuedAt      time.Time
+}
+
+// NewPortfolio creates a portfolio from its holdings, totalling their values
+func NewPortfolio(userID string, holdings []*Holding, valuedAt time.Time) *Portfolio {
+	portfolio := &Portfolio{
+		UserID:        userID,
+		Holdings:      holdings,
+		BookCost:      decimal.Zero,
+		MarketValue:   decimal.Zero,
+		PendingAmount: decimal.Zero,
+		ValuedAt:      valuedAt.UTC(),
+	}
+
+	for _, holding := range holdings {
+		portfolio.BookCost = portfolio.BookCost.Add(holding.BookCost)
+		portfolio.MarketValue = portfolio.MarketValue.Add(holding.MarketValue)
+		portfolio.PendingAmount = portfolio.PendingAmount.Add(holding.PendingAmount)
+	}
+
+	return portfolio
+}
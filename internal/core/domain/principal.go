@@ -0,0 +1,94 @@
+package domain
+
+import "context"
+
+// Role represents what kind of user a principal is, which decides the
+// permissions it holds
+type Role string
+
+const (
+	// RoleCustomer marks a direct user, who may only see and change their own account
+	RoleCustomer Role = "customer"
+	// RoleSupport marks support staff, who may see any account but change nothing
+	RoleSupport Role = "support"
+	// RoleOperations marks operations staff, who run accounts, correct transactions and manage funds
+	RoleOperations Role = "operations"
+	// RoleAdmin marks an administrator, who may do anything
+	RoleAdmin Role = "admin"
+)
+
+// IsValid checks if the role is a known role
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleCustomer, RoleSupport, RoleOperations, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Permission names something a principal may be allowed to do
+type Permission string
+
+const (
+	// PermissionViewAccount allows reading a direct user, their transactions, allowance and portfolio
+	PermissionViewAccount Permission = "account:view"
+	// PermissionCreateDirectUser allows onboarding a direct user
+	PermissionCreateDirectUser Permission = "direct-user:create"
+	// PermissionUpdateDirectUser allows renaming a direct user
+	PermissionUpdateDirectUser Permission = "direct-user:update"
+	// PermissionDeleteDirectUser allows deleting a direct user
+	PermissionDeleteDirectUser Permission = "direct-user:delete"
+	// PermissionCreateTransaction allows placing a deposit or withdrawal
+	PermissionCreateTransaction Permission = "transaction:create"
+	// PermissionBookTransaction allows booking a fee, interest or transfer against an account
+	PermissionBookTransaction Permission = "transaction:book"
+	// PermissionCorrectTransaction allows correcting and reversing a transaction
+	PermissionCorrectTransaction Permission = "transaction:correct"
+	// PermissionViewFunds allows reading the fund catalogue and prices
+	PermissionViewFunds Permission = "fund:view"
+	// PermissionManageFunds allows changing the fund catalogue and importing prices
+	PermissionManageFunds Permission = "fund:manage"
+	// PermissionViewAuditLog allows reading the record of changes to direct users and transactions
+	PermissionViewAuditLog Permission = "audit:view"
+)
+
+// Principal identifies who is making a request, as established by the
+// adapter that received it
+type Principal struct {
+	// Subject identifies the principal. For a customer it is the ID of their direct user.
+	Subject string
+	Role    Role
+}
+
+// IsCustomer reports whether the principal is a customer rather than a member of staff
+func (p *Principal) IsCustomer() bool {
+	return p.Role == RoleCustomer
+}
+
+// CanAccessUser reports whether the principal may see and change the direct
+// user's account: customers only their own, staff any
+func (p *Principal) CanAccessUser(userID string) bool {
+	if p.IsCustomer() {
+		return p.Subject == userID
+	}
+	return p.Role.IsValid()
+}
+
+// principalKey is the context key the principal is stored under
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal ctx carries, or
+// ErrUnauthenticated if it carries none
+func PrincipalFromContext(ctx context.Context) (*Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	if !ok || principal == nil {
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
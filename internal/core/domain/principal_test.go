@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPrincipal_CanAccessUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		userID    string
+		want      bool
+	}{
+		{name: "customer's own account", principal: Principal{Subject: "user-1", Role: RoleCustomer}, userID: "user-1", want: true},
+		{name: "another customer's account", principal: Principal{Subject: "user-1", Role: RoleCustomer}, userID: "user-2", want: false},
+		{name: "support", principal: Principal{Subject: "help@cushon.co.uk", Role: RoleSupport}, userID: "user-2", want: true},
+		{name: "admin", principal: Principal{Subject: "ops@cushon.co.uk", Role: RoleAdmin}, userID: "user-2", want: true},
+		{name: "unknown role", principal: Principal{Subject: "user-1", Role: "auditor"}, userID: "user-1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.CanAccessUser(tt.userID); got != tt.want {
+				t.Errorf("CanAccessUser(%q) = %v, want %v", tt.userID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	if _, err := PrincipalFromContext(context.Background()); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated without a principal, got %v", err)
+	}
+
+	want := &Principal{Subject: "user-1", Role: RoleCustomer}
+	got, err := PrincipalFromContext(ContextWithPrincipal(context.Background(), want))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Expected the principal put on the context, got %+v", got)
+	}
+}
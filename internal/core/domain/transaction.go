@@ -1,24 +1,195 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
-// Transaction represents a financial transaction in the system
+// TransactionType represents the direction and purpose of a transaction
+type TransactionType string
+
+const (
+	TransactionTypeDeposit     TransactionType = "deposit"
+	TransactionTypeWithdrawal  TransactionType = "withdrawal"
+	TransactionTypeFee         TransactionType = "fee"
+	TransactionTypeInterest    TransactionType = "interest"
+	TransactionTypeTransferIn  TransactionType = "transfer-in"
+	TransactionTypeTransferOut TransactionType = "transfer-out"
+)
+
+// IsValid checks if the transaction type is a known type
+func (t TransactionType) IsValid() bool {
+	switch t {
+	case TransactionTypeDeposit, TransactionTypeWithdrawal, TransactionTypeFee,
+		TransactionTypeInterest, TransactionTypeTransferIn, TransactionTypeTransferOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDebit reports whether the transaction type takes money out of a user's balance
+func (t TransactionType) IsDebit() bool {
+	switch t {
+	case TransactionTypeWithdrawal, TransactionTypeFee, TransactionTypeTransferOut:
+		return true
+	default:
+		return false
+	}
+}
+
+// Permission returns the permission needed to place a transaction of this
+// type. Deposits and withdrawals are the account holder's own; fees, interest
+// and transfers are booked by operations.
+func (t TransactionType) Permission() Permission {
+	switch t {
+	case TransactionTypeDeposit, TransactionTypeWithdrawal:
+		return PermissionCreateTransaction
+	default:
+		return PermissionBookTransaction
+	}
+}
+
+// Transaction represents a financial transaction in the system. The amount is
+// always positive, the type gives its direction. Transactions are forward
+// priced: units are allocated at the first valuation point after the
+// transaction is placed, until then the transaction is pending.
+//
+// Transactions are never changed or removed once recorded. A mistake is
+// corrected by a reversal: a new entry of the same type and amount that
+// references the original and cancels its effect.
 type Transaction struct {
-	ID       string
-	UserID   string
-	Amount   decimal.Decimal
-	FundName FundName
+	ID            string
+	UserID        string
+	Type          TransactionType
+	Amount        decimal.Decimal
+	FundName      FundName
+	Units         decimal.Decimal
+	UnitPrice     decimal.Decimal
+	ValuationDate *time.Time
+	// ReversalOf is the ID of the transaction this entry reverses, empty for original entries
+	ReversalOf string
+	// Reason explains why a reversal or correcting entry was made
+	Reason string
+	// Actor identifies who made a reversal or correcting entry
+	Actor string
+	// CreatedAt is when the transaction was placed
+	CreatedAt time.Time
+	// UpdatedAt is when the transaction was last changed, which is only when units are allocated
+	UpdatedAt time.Time
 }
 
-// NewTransaction creates a new transaction instance
-func NewTransaction(userID string, amount decimal.Decimal, fundName FundName) *Transaction {
+// NewTransaction creates a new transaction instance placed at now
+func NewTransaction(userID string, transactionType TransactionType, amount decimal.Decimal, fundName FundName, now time.Time) *Transaction {
 	return &Transaction{
-		ID:       uuid.New().String(),
-		UserID:   userID,
-		Amount:   amount,
-		FundName: fundName,
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      transactionType,
+		Amount:    amount,
+		FundName:  fundName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsReversal reports whether the transaction reverses another transaction
+func (t *Transaction) IsReversal() bool {
+	return t.ReversalOf != ""
+}
+
+// IsDebit reports whether the transaction takes money out of the user's
+// balance: a debit type, or the reversal of a credit
+func (t *Transaction) IsDebit() bool {
+	return t.Type.IsDebit() != t.IsReversal()
+}
+
+// SignedAmount returns the amount as it affects the user's balance, negative for debits
+func (t *Transaction) SignedAmount() decimal.Decimal {
+	if t.IsDebit() {
+		return t.Amount.Neg()
+	}
+	return t.Amount
+}
+
+// IsPriced reports whether units have been allocated to the transaction
+func (t *Transaction) IsPriced() bool {
+	return t.ValuationDate != nil
+}
+
+// Allocate prices the transaction, buying units at the offer price for credits
+// or selling them at the bid price for debits. Units sold are negative and
+// units are rounded towards zero. A reversal is priced on the same side as the
+// transaction it reverses so the two cancel out exactly.
+func (t *Transaction) Allocate(price *FundPrice, now time.Time) error {
+	if t.IsPriced() {
+		return errors.New("transaction is already priced")
+	}
+
+	unitPrice := price.Offer
+	if t.Type.IsDebit() {
+		unitPrice = price.Bid
+	}
+
+	valuationDate := price.ValuationDate
+	t.UnitPrice = unitPrice
+	t.Units = t.SignedAmount().DivRound(unitPrice, UnitPrecision+2).Truncate(UnitPrecision)
+	t.ValuationDate = &valuationDate
+	t.UpdatedAt = now
+	return nil
+}
+
+// Reverse creates the entry reversing the transaction. A priced transaction is
+// reversed at its own price, so the reversal gives back exactly the units it
+// allocated; the reversal of a pending transaction is priced alongside it.
+func (t *Transaction) Reverse(reason, actor string, now time.Time) (*Transaction, error) {
+	if t.IsReversal() {
+		return nil, NewConflictError("a reversal cannot be reversed")
+	}
+
+	reversal := NewTransaction(t.UserID, t.Type, t.Amount, t.FundName, now)
+	reversal.ReversalOf = t.ID
+	reversal.Reason = reason
+	reversal.Actor = actor
+
+	if t.IsPriced() {
+		valuationDate := *t.ValuationDate
+		reversal.Units = t.Units.Neg()
+		reversal.UnitPrice = t.UnitPrice
+		reversal.ValuationDate = &valuationDate
 	}
-} 
\ No newline at end of file
+
+	return reversal, nil
+}
+
+// Reversal records the reversal of a transaction and, when the transaction
+// was corrected rather than cancelled, the entry replacing it
+type Reversal struct {
+	Original   *Transaction
+	Reversal   *Transaction
+	Correction *Transaction
+}
+
+// InsufficientBalanceError is returned when a debit exceeds the user's available balance in a fund
+type InsufficientBalanceError struct {
+	FundName  FundName
+	Available decimal.Decimal
+	Requested decimal.Decimal
+}
+
+// Error implements the error interface
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient balance in %s: %s available, %s requested",
+		e.FundName,
+		e.Available.StringFixed(2),
+		e.Requested.StringFixed(2),
+	)
+}
+
+// Is reports whether target is ErrInsufficientBalance
+func (e *InsufficientBalanceError) Is(target error) bool {
+	return target == ErrInsufficientBalance
+}
@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+const (
+	// DefaultPageSize is the number of transactions in a page when no limit is given
+	DefaultPageSize = 50
+	// MaxPageSize is the most transactions a page can hold
+	MaxPageSize = 200
+)
+
+// SortOrder is the order a transaction history is listed in
+type SortOrder string
+
+const (
+	SortNewestFirst SortOrder = "-created_at"
+	SortOldestFirst SortOrder = "created_at"
+)
+
+// IsValid checks if the sort order is a known order
+func (o SortOrder) IsValid() bool {
+	return o == SortNewestFirst || o == SortOldestFirst
+}
+
+// TransactionCursor marks the last transaction of a page. The next page
+// starts after it in the query's sort order. Transactions are ordered by the
+// time they were recorded and then by ID, so the position is exact even when
+// several share a time.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// TransactionQuery selects a page of a user's transaction history. Zero
+// valued filters match every transaction.
+type TransactionQuery struct {
+	UserID string
+	// From and To bound the time the transactions were recorded to the half-open interval [From, To)
+	From time.Time
+	To   time.Time
+	// FundName restricts the history to one fund
+	FundName FundName
+	// Type restricts the history to one transaction type, reversals included
+	Type  TransactionType
+	Sort  SortOrder
+	Limit int
+	// After is the cursor of the previous page, nil for the first page
+	After *TransactionCursor
+}
+
+// Validate checks the query is complete and consistent, after defaults are applied
+func (q *TransactionQuery) Validate() error {
+	if q.UserID == "" {
+		return NewValidationError("user_id", "user ID is required")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return NewValidationError("to", "to must be after from")
+	}
+	if q.Type != "" && !q.Type.IsValid() {
+		return NewValidationError("type", "invalid transaction type")
+	}
+	if !q.Sort.IsValid() {
+		return NewValidationError("sort", "sort must be created_at or -created_at")
+	}
+	if q.Limit < 1 || q.Limit > MaxPageSize {
+		return NewValidationError("limit", "limit must be between 1 and 200")
+	}
+	return nil
+}
+
+// Matches reports whether a transaction passes the query's filters. The
+// cursor is not considered.
+func (q *TransactionQuery) Matches(transaction *Transaction) bool {
+	return transaction.UserID == q.UserID &&
+		(q.From.IsZero() || !transaction.CreatedAt.Before(q.From)) &&
+		(q.To.IsZero() || transaction.CreatedAt.Before(q.To)) &&
+		(q.FundName == "" || transaction.FundName == q.FundName) &&
+		(q.Type == "" || transaction.Type == q.Type)
+}
+
+// TransactionPage is one page of a user's transaction history
+type TransactionPage struct {
+	Transactions []*Transaction
+	// Total counts every transaction matching the query's filters, across all pages
+	Total int
+	// Next is the cursor for the following page, nil on the last page
+	Next *TransactionCursor
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTransactionQuery_Matches(t *testing.T) {
+	recorded := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	transaction := NewTransaction("user123", TransactionTypeDeposit, decimal.NewFromInt(100), CushonEquitiesFund, recorded)
+
+	tests := []struct {
+		name     string
+		query    TransactionQuery
+		expected bool
+	}{
+		{"no filters", TransactionQuery{UserID: "user123"}, true},
+		{"another user", TransactionQuery{UserID: "user456"}, false},
+		{"from is inclusive", TransactionQuery{UserID: "user123", From: recorded}, true},
+		{"to is exclusive", TransactionQuery{UserID: "user123", To: recorded}, false},
+		{"within interval", TransactionQuery{UserID: "user123", From: recorded.Add(-time.Hour), To: recorded.Add(time.Hour)}, true},
+		{"same fund", TransactionQuery{UserID: "user123", FundName: CushonEquitiesFund}, true},
+		{"another fund", TransactionQuery{UserID: "user123", FundName: "Another Fund"}, false},
+		{"same type", TransactionQuery{UserID: "user123", Type: TransactionTypeDeposit}, true},
+		{"another type", TransactionQuery{UserID: "user123", Type: TransactionTypeWithdrawal}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(transaction); got != tt.expected {
+				t.Errorf("Expected Matches to be %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
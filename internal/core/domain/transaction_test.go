@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -11,7 +12,7 @@ func TestNewTransaction(t *testing.T) {
 	amount := decimal.NewFromFloat(1000.50)
 	fundName := FundName("Cushon Equities Fund")
 
-	transaction := NewTransaction(userID, amount, fundName)
+	transaction := NewTransaction(userID, TransactionTypeDeposit, amount, fundName, time.Now())
 
 	// Test user ID is set correctly
 	if transaction.UserID != userID {
@@ -23,6 +24,11 @@ func TestNewTransaction(t *testing.T) {
 		t.Errorf("Expected Amount to be %s, got %s", amount.String(), transaction.Amount.String())
 	}
 
+	// Test type is set correctly
+	if transaction.Type != TransactionTypeDeposit {
+		t.Errorf("Expected Type to be %s, got %s", TransactionTypeDeposit, transaction.Type)
+	}
+
 	// Test fund name is set correctly
 	if transaction.FundName != fundName {
 		t.Errorf("Expected FundName to be %s, got %s", fundName, transaction.FundName)
@@ -32,4 +38,168 @@ func TestNewTransaction(t *testing.T) {
 	if transaction.ID == "" {
 		t.Error("Expected ID to be generated, got empty string")
 	}
-} 
\ No newline at end of file
+} 
+
+func TestTransaction_Allocate(t *testing.T) {
+	valuationDate := time.Date(2025, time.January, 2, 12, 0, 0, 0, time.UTC)
+	price := NewFundPrice("fund-id", valuationDate, decimal.RequireFromString("1.9800"), decimal.RequireFromString("2.0000"))
+
+	tests := []struct {
+		name          string
+		txType        TransactionType
+		amount        decimal.Decimal
+		expectedUnits decimal.Decimal
+		expectedPrice decimal.Decimal
+	}{
+		{
+			name:          "deposit buys at offer",
+			txType:        TransactionTypeDeposit,
+			amount:        decimal.RequireFromString("1000.00"),
+			expectedUnits: decimal.RequireFromString("500"),
+			expectedPrice: price.Offer,
+		},
+		{
+			name:          "withdrawal sells at bid",
+			txType:        TransactionTypeWithdrawal,
+			amount:        decimal.RequireFromString("99.00"),
+			expectedUnits: decimal.RequireFromString("-50"),
+			expectedPrice: price.Bid,
+		},
+		{
+			name:          "fractional units",
+			txType:        TransactionTypeInterest,
+			amount:        decimal.RequireFromString("0.01"),
+			expectedUnits: decimal.RequireFromString("0.005"),
+			expectedPrice: price.Offer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := NewTransaction("user123", tt.txType, tt.amount, CushonEquitiesFund, time.Now())
+
+			if transaction.IsPriced() {
+				t.Fatal("Expected new transaction to be pending")
+			}
+
+			if err := transaction.Allocate(price, time.Now()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !transaction.Units.Equal(tt.expectedUnits) {
+				t.Errorf("Expected units %s, got %s", tt.expectedUnits, transaction.Units)
+			}
+
+			if !transaction.UnitPrice.Equal(tt.expectedPrice) {
+				t.Errorf("Expected unit price %s, got %s", tt.expectedPrice, transaction.UnitPrice)
+			}
+
+			if !transaction.ValuationDate.Equal(valuationDate) {
+				t.Errorf("Expected valuation date %s, got %s", valuationDate, transaction.ValuationDate)
+			}
+
+			if err := transaction.Allocate(price, time.Now()); err == nil {
+				t.Error("Expected error allocating an already priced transaction, got nil")
+			}
+		})
+	}
+}
+
+func TestTransaction_Allocate_Truncates(t *testing.T) {
+	price := NewNAVFundPrice("fund-id", time.Now(), decimal.RequireFromString("3"))
+	transaction := NewTransaction("user123", TransactionTypeDeposit, decimal.RequireFromString("100"), CushonEquitiesFund, time.Now())
+
+	transaction.Allocate(price, time.Now())
+
+	if !transaction.Units.Equal(decimal.RequireFromString("33.333333")) {
+		t.Errorf("Expected units 33.333333, got %s", transaction.Units)
+	}
+}
+
+func TestTransactionType(t *testing.T) {
+	tests := []struct {
+		txType        TransactionType
+		expectedDebit bool
+	}{
+		{txType: TransactionTypeDeposit, expectedDebit: false},
+		{txType: TransactionTypeWithdrawal, expectedDebit: true},
+		{txType: TransactionTypeFee, expectedDebit: true},
+		{txType: TransactionTypeInterest, expectedDebit: false},
+		{txType: TransactionTypeTransferIn, expectedDebit: false},
+		{txType: TransactionTypeTransferOut, expectedDebit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.txType), func(t *testing.T) {
+			if !tt.txType.IsValid() {
+				t.Errorf("Expected %s to be valid", tt.txType)
+			}
+
+			if tt.txType.IsDebit() != tt.expectedDebit {
+				t.Errorf("Expected IsDebit to be %v", tt.expectedDebit)
+			}
+
+			transaction := NewTransaction("user123", tt.txType, decimal.NewFromInt(10), CushonEquitiesFund, time.Now())
+			if transaction.SignedAmount().IsNegative() != tt.expectedDebit {
+				t.Errorf("Expected signed amount sign to match debit, got %s", transaction.SignedAmount())
+			}
+		})
+	}
+
+	if TransactionType("refund").IsValid() {
+		t.Error("Expected unknown type to be invalid")
+	}
+}
+
+func TestTransaction_Reverse(t *testing.T) {
+	price := NewFundPrice("fund-id", time.Now(), decimal.RequireFromString("1.9800"), decimal.RequireFromString("2.0000"))
+
+	t.Run("priced transaction", func(t *testing.T) {
+		original := NewTransaction("user123", TransactionTypeDeposit, decimal.NewFromInt(1000), CushonEquitiesFund, time.Now())
+		original.Allocate(price, time.Now())
+
+		reversal, err := original.Reverse("duplicate payment", "ops@cushon.co.uk", time.Now())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if reversal.ID == original.ID || reversal.ReversalOf != original.ID {
+			t.Errorf("Expected a new entry referencing %s, got %s reversing %s", original.ID, reversal.ID, reversal.ReversalOf)
+		}
+		if reversal.Type != original.Type || !reversal.Amount.Equal(original.Amount) {
+			t.Errorf("Expected reversal to keep the type and amount, got %s %s", reversal.Type, reversal.Amount)
+		}
+		if !reversal.IsDebit() || !reversal.SignedAmount().Add(original.SignedAmount()).IsZero() {
+			t.Errorf("Expected reversal to cancel the original, got signed amount %s", reversal.SignedAmount())
+		}
+		if !reversal.IsPriced() || !reversal.Units.Add(original.Units).IsZero() {
+			t.Errorf("Expected reversal to give back %s units, got %s", original.Units, reversal.Units)
+		}
+		if reversal.Reason != "duplicate payment" || reversal.Actor != "ops@cushon.co.uk" {
+			t.Errorf("Expected reason and actor to be recorded, got %q by %q", reversal.Reason, reversal.Actor)
+		}
+
+		if _, err := reversal.Reverse("undo", "ops@cushon.co.uk", time.Now()); err == nil {
+			t.Error("Expected error reversing a reversal, got nil")
+		}
+	})
+
+	t.Run("pending transaction", func(t *testing.T) {
+		original := NewTransaction("user123", TransactionTypeWithdrawal, decimal.NewFromInt(99), CushonEquitiesFund, time.Now())
+
+		reversal, err := original.Reverse("entered in error", "ops@cushon.co.uk", time.Now())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if reversal.IsPriced() {
+			t.Fatal("Expected reversal of a pending transaction to be pending")
+		}
+
+		// Both are priced at the same valuation point on the bid side
+		original.Allocate(price, time.Now())
+		reversal.Allocate(price, time.Now())
+		if !reversal.UnitPrice.Equal(price.Bid) || !reversal.Units.Add(original.Units).IsZero() {
+			t.Errorf("Expected reversal to cancel %s units at %s, got %s at %s", original.Units, price.Bid, reversal.Units, reversal.UnitPrice)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// AuditService defines the input port for reading the audit log
+type AuditService interface {
+	// GetAuditLog retrieves a page of the changes made to direct users or transactions
+	GetAuditLog(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error)
+}
@@ -1,18 +1,22 @@
 package input
 
-import "cushon/internal/core/domain"
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
 
 // DirectUserService defines the input port for direct user operations
 type DirectUserService interface {
 	// CreateDirectUser creates a new direct user
-	CreateDirectUser(name string) (*domain.DirectUser, error)
+	CreateDirectUser(ctx context.Context, name string) (*domain.DirectUser, error)
 	
 	// GetDirectUser retrieves a direct user by ID
-	GetDirectUser(id string) (*domain.DirectUser, error)
+	GetDirectUser(ctx context.Context, id string) (*domain.DirectUser, error)
 	
-	// UpdateDirectUser updates an existing direct user
-	UpdateDirectUser(user *domain.DirectUser) error
+	// UpdateDirectUser renames an existing direct user, filling in user with the stored user
+	UpdateDirectUser(ctx context.Context, user *domain.DirectUser) error
 	
 	// DeleteDirectUser deletes a direct user by ID
-	DeleteDirectUser(id string) error
+	DeleteDirectUser(ctx context.Context, id string) error
 } 
\ No newline at end of file
@@ -0,0 +1,12 @@
+// Package input defines the ports primary adapters use to drive the core.
+//
+// Every method acts for the principal the caller's context carries, put
+// there with domain.ContextWithPrincipal by the adapter that authenticated
+// the request. The services check what the principal may do:
+//
+//   - A context without a principal is refused with an error matching
+//     domain.ErrUnauthenticated.
+//   - A customer may only read and change their own direct user, its
+//     transactions and its portfolio. Anything else is refused with an error
+//     matching domain.ErrForbidden.
+package input
@@ -0,0 +1,28 @@
+package input
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// FundService defines the input port for fund catalogue operations
+type FundService interface {
+	// CreateFund adds a new fund to the catalogue
+	CreateFund(ctx context.Context, fund *domain.Fund) (*domain.Fund, error)
+
+	// GetFund retrieves a fund by ID
+	GetFund(ctx context.Context, id string) (*domain.Fund, error)
+
+	// ListFunds retrieves every fund in the catalogue
+	ListFunds(ctx context.Context) ([]*domain.Fund, error)
+
+	// ListOpenFundNames retrieves the names of funds accepting new investments
+	ListOpenFundNames(ctx context.Context) ([]domain.FundName, error)
+
+	// UpdateFund updates an existing fund
+	UpdateFund(ctx context.Context, fund *domain.Fund) error
+
+	// DeleteFund removes a fund from the catalogue by ID
+	DeleteFund(ctx context.Context, id string) error
+}
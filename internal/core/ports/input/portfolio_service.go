@@ -0,0 +1,13 @@
+package input
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// PortfolioService defines the input port for portfolio valuation
+type PortfolioService interface {
+	// GetPortfolio values a direct user's holdings at the latest fund prices
+	GetPortfolio(ctx context.Context, userID string) (*domain.Portfolio, error)
+}
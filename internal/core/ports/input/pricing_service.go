@@ -0,0 +1,17 @@
+package input
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// PricingService defines the input port for fund pricing operations
+type PricingService interface {
+	// ImportPrices records prices for a fund and allocates units to the
+	// pending transactions each new valuation point prices
+	ImportPrices(ctx context.Context, fundID string, prices []*domain.FundPrice) (*domain.PriceImport, error)
+
+	// GetFundPrices retrieves the price history for a fund
+	GetFundPrices(ctx context.Context, fundID string) ([]*domain.FundPrice, error)
+}
@@ -1,24 +1,33 @@
 package input
 
 import (
-	"github.com/shopspring/decimal"
+	"context"
+
 	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
 )
 
 // TransactionService defines the input port for transaction operations
 type TransactionService interface {
-	// CreateTransaction creates a new transaction
-	CreateTransaction(userID string, amount decimal.Decimal, fundName domain.FundName) (*domain.Transaction, error)
+	// CreateTransaction creates a new transaction, rejecting debits that exceed the user's balance in the fund
+	CreateTransaction(ctx context.Context, userID string, transactionType domain.TransactionType, amount decimal.Decimal, fundName domain.FundName) (*domain.Transaction, error)
 	
 	// GetTransaction retrieves a transaction by ID
-	GetTransaction(id string) (*domain.Transaction, error)
+	GetTransaction(ctx context.Context, id string) (*domain.Transaction, error)
 	
-	// GetUserTransactions retrieves all transactions for a user
-	GetUserTransactions(userID string) ([]*domain.Transaction, error)
+	// GetUserTransactions retrieves a page of a user's transaction history.
+	// An unset sort or limit takes the default.
+	GetUserTransactions(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error)
+
+	// GetAllowance retrieves a user's ISA allowance for the current tax year
+	GetAllowance(ctx context.Context, userID string) (*domain.Allowance, error)
 	
-	// UpdateTransaction updates an existing transaction
-	UpdateTransaction(transaction *domain.Transaction) error
+	// CorrectTransaction reverses a transaction and records a corrected entry in its place.
+	// Both entries are attributed to the principal on ctx.
+	CorrectTransaction(ctx context.Context, id string, amount decimal.Decimal, fundName domain.FundName, reason string) (*domain.Reversal, error)
 	
-	// DeleteTransaction deletes a transaction by ID
-	DeleteTransaction(id string) error
+	// ReverseTransaction cancels a transaction by recording a reversing entry,
+	// attributed to the principal on ctx
+	ReverseTransaction(ctx context.Context, id, reason string) (*domain.Reversal, error)
 } 
\ No newline at end of file
@@ -0,0 +1,18 @@
+package output
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// AuditLog defines the output port for the record of changes made to direct
+// users and transactions. Entries are only ever added.
+type AuditLog interface {
+	// Record saves an entry. Made with the context of a unit of work, the
+	// entry is kept only if the change it records is.
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+
+	// FindPage retrieves a page of the entries matching the query, oldest first
+	FindPage(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error)
+}
@@ -0,0 +1,18 @@
+package output
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// Authorizer defines the output port for deciding what the principal making
+// a request may do, so the policy can change without touching the services
+type Authorizer interface {
+	// Authorize checks the principal on ctx holds the permission. ownerID is
+	// the direct user whose account the request touches, empty if it touches
+	// none; customers may only touch their own. It returns an error matching
+	// domain.ErrUnauthenticated if ctx carries no principal, or
+	// domain.ErrForbidden if the principal may not, recording the denial.
+	Authorize(ctx context.Context, permission domain.Permission, ownerID string) error
+}
@@ -0,0 +1,10 @@
+package output
+
+import "time"
+
+// Clock defines the output port for reading the current time, so services
+// can be tested at a fixed time
+type Clock interface {
+	// Now returns the current time in UTC
+	Now() time.Time
+}
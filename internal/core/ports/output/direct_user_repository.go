@@ -1,18 +1,22 @@
 package output
 
-import "cushon/internal/core/domain"
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
 
 // DirectUserRepository defines the output port for direct user persistence
 type DirectUserRepository interface {
 	// Save persists a direct user
-	Save(user *domain.DirectUser) error
+	Save(ctx context.Context, user *domain.DirectUser) error
 	
-	// FindByID retrieves a direct user by ID
-	FindByID(id string) (*domain.DirectUser, error)
+	// FindByID retrieves a direct user by ID, returning domain.ErrUserNotFound if there is none
+	FindByID(ctx context.Context, id string) (*domain.DirectUser, error)
 	
-	// Update updates an existing direct user
-	Update(user *domain.DirectUser) error
+	// Update updates an existing direct user, returning domain.ErrUserNotFound if there is none
+	Update(ctx context.Context, user *domain.DirectUser) error
 	
-	// Delete removes a direct user by ID
-	Delete(id string) error
+	// Delete removes a direct user by ID, returning domain.ErrUserNotFound if there is none
+	Delete(ctx context.Context, id string) error
 } 
\ No newline at end of file
@@ -0,0 +1,20 @@
+// Package output defines the ports the core uses to reach secondary adapters.
+//
+// Every method takes the caller's context first. Adapters pass it to the
+// queries they run, so a cancelled request or an expired deadline stops the
+// work and returns an error matching ctx.Err().
+//
+// Every repository adapter keeps the same contract for missing entities:
+//
+//   - A lookup of a single entity by its identity (FindByID, FindByName)
+//     returns an error matching domain.ErrNotFound, never a nil entity with a
+//     nil error.
+//   - Update and Delete of an entity that does not exist return an error
+//     matching domain.ErrNotFound.
+//   - Lookups where absence is an expected answer rather than a failure
+//     (FundPriceRepository.FindLatest, TransactionRepository.FindReversal)
+//     return nil with a nil error.
+//   - Queries returning a list return an empty list when nothing matches.
+//
+// The outputtest package checks an adapter keeps this contract.
+package output
@@ -0,0 +1,16 @@
+package output
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// EventPublisher defines the output port for delivering domain events to
+// systems outside the API
+type EventPublisher interface {
+	// Publish delivers an event, returning an error unless it was accepted.
+	// An event may be published more than once if the outbox cannot record
+	// that it was.
+	Publish(ctx context.Context, event *domain.Event) error
+}
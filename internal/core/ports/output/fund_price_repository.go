@@ -0,0 +1,19 @@
+package output
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// FundPriceRepository defines the output port for fund price persistence
+type FundPriceRepository interface {
+	// Save persists a fund price
+	Save(ctx context.Context, price *domain.FundPrice) error
+
+	// FindByFundID retrieves every price for a fund ordered by valuation date
+	FindByFundID(ctx context.Context, fundID string) ([]*domain.FundPrice, error)
+
+	// FindLatest retrieves the most recent price for a fund, nil if it has never been priced
+	FindLatest(ctx context.Context, fundID string) (*domain.FundPrice, error)
+}
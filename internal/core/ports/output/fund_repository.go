@@ -0,0 +1,30 @@
+package output
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+)
+
+// FundRepository defines the output port for fund catalogue persistence
+type FundRepository interface {
+	// Save persists a fund, returning domain.ErrFundExists if one with the
+	// same ID, name or ISIN is already in the catalogue
+	Save(ctx context.Context, fund *domain.Fund) error
+
+	// FindByID retrieves a fund by ID, returning domain.ErrFundNotFound if there is none
+	FindByID(ctx context.Context, id string) (*domain.Fund, error)
+
+	// FindByName retrieves a fund by its name, returning domain.ErrFundNotFound if there is none
+	FindByName(ctx context.Context, name domain.FundName) (*domain.Fund, error)
+
+	// FindAll retrieves every fund in the catalogue
+	FindAll(ctx context.Context) ([]*domain.Fund, error)
+
+	// Update updates an existing fund, returning domain.ErrFundNotFound if
+	// there is none or domain.ErrFundISINTaken if another fund has its ISIN
+	Update(ctx context.Context, fund *domain.Fund) error
+
+	// Delete removes a fund by ID, returning domain.ErrFundNotFound if there is none
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"cushon/internal/core/domain"
+)
+
+// IdempotencyRepository defines the output port for keeping the requests
+// made with idempotency keys and their responses
+type IdempotencyRepository interface {
+	// Reserve saves record before its request runs, unless a record that has
+	// not expired by record.CreatedAt already holds the key. That record is
+	// returned instead and nothing is saved. An expired record is replaced.
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error)
+
+	// Complete stores the response of a reserved request and moves its
+	// expiry to record.ExpiresAt. It returns
+	// domain.ErrIdempotencyKeyNotFound if the reservation has been lost:
+	// released, or expired and replaced by another request's.
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+
+	// Release deletes the reservation record made for a request whose
+	// response is not to be replayed, so the key can be used to try again.
+	// Releasing a reservation the key no longer holds does nothing.
+	Release(ctx context.Context, record *domain.IdempotencyRecord) error
+
+	// DeleteExpired deletes the records expired by now and returns how many there were
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"cushon/internal/core/domain"
+)
+
+// Outbox defines the output port for the events waiting to be published.
+// Events are added in the unit of work making the change they describe, so
+// an event is stored exactly when its change is, and relayed to the
+// EventPublisher afterwards.
+type Outbox interface {
+	// Add stores an event to publish. Made with the context of a unit of
+	// work, the event is kept only if the change it describes is.
+	Add(ctx context.Context, event *domain.Event) error
+
+	// Claim takes up to limit events due to be published at now, oldest
+	// first, and holds them until the given time so no other relay takes
+	// them meanwhile. Only the oldest waiting event of each aggregate is
+	// offered, so an aggregate's events are published in order.
+	Claim(ctx context.Context, now time.Time, until time.Time, limit int) ([]*domain.PendingEvent, error)
+
+	// MarkPublished records that an event was published at the given time, so it is not published again
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+
+	// RecordFailure counts a failed attempt to publish an event, keeping the
+	// reason, and releases it to be claimed again from retryAt
+	RecordFailure(ctx context.Context, id string, reason string, retryAt time.Time) error
+
+	// DeadLetter counts a failed attempt to publish an event, keeping the
+	// reason, and gives up on it: the event is kept but never offered again,
+	// and the events after it are no longer held back
+	DeadLetter(ctx context.Context, id string, reason string, at time.Time) error
+}
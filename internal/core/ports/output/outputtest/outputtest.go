@@ -0,0 +1,307 @@
+// Package outputtest checks that an adapter keeps the contract of the output
+// ports, so every adapter can be held to the same behaviour by running the
+// same checks against it.
+package outputtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Op names the repository method a check exercises
+type Op string
+
+const (
+	OpFindByID     Op = "FindByID"
+	OpFindByName   Op = "FindByName"
+	OpFindByUserID Op = "FindByUserID"
+	OpFindPage     Op = "FindPage"
+	OpFindByFundID Op = "FindByFundID"
+	OpFindLatest   Op = "FindLatest"
+	OpFindReversal Op = "FindReversal"
+	OpSave         Op = "Save"
+	OpUpdate       Op = "Update"
+	OpDelete       Op = "Delete"
+	// OpUpdateConflict saves a second fund and updates it to the ISIN of existing
+	OpUpdateConflict Op = "UpdateConflict"
+)
+
+// DirectUserFactory returns the repository a check runs against. An adapter
+// backed by a store saves existing, if not nil, before returning; a scripted
+// adapter uses op and existing to script the responses the check expects.
+type DirectUserFactory func(t *testing.T, op Op, existing *domain.DirectUser) output.DirectUserRepository
+
+// FundFactory returns the repository a check runs against, holding existing if not nil
+type FundFactory func(t *testing.T, op Op, existing *domain.Fund) output.FundRepository
+
+// FundPriceFactory returns the repository a check runs against, holding existing if not nil
+type FundPriceFactory func(t *testing.T, op Op, existing *domain.FundPrice) output.FundPriceRepository
+
+// TransactionFactory returns the repository a check runs against, holding existing if not nil
+type TransactionFactory func(t *testing.T, op Op, existing *domain.Transaction) output.TransactionRepository
+
+// RunDirectUserRepository checks a direct user repository keeps the output port contract
+func RunDirectUserRepository(t *testing.T, newRepo DirectUserFactory) {
+	t.Run("FindByID returns an existing user", func(t *testing.T) {
+		user := domain.NewDirectUser("John Doe", time.Now())
+		found, err := newRepo(t, OpFindByID, user).FindByID(context.Background(), user.ID)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if found == nil || found.ID != user.ID || found.Name != user.Name {
+			t.Errorf("FindByID() = %+v, want %+v", found, user)
+		}
+	})
+
+	t.Run("FindByID returns ErrNotFound for a missing user", func(t *testing.T) {
+		found, err := newRepo(t, OpFindByID, nil).FindByID(context.Background(), missingID())
+		expectNotFound(t, "FindByID", err, domain.ErrUserNotFound)
+		if found != nil {
+			t.Errorf("FindByID() = %+v, want nil", found)
+		}
+	})
+
+	t.Run("Update changes an existing user", func(t *testing.T) {
+		user := domain.NewDirectUser("John Doe", time.Now())
+		updated := &domain.DirectUser{ID: user.ID, Name: "Jane Doe"}
+		if err := newRepo(t, OpUpdate, user).Update(context.Background(), updated); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+
+	t.Run("Update returns ErrNotFound for a missing user", func(t *testing.T) {
+		err := newRepo(t, OpUpdate, nil).Update(context.Background(), domain.NewDirectUser("Jane Doe", time.Now()))
+		expectNotFound(t, "Update", err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Delete removes an existing user", func(t *testing.T) {
+		user := domain.NewDirectUser("John Doe", time.Now())
+		if err := newRepo(t, OpDelete, user).Delete(context.Background(), user.ID); err != nil {
+			t.Errorf("Delete() error = %v", err)
+		}
+	})
+
+	t.Run("Delete returns ErrNotFound for a missing user", func(t *testing.T) {
+		err := newRepo(t, OpDelete, nil).Delete(context.Background(), missingID())
+		expectNotFound(t, "Delete", err, domain.ErrUserNotFound)
+	})
+}
+
+// RunFundRepository checks a fund repository keeps the output port contract
+func RunFundRepository(t *testing.T, newRepo FundFactory) {
+	t.Run("FindByID returns an existing fund", func(t *testing.T) {
+		fund := newFund()
+		found, err := newRepo(t, OpFindByID, fund).FindByID(context.Background(), fund.ID)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if found == nil || found.ID != fund.ID || found.Name != fund.Name {
+			t.Errorf("FindByID() = %+v, want %+v", found, fund)
+		}
+	})
+
+	t.Run("FindByID returns ErrNotFound for a missing fund", func(t *testing.T) {
+		found, err := newRepo(t, OpFindByID, nil).FindByID(context.Background(), missingID())
+		expectNotFound(t, "FindByID", err, domain.ErrFundNotFound)
+		if found != nil {
+			t.Errorf("FindByID() = %+v, want nil", found)
+		}
+	})
+
+	t.Run("FindByName returns an existing fund", func(t *testing.T) {
+		fund := newFund()
+		found, err := newRepo(t, OpFindByName, fund).FindByName(context.Background(), fund.Name)
+		if err != nil {
+			t.Fatalf("FindByName() error = %v", err)
+		}
+		if found == nil || found.ID != fund.ID {
+			t.Errorf("FindByName() = %+v, want %+v", found, fund)
+		}
+	})
+
+	t.Run("FindByName returns ErrNotFound for a missing fund", func(t *testing.T) {
+		found, err := newRepo(t, OpFindByName, nil).FindByName(context.Background(), "Missing Fund")
+		expectNotFound(t, "FindByName", err, domain.ErrFundNotFound)
+		if found != nil {
+			t.Errorf("FindByName() = %+v, want nil", found)
+		}
+	})
+
+	t.Run("Save refuses a fund whose name is taken", func(t *testing.T) {
+		existing := newFund()
+		duplicate := domain.NewFund(existing.Name, "GB00BYX7QG64", domain.AssetClassBond, "GBP", 3)
+		err := newRepo(t, OpSave, existing).Save(context.Background(), duplicate)
+		expectConflict(t, "Save", err, domain.ErrFundExists)
+	})
+
+	t.Run("Save refuses a fund whose ISIN is taken", func(t *testing.T) {
+		existing := newFund()
+		duplicate := domain.NewFund("Cushon Bond Fund", existing.ISIN, domain.AssetClassBond, "GBP", 3)
+		err := newRepo(t, OpSave, existing).Save(context.Background(), duplicate)
+		expectConflict(t, "Save", err, domain.ErrFundExists)
+	})
+
+	t.Run("Update refuses an ISIN another fund has", func(t *testing.T) {
+		existing := newFund()
+		repo := newRepo(t, OpUpdateConflict, existing)
+		other := domain.NewFund("Cushon Bond Fund", "GB00BYX7QG64", domain.AssetClassBond, "GBP", 3)
+		if err := repo.Save(context.Background(), other); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		updated := *other
+		updated.ISIN = existing.ISIN
+		err := repo.Update(context.Background(), &updated)
+		expectConflict(t, "Update", err, domain.ErrFundISINTaken)
+	})
+
+	t.Run("Update changes an existing fund", func(t *testing.T) {
+		fund := newFund()
+		updated := *fund
+		updated.Status = domain.FundStatusClosed
+		if err := newRepo(t, OpUpdate, fund).Update(context.Background(), &updated); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+
+	t.Run("Update returns ErrNotFound for a missing fund", func(t *testing.T) {
+		err := newRepo(t, OpUpdate, nil).Update(context.Background(), newFund())
+		expectNotFound(t, "Update", err, domain.ErrFundNotFound)
+	})
+
+	t.Run("Delete removes an existing fund", func(t *testing.T) {
+		fund := newFund()
+		if err := newRepo(t, OpDelete, fund).Delete(context.Background(), fund.ID); err != nil {
+			t.Errorf("Delete() error = %v", err)
+		}
+	})
+
+	t.Run("Delete returns ErrNotFound for a missing fund", func(t *testing.T) {
+		err := newRepo(t, OpDelete, nil).Delete(context.Background(), missingID())
+		expectNotFound(t, "Delete", err, domain.ErrFundNotFound)
+	})
+}
+
+// RunFundPriceRepository checks a fund price repository keeps the output port contract
+func RunFundPriceRepository(t *testing.T, newRepo FundPriceFactory) {
+	t.Run("FindLatest returns the price of a priced fund", func(t *testing.T) {
+		price := domain.NewNAVFundPrice(missingID(), time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), decimal.NewFromFloat(1.2345))
+		found, err := newRepo(t, OpFindLatest, price).FindLatest(context.Background(), price.FundID)
+		if err != nil {
+			t.Fatalf("FindLatest() error = %v", err)
+		}
+		if found == nil || found.ID != price.ID {
+			t.Errorf("FindLatest() = %+v, want %+v", found, price)
+		}
+	})
+
+	t.Run("FindLatest returns nil for a fund never priced", func(t *testing.T) {
+		found, err := newRepo(t, OpFindLatest, nil).FindLatest(context.Background(), missingID())
+		if err != nil {
+			t.Errorf("FindLatest() error = %v, want nil", err)
+		}
+		if found != nil {
+			t.Errorf("FindLatest() = %+v, want nil", found)
+		}
+	})
+
+	t.Run("FindByFundID returns no prices for a fund never priced", func(t *testing.T) {
+		prices, err := newRepo(t, OpFindByFundID, nil).FindByFundID(context.Background(), missingID())
+		if err != nil {
+			t.Errorf("FindByFundID() error = %v, want nil", err)
+		}
+		if len(prices) != 0 {
+			t.Errorf("FindByFundID() returned %d prices, want none", len(prices))
+		}
+	})
+}
+
+// RunTransactionRepository checks a transaction repository keeps the output port contract
+func RunTransactionRepository(t *testing.T, newRepo TransactionFactory) {
+	t.Run("FindByID returns an existing transaction", func(t *testing.T) {
+		transaction := newTransaction()
+		found, err := newRepo(t, OpFindByID, transaction).FindByID(context.Background(), transaction.ID)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if found == nil || found.ID != transaction.ID || !found.Amount.Equal(transaction.Amount) {
+			t.Errorf("FindByID() = %+v, want %+v", found, transaction)
+		}
+	})
+
+	t.Run("FindByID returns ErrNotFound for a missing transaction", func(t *testing.T) {
+		found, err := newRepo(t, OpFindByID, nil).FindByID(context.Background(), missingID())
+		expectNotFound(t, "FindByID", err, domain.ErrTransactionNotFound)
+		if found != nil {
+			t.Errorf("FindByID() = %+v, want nil", found)
+		}
+	})
+
+	t.Run("FindByUserID returns no transactions for a user without any", func(t *testing.T) {
+		transactions, err := newRepo(t, OpFindByUserID, nil).FindByUserID(context.Background(), missingID())
+		if err != nil {
+			t.Errorf("FindByUserID() error = %v, want nil", err)
+		}
+		if len(transactions) != 0 {
+			t.Errorf("FindByUserID() returned %d transactions, want none", len(transactions))
+		}
+	})
+
+	t.Run("FindPage returns an empty last page for a user without any", func(t *testing.T) {
+		query := domain.TransactionQuery{UserID: missingID(), Sort: domain.SortNewestFirst, Limit: domain.DefaultPageSize}
+		page, err := newRepo(t, OpFindPage, nil).FindPage(context.Background(), query)
+		if err != nil {
+			t.Fatalf("FindPage() error = %v, want nil", err)
+		}
+		if page == nil || page.Transactions == nil || len(page.Transactions) != 0 || page.Total != 0 || page.Next != nil {
+			t.Errorf("FindPage() = %+v, want an empty page with no next cursor", page)
+		}
+	})
+
+	t.Run("FindReversal returns nil for a transaction not reversed", func(t *testing.T) {
+		transaction := newTransaction()
+		found, err := newRepo(t, OpFindReversal, transaction).FindReversal(context.Background(), transaction.ID)
+		if err != nil {
+			t.Errorf("FindReversal() error = %v, want nil", err)
+		}
+		if found != nil {
+			t.Errorf("FindReversal() = %+v, want nil", found)
+		}
+	})
+}
+
+// expectNotFound fails the check unless err matches both domain.ErrNotFound and want
+func expectNotFound(t *testing.T, method string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, domain.ErrNotFound) || !errors.Is(err, want) {
+		t.Errorf("%s() error = %v, want %v", method, err, want)
+	}
+}
+
+// expectConflict fails the check unless err matches both domain.ErrConflict and want
+func expectConflict(t *testing.T, method string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, domain.ErrConflict) || !errors.Is(err, want) {
+		t.Errorf("%s() error = %v, want %v", method, err, want)
+	}
+}
+
+// missingID returns an ID no repository holds
+func missingID() string {
+	return uuid.New().String()
+}
+
+func newFund() *domain.Fund {
+	return domain.NewFund("Cushon Equities Fund", "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+}
+
+func newTransaction() *domain.Transaction {
+	return domain.NewTransaction(missingID(), domain.TransactionTypeDeposit, decimal.NewFromInt(100), "Cushon Equities Fund", time.Now())
+}
@@ -1,21 +1,62 @@
 package output
 
-import "cushon/internal/core/domain"
+import (
+	"context"
+	"time"
 
-// TransactionRepository defines the output port for transaction persistence
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionRepository defines the output port for transaction persistence.
+// The ledger is append-only: transactions are never updated or deleted, apart
+// from recording the units allocated to a pending transaction.
 type TransactionRepository interface {
 	// Save persists a transaction
-	Save(transaction *domain.Transaction) error
+	Save(ctx context.Context, transaction *domain.Transaction) error
+
+	// SaveDebit persists a debit transaction only if the user's balance in the
+	// fund covers it, returning *domain.InsufficientBalanceError otherwise. The
+	// check and insert are atomic with respect to other debits for the user.
+	SaveDebit(ctx context.Context, transaction *domain.Transaction) error
+
+	// SaveDeposit persists a deposit only if the user's deposits in its tax
+	// year, with this one, stay within limit, returning
+	// *domain.AllowanceExceededError otherwise. The check and insert are atomic
+	// with respect to other deposits for the user.
+	SaveDeposit(ctx context.Context, transaction *domain.Transaction, limit decimal.Decimal) error
+
+	// SaveReversal persists a reversal and, if not nil, the entry correcting the
+	// reversed transaction, atomically. Either entry taking money out of a fund
+	// must be covered by the user's balance, otherwise
+	// *domain.InsufficientBalanceError is returned and nothing is saved.
+	SaveReversal(ctx context.Context, reversal, correction *domain.Transaction) error
+
+	// Balance returns a user's available balance in a fund: credits less debits
+	Balance(ctx context.Context, userID string, fundName domain.FundName) (decimal.Decimal, error)
 	
-	// FindByID retrieves a transaction by ID
-	FindByID(id string) (*domain.Transaction, error)
+	// FindByID retrieves a transaction by ID, returning domain.ErrTransactionNotFound if there is none
+	FindByID(ctx context.Context, id string) (*domain.Transaction, error)
 	
 	// FindByUserID retrieves all transactions for a user
-	FindByUserID(userID string) ([]*domain.Transaction, error)
-	
-	// Update updates an existing transaction
-	Update(transaction *domain.Transaction) error
-	
-	// Delete removes a transaction by ID
-	Delete(id string) error
+	FindByUserID(ctx context.Context, userID string) ([]*domain.Transaction, error)
+
+	// FindPage retrieves the page of a user's transactions the query selects,
+	// ordered by the time they were recorded and then by ID, with the number
+	// of transactions matching its filters across all pages
+	FindPage(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error)
+
+	// FindReversal retrieves the entry reversing a transaction, nil if it has not been reversed
+	FindReversal(ctx context.Context, transactionID string) (*domain.Transaction, error)
+
+	// FindUnpriced retrieves pending transactions in a fund placed before the given valuation point
+	FindUnpriced(ctx context.Context, fundName domain.FundName, placedBefore time.Time) ([]*domain.Transaction, error)
+
+	// AllocateUnits records the units, unit price and valuation date of a pending transaction
+	AllocateUnits(ctx context.Context, transaction *domain.Transaction) error
+
+	// SumDeposits totals a user's deposits made in the half-open interval [from, to),
+	// leaving out deposits that have been reversed
+	SumDeposits(ctx context.Context, userID string, from, to time.Time) (decimal.Decimal, error)
 } 
\ No newline at end of file
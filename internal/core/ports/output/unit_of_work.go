@@ -0,0 +1,17 @@
+package output
+
+import "context"
+
+// UnitOfWork defines the output port for running several repository calls
+// atomically: either all their changes are kept or none are.
+type UnitOfWork interface {
+	// Do runs fn in a unit of work. Repository calls made with the context
+	// passed to fn take part in it; calls made with any other context do not.
+	// If fn returns an error every change is undone and the error returned,
+	// otherwise the changes are committed.
+	//
+	// fn may be run again if the unit of work conflicts with another, so it
+	// must not have effects outside the repositories. Calling Do inside fn
+	// joins the unit of work already running.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
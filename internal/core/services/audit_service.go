@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+	"cushon/internal/core/ports/output"
+)
+
+// AuditService implements the input.AuditService interface
+type AuditService struct {
+	auditLog   output.AuditLog
+	authorizer output.Authorizer
+}
+
+// NewAuditService creates a new audit service instance
+func NewAuditService(auditLog output.AuditLog, authorizer output.Authorizer) input.AuditService {
+	return &AuditService{
+		auditLog:   auditLog,
+		authorizer: authorizer,
+	}
+}
+
+// GetAuditLog implements the audit log retrieval use case
+func (s *AuditService) GetAuditLog(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultAuditPageSize
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewAuditLog, ""); err != nil {
+		return nil, err
+	}
+
+	return s.auditLog.FindPage(ctx, query)
+}
+
+// recordChange adds a change to the audit log, attributed to the principal
+// and the request ctx carries. Called inside a unit of work, the entry is
+// kept only if the change is.
+func recordChange(ctx context.Context, auditLog output.AuditLog, clock output.Clock, action domain.AuditAction, before, after domain.Auditable) error {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry, err := domain.NewAuditEntry(principal.Subject, action, before, after, domain.RequestIDFromContext(ctx), clock.Now())
+	if err != nil {
+		return err
+	}
+
+	return auditLog.Record(ctx, entry)
+}
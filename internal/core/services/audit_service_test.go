@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// requestContext returns a staff context serving the request with the given ID
+func requestContext(requestID string) context.Context {
+	return domain.ContextWithRequestID(staffContext(), requestID)
+}
+
+// snapshotField reads a field of an audit snapshot
+func snapshotField(t *testing.T, snapshot json.RawMessage, name string) any {
+	t.Helper()
+	var fields map[string]any
+	if err := json.Unmarshal(snapshot, &fields); err != nil {
+		t.Fatalf("Failed to decode snapshot %s: %v", snapshot, err)
+	}
+	return fields[name]
+}
+
+func TestDirectUserService_RecordsChanges(t *testing.T) {
+	auditLog := NewMockAuditLog()
+	service := NewDirectUserService(NewMockDirectUserRepository(), NewMockUnitOfWork(), auditLog, NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	user, err := service.CreateDirectUser(requestContext("req-1"), "John Doe")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.UpdateDirectUser(requestContext("req-2"), &domain.DirectUser{ID: user.ID, Name: "Jane Doe"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.DeleteDirectUser(requestContext("req-3"), user.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(auditLog.Entries) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d", len(auditLog.Entries))
+	}
+	for i, want := range []struct {
+		action    domain.AuditAction
+		requestID string
+		before    any
+		after     any
+	}{
+		{action: domain.AuditActionCreate, requestID: "req-1", after: "John Doe"},
+		{action: domain.AuditActionUpdate, requestID: "req-2", before: "John Doe", after: "Jane Doe"},
+		{action: domain.AuditActionDelete, requestID: "req-3", before: "Jane Doe"},
+	} {
+		entry := auditLog.Entries[i]
+		if entry.Action != want.action || entry.RequestID != want.requestID {
+			t.Errorf("Entry %d: expected %s in %s, got %s in %s", i, want.action, want.requestID, entry.Action, entry.RequestID)
+		}
+		if entry.Actor != testStaff.Subject || entry.EntityType != domain.AuditEntityDirectUser || entry.EntityID != user.ID {
+			t.Errorf("Entry %d: expected %s changing direct user %s, got %+v", i, testStaff.Subject, user.ID, entry)
+		}
+		if !entry.CreatedAt.Equal(testNow) {
+			t.Errorf("Entry %d: expected to be recorded at %v, got %v", i, testNow, entry.CreatedAt)
+		}
+		if want.before == nil && entry.Before != nil || want.before != nil && snapshotField(t, entry.Before, "name") != want.before {
+			t.Errorf("Entry %d: expected before name %v, got %s", i, want.before, entry.Before)
+		}
+		if want.after == nil && entry.After != nil || want.after != nil && snapshotField(t, entry.After, "name") != want.after {
+			t.Errorf("Entry %d: expected after name %v, got %s", i, want.after, entry.After)
+		}
+	}
+}
+
+func TestTransactionService_RecordsCorrections(t *testing.T) {
+	auditLog := NewMockAuditLog()
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), auditLog, NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	transaction, err := service.CreateTransaction(requestContext("req-1"), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	corrected, err := service.CorrectTransaction(requestContext("req-2"), transaction.ID, decimal.NewFromInt(150), domain.CushonEquitiesFund, "wrong amount")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(auditLog.Entries) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(auditLog.Entries))
+	}
+	if entry := auditLog.Entries[0]; entry.Action != domain.AuditActionCreate || entry.EntityID != transaction.ID || entry.Before != nil {
+		t.Errorf("Expected the deposit's creation, got %+v", entry)
+	}
+
+	correction := auditLog.Entries[1]
+	if correction.Action != domain.AuditActionCorrect || correction.EntityType != domain.AuditEntityTransaction || correction.EntityID != transaction.ID {
+		t.Errorf("Expected the correction of %s, got %+v", transaction.ID, correction)
+	}
+	if correction.RequestID != "req-2" || correction.Actor != testStaff.Subject {
+		t.Errorf("Expected the correction attributed to %s in req-2, got %s in %s", testStaff.Subject, correction.Actor, correction.RequestID)
+	}
+	if got := snapshotField(t, correction.Before, "amount"); got != "100" {
+		t.Errorf("Expected amount 100 before, got %v", got)
+	}
+	if got := snapshotField(t, correction.After, "amount"); got != "150" {
+		t.Errorf("Expected amount 150 after, got %v", got)
+	}
+	if got := snapshotField(t, correction.After, "id"); got != corrected.Correction.ID {
+		t.Errorf("Expected the corrected entry after, got %v", got)
+	}
+}
+
+func TestPricingService_RecordsAllocations(t *testing.T) {
+	fundRepo := NewSeededMockFundRepository()
+	transactionRepo := NewMockTransactionRepository()
+	auditLog := NewMockAuditLog()
+	service := NewPricingService(fundRepo, NewMockFundPriceRepository(), transactionRepo, NewMockUnitOfWork(), auditLog, NewMockAuthorizer(), NewMockClock(testNow))
+	fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+
+	deposit := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund, testNow.Add(-2*time.Hour))
+	transactionRepo.Save(context.Background(), deposit)
+
+	price := domain.NewNAVFundPrice("", testNow.Add(-time.Hour), decimal.NewFromInt(2))
+	if _, err := service.ImportPrices(staffContext(), fund.ID, []*domain.FundPrice{price}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(auditLog.Entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(auditLog.Entries))
+	}
+	entry := auditLog.Entries[0]
+	if entry.Action != domain.AuditActionAllocateUnits || entry.EntityID != deposit.ID {
+		t.Errorf("Expected units allocated to %s, got %+v", deposit.ID, entry)
+	}
+	if got := snapshotField(t, entry.Before, "units"); got != nil {
+		t.Errorf("Expected no units before, got %v", got)
+	}
+	if got := snapshotField(t, entry.After, "units"); got != "500" {
+		t.Errorf("Expected 500 units after, got %v", got)
+	}
+}
+
+func TestAuditService_GetAuditLog(t *testing.T) {
+	auditLog := NewMockAuditLog()
+	authorizer := NewMockAuthorizer()
+	service := NewAuditService(auditLog, authorizer)
+
+	user := domain.NewDirectUser("John Doe", testNow)
+	for _, action := range []domain.AuditAction{domain.AuditActionCreate, domain.AuditActionUpdate} {
+		entry, err := domain.NewAuditEntry(testStaff.Subject, action, nil, user, "", testNow)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		auditLog.Record(context.Background(), entry)
+	}
+
+	page, err := service.GetAuditLog(staffContext(), domain.AuditQuery{EntityType: domain.AuditEntityDirectUser, EntityID: user.ID})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(page.Entries))
+	}
+	if authorizer.Requested[0] != domain.PermissionViewAuditLog {
+		t.Errorf("Expected %s to be checked, got %v", domain.PermissionViewAuditLog, authorizer.Requested)
+	}
+
+	if _, err := service.GetAuditLog(staffContext(), domain.AuditQuery{EntityType: "fund"}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected ErrValidation for an unknown entity type, got %v", err)
+	}
+
+	denied := NewAuditService(auditLog, NewMockAuthorizer(domain.PermissionViewAuditLog))
+	if _, err := denied.GetAuditLog(staffContext(), domain.AuditQuery{EntityType: domain.AuditEntityDirectUser}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without the permission, got %v", err)
+	}
+}
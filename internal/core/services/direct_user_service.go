@@ -1,7 +1,7 @@
 package services
 
 import (
-	"errors"
+	"context"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/input"
@@ -11,27 +11,57 @@ import (
 // DirectUserService implements the input.DirectUserService interface
 type DirectUserService struct {
 	directUserRepo output.DirectUserRepository
+	unitOfWork     output.UnitOfWork
+	auditLog       output.AuditLog
+	outbox         output.Outbox
+	authorizer     output.Authorizer
+	clock          output.Clock
 }
 
 // NewDirectUserService creates a new direct user service instance
-func NewDirectUserService(directUserRepo output.DirectUserRepository) input.DirectUserService {
+func NewDirectUserService(directUserRepo output.DirectUserRepository, unitOfWork output.UnitOfWork, auditLog output.AuditLog, outbox output.Outbox, authorizer output.Authorizer, clock output.Clock) input.DirectUserService {
 	return &DirectUserService{
 		directUserRepo: directUserRepo,
+		unitOfWork:     unitOfWork,
+		auditLog:       auditLog,
+		outbox:         outbox,
+		authorizer:     authorizer,
+		clock:          clock,
 	}
 }
 
-// CreateDirectUser implements the direct user creation use case
-func (s *DirectUserService) CreateDirectUser(name string) (*domain.DirectUser, error) {
+// CreateDirectUser implements the direct user creation use case. Customers
+// are onboarded by staff, so a customer cannot create a direct user.
+func (s *DirectUserService) CreateDirectUser(ctx context.Context, name string) (*domain.DirectUser, error) {
+	if err := s.authorizer.Authorize(ctx, domain.PermissionCreateDirectUser, ""); err != nil {
+		return nil, err
+	}
+
 	// Validate input
 	if name == "" {
-		return nil, errors.New("name is required")
+		return nil, domain.NewValidationError("name", "name is required")
 	}
 
 	// Create new direct user
-	directUser := domain.NewDirectUser(name)
-
-	// Save direct user to repository
-	if err := s.directUserRepo.Save(directUser); err != nil {
+	directUser := domain.NewDirectUser(name, s.clock.Now())
+
+	// Save direct user to repository, recording who onboarded them and
+	// telling downstream systems
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.directUserRepo.Save(ctx, directUser); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.auditLog, s.clock, domain.AuditActionCreate, nil, directUser); err != nil {
+			return err
+		}
+
+		event, err := domain.NewDirectUserCreatedEvent(directUser, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, event)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -39,12 +69,15 @@ func (s *DirectUserService) CreateDirectUser(name string) (*domain.DirectUser, e
 }
 
 // GetDirectUser implements the direct user retrieval use case
-func (s *DirectUserService) GetDirectUser(id string) (*domain.DirectUser, error) {
+func (s *DirectUserService) GetDirectUser(ctx context.Context, id string) (*domain.DirectUser, error) {
 	if id == "" {
-		return nil, errors.New("direct user ID is required")
+		return nil, domain.NewValidationError("id", "direct user ID is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewAccount, id); err != nil {
+		return nil, err
 	}
 
-	directUser, err := s.directUserRepo.FindByID(id)
+	directUser, err := s.directUserRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -52,34 +85,77 @@ func (s *DirectUserService) GetDirectUser(id string) (*domain.DirectUser, error)
 	return directUser, nil
 }
 
-// UpdateDirectUser implements the direct user update use case
-func (s *DirectUserService) UpdateDirectUser(user *domain.DirectUser) error {
+// UpdateDirectUser implements the direct user update use case. Only the name
+// can be changed; user is filled in with the updated user.
+func (s *DirectUserService) UpdateDirectUser(ctx context.Context, user *domain.DirectUser) error {
 	if user == nil {
-		return errors.New("direct user cannot be nil")
+		return domain.NewValidationError("user", "direct user cannot be nil")
 	}
 
 	if user.ID == "" {
-		return errors.New("direct user ID is required")
+		return domain.NewValidationError("id", "direct user ID is required")
 	}
 
 	if user.Name == "" {
-		return errors.New("name is required")
+		return domain.NewValidationError("name", "name is required")
+	}
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionUpdateDirectUser, user.ID); err != nil {
+		return err
+	}
+
+	var updated *domain.DirectUser
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		existing, err := s.directUserRepo.FindByID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		before := *existing
+		existing.Name = user.Name
+		existing.UpdatedAt = s.clock.Now()
+
+		if err := s.directUserRepo.Update(ctx, existing); err != nil {
+			return err
+		}
+		updated = existing
+		return recordChange(ctx, s.auditLog, s.clock, domain.AuditActionUpdate, &before, existing)
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.directUserRepo.Update(user)
+	// Hand back the stored user, so the caller sees when it was created and updated
+	*user = *updated
+	return nil
 }
 
 // DeleteDirectUser implements the direct user deletion use case
-func (s *DirectUserService) DeleteDirectUser(id string) error {
+func (s *DirectUserService) DeleteDirectUser(ctx context.Context, id string) error {
 	if id == "" {
-		return errors.New("direct user ID is required")
+		return domain.NewValidationError("id", "direct user ID is required")
 	}
-
-	// Verify direct user exists
-	_, err := s.directUserRepo.FindByID(id)
-	if err != nil {
+	if err := s.authorizer.Authorize(ctx, domain.PermissionDeleteDirectUser, id); err != nil {
 		return err
 	}
 
-	return s.directUserRepo.Delete(id)
+	return s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		// Verify direct user exists, keeping them for the audit log and event
+		existing, err := s.directUserRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.directUserRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.auditLog, s.clock, domain.AuditActionDelete, existing, nil); err != nil {
+			return err
+		}
+
+		event, err := domain.NewDirectUserDeletedEvent(existing, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, event)
+	})
 } 
\ No newline at end of file
@@ -1,14 +1,18 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"cushon/internal/core/domain"
 )
 
 func TestDirectUserService_CreateDirectUser(t *testing.T) {
 	repo := NewMockDirectUserRepository()
-	service := NewDirectUserService(repo)
+	service := NewDirectUserService(repo, NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	tests := []struct {
 		name          string
@@ -29,7 +33,7 @@ func TestDirectUserService_CreateDirectUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, err := service.CreateDirectUser(tt.inputName)
+			user, err := service.CreateDirectUser(staffContext(), tt.inputName)
 
 			if tt.expectedError {
 				if err == nil {
@@ -52,7 +56,7 @@ func TestDirectUserService_CreateDirectUser(t *testing.T) {
 			}
 
 			// Verify user was saved in repository
-			savedUser, err := repo.FindByID(user.ID)
+			savedUser, err := repo.FindByID(context.Background(), user.ID)
 			if err != nil {
 				t.Errorf("Failed to find saved user: %v", err)
 			}
@@ -65,10 +69,10 @@ func TestDirectUserService_CreateDirectUser(t *testing.T) {
 
 func TestDirectUserService_GetDirectUser(t *testing.T) {
 	repo := NewMockDirectUserRepository()
-	service := NewDirectUserService(repo)
+	service := NewDirectUserService(repo, NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create a test user
-	testUser, _ := service.CreateDirectUser("John Doe")
+	testUser, _ := service.CreateDirectUser(staffContext(), "John Doe")
 
 	tests := []struct {
 		name          string
@@ -94,7 +98,7 @@ func TestDirectUserService_GetDirectUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, err := service.GetDirectUser(tt.userID)
+			user, err := service.GetDirectUser(staffContext(), tt.userID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -117,10 +121,10 @@ func TestDirectUserService_GetDirectUser(t *testing.T) {
 
 func TestDirectUserService_UpdateDirectUser(t *testing.T) {
 	repo := NewMockDirectUserRepository()
-	service := NewDirectUserService(repo)
+	service := NewDirectUserService(repo, NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create a test user
-	testUser, _ := service.CreateDirectUser("John Doe")
+	testUser, _ := service.CreateDirectUser(staffContext(), "John Doe")
 
 	tests := []struct {
 		name          string
@@ -160,7 +164,7 @@ func TestDirectUserService_UpdateDirectUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := service.UpdateDirectUser(tt.user)
+			err := service.UpdateDirectUser(staffContext(), tt.user)
 
 			if tt.expectedError {
 				if err == nil {
@@ -175,7 +179,7 @@ func TestDirectUserService_UpdateDirectUser(t *testing.T) {
 			}
 
 			// Verify user was updated in repository
-			updatedUser, err := repo.FindByID(tt.user.ID)
+			updatedUser, err := repo.FindByID(context.Background(), tt.user.ID)
 			if err != nil {
 				t.Errorf("Failed to find updated user: %v", err)
 			}
@@ -188,10 +192,10 @@ func TestDirectUserService_UpdateDirectUser(t *testing.T) {
 
 func TestDirectUserService_DeleteDirectUser(t *testing.T) {
 	repo := NewMockDirectUserRepository()
-	service := NewDirectUserService(repo)
+	service := NewDirectUserService(repo, NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create a test user
-	testUser, _ := service.CreateDirectUser("John Doe")
+	testUser, _ := service.CreateDirectUser(staffContext(), "John Doe")
 
 	tests := []struct {
 		name          string
@@ -217,7 +221,7 @@ func TestDirectUserService_DeleteDirectUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := service.DeleteDirectUser(tt.userID)
+			err := service.DeleteDirectUser(staffContext(), tt.userID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -232,10 +236,165 @@ func TestDirectUserService_DeleteDirectUser(t *testing.T) {
 			}
 
 			// Verify user was deleted from repository
-			_, err = repo.FindByID(tt.userID)
+			_, err = repo.FindByID(context.Background(), tt.userID)
 			if err == nil {
 				t.Error("Expected user to be deleted, but found in repository")
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestDirectUserService_Timestamps(t *testing.T) {
+	clock := NewMockClock(testNow)
+	service := NewDirectUserService(NewMockDirectUserRepository(), NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), clock)
+
+	created, err := service.CreateDirectUser(staffContext(), "John Doe")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !created.CreatedAt.Equal(testNow) || !created.UpdatedAt.Equal(testNow) {
+		t.Errorf("Expected user created and updated at %s, got %s and %s", testNow, created.CreatedAt, created.UpdatedAt)
+	}
+
+	// Renaming the user moves only the update time on
+	clock.Advance(time.Hour)
+	renamed := &domain.DirectUser{ID: created.ID, Name: "Johnny Doe"}
+	if err := service.UpdateDirectUser(staffContext(), renamed); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !renamed.CreatedAt.Equal(testNow) || !renamed.UpdatedAt.Equal(testNow.Add(time.Hour)) {
+		t.Errorf("Expected user created at %s and updated an hour later, got %s and %s", testNow, renamed.CreatedAt, renamed.UpdatedAt)
+	}
+}
+
+func TestDirectUserService_Authorization(t *testing.T) {
+	repo := NewSeededMockDirectUserRepository("user123", "user456")
+	service := NewDirectUserService(repo, NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(domain.PermissionDeleteDirectUser), NewMockClock(testNow))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		call    func(ctx context.Context) error
+		wantErr error
+	}{
+		{
+			name: "deleting a user without the permission",
+			ctx:  staffContext(),
+			call: func(ctx context.Context) error {
+				return service.DeleteDirectUser(ctx, "user456")
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "customer reading their own user",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetDirectUser(ctx, "user123")
+				return err
+			},
+		},
+		{
+			name: "customer reading another user",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetDirectUser(ctx, "user456")
+				return err
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "customer renaming another user",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				return service.UpdateDirectUser(ctx, &domain.DirectUser{ID: "user456", Name: "Jane Doe"})
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "customer deleting another user",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				return service.DeleteDirectUser(ctx, "user456")
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "no principal",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := service.GetDirectUser(ctx, "user123")
+				return err
+			},
+			wantErr: domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(tt.ctx)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// A refused change leaves the user as it was
+	if _, err := repo.FindByID(context.Background(), "user456"); err != nil {
+		t.Errorf("Expected user456 to remain: %v", err)
+	}
+}
+
+func TestDirectUserService_Permissions(t *testing.T) {
+	authorizer := NewMockAuthorizer()
+	service := NewDirectUserService(NewMockDirectUserRepository(), NewMockUnitOfWork(), NewMockAuditLog(), NewMockOutbox(), authorizer, NewMockClock(testNow))
+
+	user, err := service.CreateDirectUser(staffContext(), "John Doe")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	service.GetDirectUser(staffContext(), user.ID)
+	service.UpdateDirectUser(staffContext(), &domain.DirectUser{ID: user.ID, Name: "Jane Doe"})
+	service.DeleteDirectUser(staffContext(), user.ID)
+
+	want := []domain.Permission{
+		domain.PermissionCreateDirectUser,
+		domain.PermissionViewAccount,
+		domain.PermissionUpdateDirectUser,
+		domain.PermissionDeleteDirectUser,
+	}
+	if !slices.Equal(authorizer.Requested, want) {
+		t.Errorf("Expected permissions %v to be checked, got %v", want, authorizer.Requested)
+	}
+}
+
+func TestDirectUserService_AddsEvents(t *testing.T) {
+	outbox := NewMockOutbox()
+	service := NewDirectUserService(NewMockDirectUserRepository(), NewMockUnitOfWork(), NewMockAuditLog(), outbox, NewMockAuthorizer(), NewMockClock(testNow))
+
+	user, err := service.CreateDirectUser(staffContext(), "John Doe")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Renaming a user is not an event downstream systems need
+	if err := service.UpdateDirectUser(staffContext(), &domain.DirectUser{ID: user.ID, Name: "Jane Doe"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.DeleteDirectUser(staffContext(), user.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []domain.EventType{domain.EventDirectUserCreated, domain.EventDirectUserDeleted}
+	if len(outbox.Events) != len(want) {
+		t.Fatalf("Expected events %v, got %d events", want, len(outbox.Events))
+	}
+	for i, event := range outbox.Events {
+		if event.Type != want[i] || event.AggregateID != user.ID || !event.OccurredAt.Equal(testNow) {
+			t.Errorf("Event %d: expected %s for %s, got %+v", i, want[i], user.ID, event)
+		}
+	}
+	if name := snapshotField(t, outbox.Events[1].Payload, "name"); name != "Jane Doe" {
+		t.Errorf("Expected the deleted user in the payload, got name %v", name)
+	}
+}
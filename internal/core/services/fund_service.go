@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+	"cushon/internal/core/ports/output"
+)
+
+// FundService implements the input.FundService interface
+type FundService struct {
+	fundRepo   output.FundRepository
+	authorizer output.Authorizer
+}
+
+// NewFundService creates a new fund service instance
+func NewFundService(fundRepo output.FundRepository, authorizer output.Authorizer) input.FundService {
+	return &FundService{
+		fundRepo:   fundRepo,
+		authorizer: authorizer,
+	}
+}
+
+// CreateFund implements the fund creation use case
+func (s *FundService) CreateFund(ctx context.Context, fund *domain.Fund) (*domain.Fund, error) {
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageFunds, ""); err != nil {
+		return nil, err
+	}
+
+	if fund == nil {
+		return nil, domain.NewValidationError("fund", "fund cannot be nil")
+	}
+
+	if err := fund.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Fund names are referenced by transactions, so they must be unique. A
+	// duplicate ISIN is refused by the repository, which enforces both.
+	_, err := s.fundRepo.FindByName(ctx, fund.Name)
+	if err == nil {
+		return nil, domain.ErrFundExists
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := s.fundRepo.Save(ctx, fund); err != nil {
+		return nil, err
+	}
+
+	return fund, nil
+}
+
+// GetFund implements the fund retrieval use case
+func (s *FundService) GetFund(ctx context.Context, id string) (*domain.Fund, error) {
+	if id == "" {
+		return nil, domain.NewValidationError("id", "fund ID is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewFunds, ""); err != nil {
+		return nil, err
+	}
+
+	fund, err := s.fundRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return fund, nil
+}
+
+// ListFunds implements the fund catalogue listing use case
+func (s *FundService) ListFunds(ctx context.Context) ([]*domain.Fund, error) {
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewFunds, ""); err != nil {
+		return nil, err
+	}
+	return s.fundRepo.FindAll(ctx)
+}
+
+// ListOpenFundNames implements the listing of funds available for investment
+func (s *FundService) ListOpenFundNames(ctx context.Context) ([]domain.FundName, error) {
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewFunds, ""); err != nil {
+		return nil, err
+	}
+
+	funds, err := s.fundRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fundNames := []domain.FundName{}
+	for _, fund := range funds {
+		if fund.IsOpen() {
+			fundNames = append(fundNames, fund.Name)
+		}
+	}
+
+	return fundNames, nil
+}
+
+// UpdateFund implements the fund update use case
+func (s *FundService) UpdateFund(ctx context.Context, fund *domain.Fund) error {
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageFunds, ""); err != nil {
+		return err
+	}
+
+	if fund == nil {
+		return domain.NewValidationError("fund", "fund cannot be nil")
+	}
+
+	if fund.ID == "" {
+		return domain.NewValidationError("id", "fund ID is required")
+	}
+
+	// Verify fund exists
+	existingFund, err := s.fundRepo.FindByID(ctx, fund.ID)
+	if err != nil {
+		return err
+	}
+
+	// A fund is only reopened or closed when asked to
+	if fund.Status == "" {
+		fund.Status = existingFund.Status
+	}
+	fund.Normalize()
+	if err := fund.Validate(); err != nil {
+		return err
+	}
+
+	// Existing transactions record the fund by name, so it cannot be renamed
+	if existingFund.Name != fund.Name {
+		return domain.NewValidationError("name", "fund name cannot be changed")
+	}
+
+	return s.fundRepo.Update(ctx, fund)
+}
+
+// DeleteFund implements the fund deletion use case
+func (s *FundService) DeleteFund(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.NewValidationError("id", "fund ID is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageFunds, ""); err != nil {
+		return err
+	}
+
+	return s.fundRepo.Delete(ctx, id)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cushon/internal/core/domain"
+)
+
+func TestFundService_CreateFund(t *testing.T) {
+	repo := NewSeededMockFundRepository()
+	service := NewFundService(repo, NewMockAuthorizer())
+
+	tests := []struct {
+		name          string
+		fund          *domain.Fund
+		expectedError bool
+	}{
+		{
+			name:          "valid fund",
+			fund:          domain.NewFund("Cushon Global Bond Fund", "US0378331005", domain.AssetClassBond, "GBP", 3),
+			expectedError: false,
+		},
+		{
+			name:          "duplicate fund name",
+			fund:          domain.NewFund(domain.CushonEquitiesFund, "US0378331005", domain.AssetClassEquity, "GBP", 5),
+			expectedError: true,
+		},
+		{
+			name:          "invalid ISIN",
+			fund:          domain.NewFund("Cushon Property Fund", "INVALID", domain.AssetClassProperty, "GBP", 4),
+			expectedError: true,
+		},
+		{
+			name:          "nil fund",
+			fund:          nil,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fund, err := service.CreateFund(staffContext(), tt.fund)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			// Verify fund was saved in repository
+			savedFund, err := repo.FindByID(context.Background(), fund.ID)
+			if err != nil {
+				t.Errorf("Failed to find saved fund: %v", err)
+			}
+			if savedFund.Name != tt.fund.Name {
+				t.Errorf("Expected saved fund name %s, got %s", tt.fund.Name, savedFund.Name)
+			}
+		})
+	}
+}
+
+func TestFundService_UpdateFund(t *testing.T) {
+	repo := NewMockFundRepository()
+	service := NewFundService(repo, NewMockAuthorizer())
+
+	testFund, _ := service.CreateFund(staffContext(), domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5))
+
+	tests := []struct {
+		name          string
+		fund          *domain.Fund
+		expectedError bool
+	}{
+		{
+			name: "close fund",
+			fund: &domain.Fund{
+				ID:         testFund.ID,
+				Name:       testFund.Name,
+				ISIN:       testFund.ISIN,
+				AssetClass: testFund.AssetClass,
+				Currency:   testFund.Currency,
+				RiskRating: 6,
+				Status:     domain.FundStatusClosed,
+			},
+			expectedError: false,
+		},
+		{
+			name: "rename fund",
+			fund: &domain.Fund{
+				ID:         testFund.ID,
+				Name:       "Renamed Fund",
+				ISIN:       testFund.ISIN,
+				AssetClass: testFund.AssetClass,
+				Currency:   testFund.Currency,
+				RiskRating: testFund.RiskRating,
+				Status:     testFund.Status,
+			},
+			expectedError: true,
+		},
+		{
+			name: "non-existent fund",
+			fund: &domain.Fund{
+				ID:         "non-existent",
+				Name:       testFund.Name,
+				ISIN:       testFund.ISIN,
+				AssetClass: testFund.AssetClass,
+				Currency:   testFund.Currency,
+				RiskRating: testFund.RiskRating,
+				Status:     testFund.Status,
+			},
+			expectedError: true,
+		},
+		{
+			name:          "nil fund",
+			fund:          nil,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.UpdateFund(staffContext(), tt.fund)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			updatedFund, _ := repo.FindByID(context.Background(), tt.fund.ID)
+			if updatedFund.Status != tt.fund.Status {
+				t.Errorf("Expected status %s, got %s", tt.fund.Status, updatedFund.Status)
+			}
+		})
+	}
+}
+
+func TestFundService_UpdateFund_KeepsStatusAndNormalises(t *testing.T) {
+	repo := NewMockFundRepository()
+	service := NewFundService(repo, NewMockAuthorizer())
+
+	fund := domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5)
+	fund.Status = domain.FundStatusClosed
+	created, err := service.CreateFund(staffContext(), fund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// An update leaving out the status keeps the fund closed
+	update := &domain.Fund{
+		ID:         created.ID,
+		Name:       created.Name,
+		ISIN:       "gb00b3x7qg63",
+		AssetClass: created.AssetClass,
+		Currency:   "gbp",
+		RiskRating: 6,
+	}
+	if err := service.UpdateFund(staffContext(), update); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	updated, _ := repo.FindByID(context.Background(), created.ID)
+	if updated.Status != domain.FundStatusClosed {
+		t.Errorf("Expected the fund to stay closed, got %s", updated.Status)
+	}
+	if updated.ISIN != "GB00B3X7QG63" || updated.Currency != "GBP" {
+		t.Errorf("Expected the ISIN and currency upper-cased as on creation, got %s and %s", updated.ISIN, updated.Currency)
+	}
+}
+
+func TestFundService_ListOpenFundNames(t *testing.T) {
+	repo := NewSeededMockFundRepository()
+	service := NewFundService(repo, NewMockAuthorizer())
+
+	closedFund := domain.NewFund("Cushon Closed Fund", "US0378331005", domain.AssetClassCash, "GBP", 1)
+	closedFund.Status = domain.FundStatusClosed
+	repo.Save(context.Background(), closedFund)
+
+	fundNames, err := service.ListOpenFundNames(staffContext())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(fundNames) != 1 || fundNames[0] != domain.CushonEquitiesFund {
+		t.Errorf("Expected only %s, got %v", domain.CushonEquitiesFund, fundNames)
+	}
+}
+
+func TestFundService_DeleteFund(t *testing.T) {
+	repo := NewSeededMockFundRepository()
+	service := NewFundService(repo, NewMockAuthorizer())
+
+	fund, _ := repo.FindByName(context.Background(), domain.CushonEquitiesFund)
+
+	if err := service.DeleteFund(staffContext(), fund.ID); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := service.DeleteFund(staffContext(), fund.ID); err == nil {
+		t.Error("Expected error deleting non-existent fund, got nil")
+	}
+
+	if err := service.DeleteFund(staffContext(), ""); err == nil {
+		t.Error("Expected error for empty ID, got nil")
+	}
+}
+
+func TestFundService_ManagementNeedsPermission(t *testing.T) {
+	repo := NewSeededMockFundRepository()
+	service := NewFundService(repo, NewMockAuthorizer(domain.PermissionManageFunds))
+
+	fund, _ := repo.FindByName(context.Background(), domain.CushonEquitiesFund)
+
+	if _, err := service.CreateFund(staffContext(), domain.NewFund("Cushon Global Bond Fund", "US0378331005", domain.AssetClassBond, "GBP", 3)); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden creating a fund, got %v", err)
+	}
+	if err := service.UpdateFund(staffContext(), fund); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden updating a fund, got %v", err)
+	}
+	if err := service.DeleteFund(staffContext(), fund.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden deleting a fund, got %v", err)
+	}
+
+	// Reading the catalogue needs only the view permission
+	if _, err := service.GetFund(staffContext(), fund.ID); err != nil {
+		t.Errorf("Unexpected error reading a fund: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+)
+
+// OutboxRelayConfig holds how the relay reads the outbox and retries events
+type OutboxRelayConfig struct {
+	// BatchSize is how many events are claimed from the outbox at a time
+	BatchSize int
+	// ClaimTTL is how long the relay holds the events it claims. Events not
+	// published by then are left to be claimed again.
+	ClaimTTL time.Duration
+	// MaxAttempts is how many times an event is tried before it is dead-lettered
+	MaxAttempts int
+	// RetryBackoff is how long an event waits after its first failure,
+	// doubling after each one after that up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// OutboxRelay publishes the events in the outbox. Events are claimed before
+// they are published, so relays running at once publish different events,
+// and an aggregate's events are published in the order they occurred. An
+// event is marked published only once the publisher has accepted it, so each
+// is delivered at least once: an event whose publication cannot be recorded
+// is published again once its claim runs out.
+type OutboxRelay struct {
+	outbox    output.Outbox
+	publisher output.EventPublisher
+	clock     output.Clock
+	config    OutboxRelayConfig
+}
+
+// NewOutboxRelay creates a relay claiming and retrying events as config sets out
+func NewOutboxRelay(outbox output.Outbox, publisher output.EventPublisher, clock output.Clock, config OutboxRelayConfig) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		clock:     clock,
+		config:    config,
+	}
+}
+
+// Relay publishes the events due to be published until none are left,
+// returning how many were published. An event that fails is tried again
+// after a backoff, holding back only the later events of its aggregate, and
+// is dead-lettered once it has failed MaxAttempts times; the failures are
+// returned together.
+func (r *OutboxRelay) Relay(ctx context.Context) (int, error) {
+	published := 0
+	var errs []error
+	for {
+		now := r.clock.Now()
+		claimedUntil := now.Add(r.config.ClaimTTL)
+		events, err := r.outbox.Claim(ctx, now, claimedUntil, r.config.BatchSize)
+		if err != nil {
+			return published, errors.Join(append(errs, err)...)
+		}
+
+		progressed := false
+		for _, pending := range events {
+			if err := ctx.Err(); err != nil {
+				return published, errors.Join(append(errs, err)...)
+			}
+			// Once the claim runs out another relay may have the rest
+			if !r.clock.Now().Before(claimedUntil) {
+				return published, errors.Join(errs...)
+			}
+
+			settled, err := r.publish(ctx, pending)
+			progressed = progressed || settled
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			published++
+		}
+
+		// Settling an event lets the next of its aggregate be claimed
+		if !progressed {
+			return published, errors.Join(errs...)
+		}
+	}
+}
+
+// publish publishes a claimed event and marks it published, or records the
+// failure against it so it is tried again later or given up on. It reports
+// whether the event was settled, published or given up on, so that it no
+// longer holds back the events after it.
+func (r *OutboxRelay) publish(ctx context.Context, pending *domain.PendingEvent) (bool, error) {
+	event := pending.Event
+	err := r.publisher.Publish(ctx, event)
+	if err == nil {
+		if err := r.outbox.MarkPublished(ctx, event.ID, r.clock.Now()); err != nil {
+			return false, fmt.Errorf("published event %s but failed to mark it published: %w", event.ID, err)
+		}
+		return true, nil
+	}
+
+	attempts := pending.Attempts + 1
+	if attempts >= r.config.MaxAttempts {
+		if recordErr := r.outbox.DeadLetter(ctx, event.ID, err.Error(), r.clock.Now()); recordErr != nil {
+			return false, fmt.Errorf("failed to publish event %s: %w (and to record the failure: %v)", event.ID, err, recordErr)
+		}
+		return true, fmt.Errorf("gave up on event %s after %d attempts: %w", event.ID, attempts, err)
+	}
+
+	retryAt := r.clock.Now().Add(r.backoff(attempts))
+	if recordErr := r.outbox.RecordFailure(ctx, event.ID, err.Error(), retryAt); recordErr != nil {
+		return false, fmt.Errorf("failed to publish event %s: %w (and to record the failure: %v)", event.ID, err, recordErr)
+	}
+	return false, fmt.Errorf("failed to publish event %s: %w", event.ID, err)
+}
+
+// backoff is how long an event waits to be tried again after failing attempts times
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	wait := r.config.RetryBackoff
+	for i := 1; i < attempts && wait < r.config.MaxRetryBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, r.config.MaxRetryBackoff)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+)
+
+// testRelayConfig returns the relay settings the tests use, reading batchSize events at a time
+func testRelayConfig(batchSize int) OutboxRelayConfig {
+	return OutboxRelayConfig{
+		BatchSize:       batchSize,
+		ClaimTTL:        time.Minute,
+		MaxAttempts:     3,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: 90 * time.Second,
+	}
+}
+
+// addEvents adds n direct user created events to the outbox, each for a
+// different user and a minute apart
+func addEvents(t *testing.T, outbox *MockOutbox, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		event, err := domain.NewDirectUserCreatedEvent(domain.NewDirectUser("John Doe", testNow), testNow.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		outbox.Add(context.Background(), event)
+	}
+}
+
+// addUserEvents adds the created and deleted events for one direct user to the outbox
+func addUserEvents(t *testing.T, outbox *MockOutbox) (*domain.Event, *domain.Event) {
+	t.Helper()
+	user := domain.NewDirectUser("Jane Doe", testNow)
+	created, err := domain.NewDirectUserCreatedEvent(user, testNow)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	deleted, err := domain.NewDirectUserDeletedEvent(user, testNow.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	outbox.Add(context.Background(), created)
+	outbox.Add(context.Background(), deleted)
+	return created, deleted
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	outbox := NewMockOutbox()
+	publisher := &MockEventPublisher{}
+	relay := NewOutboxRelay(outbox, publisher, NewMockClock(testNow), testRelayConfig(2))
+	addEvents(t, outbox, 5)
+
+	// Every batch is relayed in one run, in the order the events were added
+	published, err := relay.Relay(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if published != 5 || len(publisher.Published) != 5 {
+		t.Fatalf("Expected 5 events published, got %d (%d reported)", len(publisher.Published), published)
+	}
+	for i, event := range publisher.Published {
+		if event.ID != outbox.Events[i].ID {
+			t.Errorf("Event %d: expected %s, got %s", i, outbox.Events[i].ID, event.ID)
+		}
+		if at, ok := outbox.Published[event.ID]; !ok || !at.Equal(testNow) {
+			t.Errorf("Event %d: expected to be marked published at %v, got %v", i, testNow, at)
+		}
+	}
+
+	// Published events are not published again
+	published, err = relay.Relay(context.Background())
+	if err != nil || published != 0 {
+		t.Errorf("Expected nothing left to publish, got %d, %v", published, err)
+	}
+}
+
+func TestOutboxRelay_Relay_PublishFails(t *testing.T) {
+	outbox := NewMockOutbox()
+	failure := errors.New("webhook unavailable")
+	publisher := &MockEventPublisher{Err: failure}
+	clock := NewMockClock(testNow)
+	relay := NewOutboxRelay(outbox, publisher, clock, testRelayConfig(10))
+	addEvents(t, outbox, 3)
+
+	// Every event is tried, each failure recorded against its event
+	published, err := relay.Relay(context.Background())
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the publisher's error, got %v", err)
+	}
+	if published != 0 || len(outbox.Published) != 0 {
+		t.Errorf("Expected nothing published, got %d", published)
+	}
+	for _, event := range outbox.Events {
+		if reasons := outbox.Failures[event.ID]; len(reasons) != 1 || reasons[0] != failure.Error() {
+			t.Errorf("Expected the failure recorded against %s, got %v", event.ID, reasons)
+		}
+		if retryAt := outbox.RetryAt[event.ID]; !retryAt.Equal(testNow.Add(time.Second)) {
+			t.Errorf("Expected %s to be retried after a second, got %v", event.ID, retryAt)
+		}
+	}
+
+	// Nothing is tried again before the backoff has passed
+	publisher.Err = nil
+	published, err = relay.Relay(context.Background())
+	if err != nil || published != 0 {
+		t.Errorf("Expected nothing due to publish, got %d, %v", published, err)
+	}
+
+	// Once it has the events are published in order
+	clock.Advance(time.Second)
+	published, err = relay.Relay(context.Background())
+	if err != nil || published != 3 {
+		t.Fatalf("Expected 3 events published, got %d, %v", published, err)
+	}
+	if publisher.Published[0].ID != outbox.Events[0].ID {
+		t.Errorf("Expected %s published first, got %s", outbox.Events[0].ID, publisher.Published[0].ID)
+	}
+}
+
+func TestOutboxRelay_Relay_FailureHoldsBackItsAggregate(t *testing.T) {
+	outbox := NewMockOutbox()
+	created, deleted := addUserEvents(t, outbox)
+	addEvents(t, outbox, 1)
+	other := outbox.Events[2]
+	failure := errors.New("rejected")
+	publisher := &MockEventPublisher{Failing: map[string]error{created.ID: failure}}
+	relay := NewOutboxRelay(outbox, publisher, NewMockClock(testNow), testRelayConfig(10))
+
+	// The user's deletion waits behind its creation, other users' events do not
+	published, err := relay.Relay(context.Background())
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the publisher's error, got %v", err)
+	}
+	if published != 1 || len(publisher.Published) != 1 || publisher.Published[0].ID != other.ID {
+		t.Fatalf("Expected only %s published, got %d", other.ID, published)
+	}
+	if _, ok := outbox.Failures[deleted.ID]; ok {
+		t.Errorf("Expected %s not to be tried", deleted.ID)
+	}
+}
+
+func TestOutboxRelay_Relay_DeadLetters(t *testing.T) {
+	outbox := NewMockOutbox()
+	created, deleted := addUserEvents(t, outbox)
+	failure := errors.New("rejected")
+	publisher := &MockEventPublisher{Failing: map[string]error{created.ID: failure}}
+	clock := NewMockClock(testNow)
+	relay := NewOutboxRelay(outbox, publisher, clock, testRelayConfig(10))
+
+	// The wait doubles after each failure
+	for attempt, backoff := range []time.Duration{time.Second, 2 * time.Second} {
+		if _, err := relay.Relay(context.Background()); !errors.Is(err, failure) {
+			t.Fatalf("Attempt %d: expected the publisher's error, got %v", attempt+1, err)
+		}
+		if retryAt := outbox.RetryAt[created.ID]; !retryAt.Equal(clock.Now().Add(backoff)) {
+			t.Errorf("Attempt %d: expected a retry after %s, got %v", attempt+1, backoff, retryAt)
+		}
+		clock.Advance(backoff)
+	}
+
+	// The last attempt gives up on the event, letting the user's next event through
+	published, err := relay.Relay(context.Background())
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "gave up on event "+created.ID+" after 3 attempts") {
+		t.Errorf("Expected the event to be given up on, got %v", err)
+	}
+	if at, ok := outbox.DeadLettered[created.ID]; !ok || !at.Equal(clock.Now()) {
+		t.Errorf("Expected %s dead-lettered at %v, got %v", created.ID, clock.Now(), at)
+	}
+	if published != 1 || publisher.Published[0].ID != deleted.ID {
+		t.Errorf("Expected %s published, got %d", deleted.ID, published)
+	}
+
+	// A dead-lettered event is not tried again
+	clock.Advance(time.Hour)
+	if published, err := relay.Relay(context.Background()); err != nil || published != 0 {
+		t.Errorf("Expected nothing left to publish, got %d, %v", published, err)
+	}
+}
+
+func TestOutboxRelay_Backoff(t *testing.T) {
+	relay := NewOutboxRelay(NewMockOutbox(), &MockEventPublisher{}, NewMockClock(testNow), testRelayConfig(10))
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{7, 64 * time.Second},
+		{8, 90 * time.Second},
+		{1000, 90 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+	"cushon/internal/core/ports/output"
+
+	"github.com/shopspring/decimal"
+)
+
+// PortfolioService implements the input.PortfolioService interface
+type PortfolioService struct {
+	directUserRepo  output.DirectUserRepository
+	transactionRepo output.TransactionRepository
+	fundRepo        output.FundRepository
+	priceRepo       output.FundPriceRepository
+	authorizer      output.Authorizer
+	clock           output.Clock
+}
+
+// NewPortfolioService creates a new portfolio service instance
+func NewPortfolioService(directUserRepo output.DirectUserRepository, transactionRepo output.TransactionRepository, fundRepo output.FundRepository, priceRepo output.FundPriceRepository, authorizer output.Authorizer, clock output.Clock) input.PortfolioService {
+	return &PortfolioService{
+		directUserRepo:  directUserRepo,
+		transactionRepo: transactionRepo,
+		fundRepo:        fundRepo,
+		priceRepo:       priceRepo,
+		authorizer:      authorizer,
+		clock:           clock,
+	}
+}
+
+// GetPortfolio implements the portfolio valuation use case
+func (s *PortfolioService) GetPortfolio(ctx context.Context, userID string) (*domain.Portfolio, error) {
+	if userID == "" {
+		return nil, domain.NewValidationError("id", "direct user ID is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewAccount, userID); err != nil {
+		return nil, err
+	}
+
+	// Fail with the repository's not found error rather than an empty portfolio
+	if _, err := s.directUserRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Aggregate transactions into a holding per fund
+	holdingsByFund := make(map[domain.FundName]*domain.Holding)
+	for _, transaction := range transactions {
+		holding, exists := holdingsByFund[transaction.FundName]
+		if !exists {
+			holding = &domain.Holding{
+				FundName:      transaction.FundName,
+				Units:         decimal.Zero,
+				BookCost:      decimal.Zero,
+				MarketValue:   decimal.Zero,
+				PendingAmount: decimal.Zero,
+			}
+			holdingsByFund[transaction.FundName] = holding
+		}
+
+		if !transaction.IsPriced() {
+			holding.PendingAmount = holding.PendingAmount.Add(transaction.SignedAmount())
+			continue
+		}
+		holding.Units = holding.Units.Add(transaction.Units)
+		holding.BookCost = holding.BookCost.Add(transaction.SignedAmount())
+	}
+
+	holdings := make([]*domain.Holding, 0, len(holdingsByFund))
+	for _, holding := range holdingsByFund {
+		if err := s.value(ctx, holding); err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, holding)
+	}
+
+	sort.Slice(holdings, func(i, j int) bool {
+		return holdings[i].FundName < holdings[j].FundName
+	})
+
+	return domain.NewPortfolio(userID, holdings, s.clock.Now()), nil
+}
+
+// value prices a holding at its fund's latest bid price
+func (s *PortfolioService) value(ctx context.Context, holding *domain.Holding) error {
+	fund, err := s.fundRepo.FindByName(ctx, holding.FundName)
+	if err != nil {
+		return err
+	}
+
+	price, err := s.priceRepo.FindLatest(ctx, fund.ID)
+	if err != nil {
+		return err
+	}
+	if price == nil {
+		return nil
+	}
+
+	holding.Price = price
+	holding.MarketValue = holding.Units.Mul(price.Bid).RoundBank(2)
+	return nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPortfolioService_GetPortfolio(t *testing.T) {
+	userRepo := NewMockDirectUserRepository()
+	transactionRepo := NewMockTransactionRepository()
+	fundRepo := NewSeededMockFundRepository()
+	priceRepo := NewMockFundPriceRepository()
+	service := NewPortfolioService(userRepo, transactionRepo, fundRepo, priceRepo, NewMockAuthorizer(), NewMockClock(testNow))
+
+	user := domain.NewDirectUser("John Doe", testNow)
+	userRepo.Save(context.Background(), user)
+	emptyUser := domain.NewDirectUser("Jane Doe", testNow)
+	userRepo.Save(context.Background(), emptyUser)
+
+	fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+	bondFund := domain.NewFund("Cushon Bond Fund", "US0378331005", domain.AssetClassBond, "GBP", 3)
+	fundRepo.Save(context.Background(), bondFund)
+
+	// Two deposits priced at different valuation points, one still pending
+	firstPrice := domain.NewFundPrice(fund.ID, testNow.Add(-48*time.Hour), decimal.RequireFromString("1.95"), decimal.NewFromInt(2))
+	latestPrice := domain.NewFundPrice(fund.ID, testNow.Add(-24*time.Hour), decimal.RequireFromString("2.45"), decimal.RequireFromString("2.50"))
+	priceRepo.Save(context.Background(), firstPrice)
+	priceRepo.Save(context.Background(), latestPrice)
+
+	first := domain.NewTransaction(user.ID, domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund, testNow)
+	first.Allocate(firstPrice, testNow)
+	second := domain.NewTransaction(user.ID, domain.TransactionTypeDeposit, decimal.NewFromInt(500), domain.CushonEquitiesFund, testNow)
+	second.Allocate(latestPrice, testNow)
+	withdrawal := domain.NewTransaction(user.ID, domain.TransactionTypeWithdrawal, decimal.RequireFromString("245"), domain.CushonEquitiesFund, testNow)
+	withdrawal.Allocate(latestPrice, testNow)
+	pending := domain.NewTransaction(user.ID, domain.TransactionTypeDeposit, decimal.NewFromInt(250), domain.CushonEquitiesFund, testNow)
+	unpricedFund := domain.NewTransaction(user.ID, domain.TransactionTypeDeposit, decimal.NewFromInt(100), "Cushon Bond Fund", testNow)
+	for _, transaction := range []*domain.Transaction{first, second, withdrawal, pending, unpricedFund} {
+		transactionRepo.Save(context.Background(), transaction)
+	}
+
+	portfolio, err := service.GetPortfolio(staffContext(), user.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(portfolio.Holdings) != 2 {
+		t.Fatalf("Expected 2 holdings, got %d", len(portfolio.Holdings))
+	}
+
+	// Holdings are ordered by fund name
+	bond, equities := portfolio.Holdings[0], portfolio.Holdings[1]
+
+	// 500 + 200 units bought, 100 sold at the bid price
+	if !equities.Units.Equal(decimal.NewFromInt(600)) {
+		t.Errorf("Expected 600 units, got %s", equities.Units)
+	}
+	if !equities.BookCost.Equal(decimal.NewFromInt(1255)) {
+		t.Errorf("Expected book cost 1255, got %s", equities.BookCost)
+	}
+	if !equities.MarketValue.Equal(decimal.NewFromInt(1470)) {
+		t.Errorf("Expected market value 1470 (600 units at bid 2.45), got %s", equities.MarketValue)
+	}
+	if !equities.PendingAmount.Equal(decimal.NewFromInt(250)) {
+		t.Errorf("Expected pending amount 250, got %s", equities.PendingAmount)
+	}
+	if equities.Price == nil || equities.Price.ID != latestPrice.ID {
+		t.Error("Expected holding to be valued at the latest price")
+	}
+
+	if bond.Price != nil || !bond.MarketValue.IsZero() || !bond.PendingAmount.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected unpriced bond holding with 100 pending, got %+v", bond)
+	}
+
+	if !portfolio.MarketValue.Equal(decimal.NewFromInt(1470)) || !portfolio.PendingAmount.Equal(decimal.NewFromInt(350)) {
+		t.Errorf("Expected portfolio totals 1470 and 350 pending, got %s and %s", portfolio.MarketValue, portfolio.PendingAmount)
+	}
+
+	// A user without transactions has an empty portfolio
+	emptyPortfolio, err := service.GetPortfolio(staffContext(), emptyUser.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(emptyPortfolio.Holdings) != 0 || !emptyPortfolio.MarketValue.IsZero() {
+		t.Errorf("Expected empty portfolio, got %+v", emptyPortfolio)
+	}
+}
+
+func TestPortfolioService_GetPortfolio_UnknownUser(t *testing.T) {
+	service := NewPortfolioService(NewMockDirectUserRepository(), NewMockTransactionRepository(), NewSeededMockFundRepository(), NewMockFundPriceRepository(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	if _, err := service.GetPortfolio(staffContext(), "non-existent"); err == nil {
+		t.Error("Expected error for unknown user, got nil")
+	}
+
+	if _, err := service.GetPortfolio(staffContext(), ""); err == nil {
+		t.Error("Expected error for empty user ID, got nil")
+	}
+}
+
+func TestPortfolioService_GetPortfolio_AnotherCustomer(t *testing.T) {
+	service := NewPortfolioService(NewSeededMockDirectUserRepository("user123", "user456"), NewMockTransactionRepository(), NewSeededMockFundRepository(), NewMockFundPriceRepository(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	if _, err := service.GetPortfolio(customerContext("user123"), "user123"); err != nil {
+		t.Errorf("Unexpected error for the customer's own portfolio: %v", err)
+	}
+
+	if _, err := service.GetPortfolio(customerContext("user123"), "user456"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for another customer's portfolio, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cushon/internal/core/ports/output"
+	"cushon/internal/health"
+)
+
+// NewPriceFreshnessCheck creates a health check that fails when an open
+// fund's latest price is older than maxAge, which usually means the daily
+// price feed has stopped and deposits are waiting for units. Funds that have
+// never been priced are skipped, since a new fund has no price until its
+// first valuation point.
+func NewPriceFreshnessCheck(fundRepo output.FundRepository, priceRepo output.FundPriceRepository, maxAge time.Duration, clock output.Clock) health.Check {
+	return func(ctx context.Context) error {
+		funds, err := fundRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		var stale []string
+		for _, fund := range funds {
+			if !fund.IsOpen() {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			latest, err := priceRepo.FindLatest(ctx, fund.ID)
+			if err != nil {
+				return err
+			}
+			if latest != nil && clock.Now().Sub(latest.ValuationDate) > maxAge {
+				stale = append(stale, fmt.Sprintf("%s last priced %s", fund.Name, latest.ValuationDate.Format(time.RFC3339)))
+			}
+		}
+
+		if len(stale) > 0 {
+			return fmt.Errorf("prices older than %s: %s", maxAge, strings.Join(stale, "; "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPriceFreshnessCheck(t *testing.T) {
+	now := time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC)
+	maxAge := 96 * time.Hour
+
+	tests := []struct {
+		name      string
+		pricedAgo time.Duration
+		closed    bool
+		wantErr   string
+	}{
+		{name: "priced yesterday", pricedAgo: 24 * time.Hour},
+		{name: "priced over a long weekend", pricedAgo: maxAge},
+		{name: "price feed stopped", pricedAgo: maxAge + time.Hour, wantErr: "Cushon Equities Fund last priced 2024-06-10T08:00:00Z"},
+		{name: "closed fund no longer priced", pricedAgo: 30 * 24 * time.Hour, closed: true},
+		{name: "never priced"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fundRepo := NewSeededMockFundRepository()
+			priceRepo := NewMockFundPriceRepository()
+			fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+			if tt.closed {
+				fund.Status = domain.FundStatusClosed
+			}
+			if tt.pricedAgo > 0 {
+				priceRepo.Save(context.Background(), domain.NewNAVFundPrice(fund.ID, now.Add(-tt.pricedAgo), decimal.NewFromInt(2)))
+			}
+
+			check := NewPriceFreshnessCheck(fundRepo, priceRepo, maxAge, NewMockClock(now))
+			err := check(context.Background())
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/input"
+	"cushon/internal/core/ports/output"
+)
+
+// PricingService implements the input.PricingService interface
+type PricingService struct {
+	fundRepo        output.FundRepository
+	priceRepo       output.FundPriceRepository
+	transactionRepo output.TransactionRepository
+	unitOfWork      output.UnitOfWork
+	auditLog        output.AuditLog
+	authorizer      output.Authorizer
+	clock           output.Clock
+}
+
+// NewPricingService creates a new pricing service instance
+func NewPricingService(fundRepo output.FundRepository, priceRepo output.FundPriceRepository, transactionRepo output.TransactionRepository, unitOfWork output.UnitOfWork, auditLog output.AuditLog, authorizer output.Authorizer, clock output.Clock) input.PricingService {
+	return &PricingService{
+		fundRepo:        fundRepo,
+		priceRepo:       priceRepo,
+		transactionRepo: transactionRepo,
+		unitOfWork:      unitOfWork,
+		auditLog:        auditLog,
+		authorizer:      authorizer,
+		clock:           clock,
+	}
+}
+
+// ImportPrices implements the fund price import use case. Prices must be
+// imported in valuation date order, so each pending transaction is priced at
+// the first valuation point after it was placed. The import is all or
+// nothing: if any price or allocation fails to save, none is kept.
+func (s *PricingService) ImportPrices(ctx context.Context, fundID string, prices []*domain.FundPrice) (*domain.PriceImport, error) {
+	if fundID == "" {
+		return nil, domain.NewValidationError("fund_id", "fund ID is required")
+	}
+	if len(prices) == 0 {
+		return nil, domain.NewValidationError("prices", "at least one price is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageFunds, ""); err != nil {
+		return nil, err
+	}
+
+	fund, err := s.fundRepo.FindByID(ctx, fundID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, price := range prices {
+		if price == nil {
+			return nil, domain.NewValidationError("prices", "price cannot be nil")
+		}
+		price.FundID = fund.ID
+		if err := price.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].ValuationDate.Before(prices[j].ValuationDate)
+	})
+
+	var result *domain.PriceImport
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		latest, err := s.priceRepo.FindLatest(ctx, fund.ID)
+		if err != nil {
+			return err
+		}
+		for i, price := range prices {
+			if latest != nil && !price.ValuationDate.After(latest.ValuationDate) {
+				return domain.NewValidationError("valuation_date", "prices must be after the latest valuation date")
+			}
+			if i > 0 && price.ValuationDate.Equal(prices[i-1].ValuationDate) {
+				return domain.NewValidationError("valuation_date", "duplicate valuation date")
+			}
+		}
+
+		result = &domain.PriceImport{FundID: fund.ID}
+		for _, price := range prices {
+			if err := s.priceRepo.Save(ctx, price); err != nil {
+				return err
+			}
+			result.PricesImported++
+
+			priced, err := s.allocatePending(ctx, fund, price)
+			if err != nil {
+				return err
+			}
+			result.TransactionsPriced += priced
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetFundPrices implements the fund price history retrieval use case
+func (s *PricingService) GetFundPrices(ctx context.Context, fundID string) ([]*domain.FundPrice, error) {
+	if fundID == "" {
+		return nil, domain.NewValidationError("fund_id", "fund ID is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewFunds, ""); err != nil {
+		return nil, err
+	}
+
+	fund, err := s.fundRepo.FindByID(ctx, fundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.priceRepo.FindByFundID(ctx, fund.ID)
+}
+
+// allocatePending prices every pending transaction placed before the valuation point
+func (s *PricingService) allocatePending(ctx context.Context, fund *domain.Fund, price *domain.FundPrice) (int, error) {
+	transactions, err := s.transactionRepo.FindUnpriced(ctx, fund.Name, price.ValuationDate)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, transaction := range transactions {
+		before := *transaction
+		if err := transaction.Allocate(price, s.clock.Now()); err != nil {
+			return 0, err
+		}
+		if err := s.transactionRepo.AllocateUnits(ctx, transaction); err != nil {
+			return 0, err
+		}
+		if err := recordChange(ctx, s.auditLog, s.clock, domain.AuditActionAllocateUnits, &before, transaction); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(transactions), nil
+}
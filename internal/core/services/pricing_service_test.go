@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cushon/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func setupPricingTest() (*PricingService, *MockTransactionRepository, *domain.Fund) {
+	fundRepo := NewSeededMockFundRepository()
+	transactionRepo := NewMockTransactionRepository()
+	service := NewPricingService(fundRepo, NewMockFundPriceRepository(), transactionRepo, NewMockUnitOfWork(), NewMockAuditLog(), NewMockAuthorizer(), NewMockClock(testNow)).(*PricingService)
+	fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+	return service, transactionRepo, fund
+}
+
+func TestPricingService_ImportPrices_AllocatesPendingTransactions(t *testing.T) {
+	service, transactionRepo, fund := setupPricingTest()
+
+	deposit := domain.NewTransaction("user123", domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund, testNow)
+	transactionRepo.Save(context.Background(), deposit)
+
+	// A price for a valuation point before the deposit was placed does not price it
+	earlier := domain.NewNAVFundPrice("", testNow.Add(-time.Hour), decimal.NewFromInt(4))
+	result, err := service.ImportPrices(staffContext(), fund.ID, []*domain.FundPrice{earlier})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TransactionsPriced != 0 || deposit.IsPriced() {
+		t.Fatalf("Expected deposit to remain pending, priced %d", result.TransactionsPriced)
+	}
+
+	// The next valuation point prices the deposit
+	next := domain.NewFundPrice("", testNow.Add(time.Hour), decimal.RequireFromString("1.95"), decimal.NewFromInt(2))
+	result, err = service.ImportPrices(staffContext(), fund.ID, []*domain.FundPrice{next})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.PricesImported != 1 || result.TransactionsPriced != 1 {
+		t.Errorf("Expected 1 price imported and 1 transaction priced, got %+v", result)
+	}
+
+	priced, _ := transactionRepo.FindByID(context.Background(), deposit.ID)
+	if !priced.IsPriced() {
+		t.Fatal("Expected deposit to be priced")
+	}
+	if !priced.Units.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("Expected 500 units, got %s", priced.Units)
+	}
+	if !priced.UnitPrice.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Expected unit price 2, got %s", priced.UnitPrice)
+	}
+}
+
+func TestPricingService_ImportPrices_OneUnitOfWork(t *testing.T) {
+	service, _, fund := setupPricingTest()
+	unitOfWork := service.unitOfWork.(*MockUnitOfWork)
+
+	prices := []*domain.FundPrice{
+		domain.NewNAVFundPrice("", testNow.Add(time.Hour), decimal.NewFromInt(2)),
+		domain.NewNAVFundPrice("", testNow.Add(2*time.Hour), decimal.NewFromInt(3)),
+	}
+	if _, err := service.ImportPrices(staffContext(), fund.ID, prices); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Every price in the import is saved in the same unit, so a failure keeps none of them
+	if unitOfWork.Calls != 1 {
+		t.Errorf("Expected the import to run in 1 unit of work, got %d", unitOfWork.Calls)
+	}
+}
+
+func TestPricingService_ImportPrices_Validation(t *testing.T) {
+	service, _, fund := setupPricingTest()
+
+	if _, err := service.ImportPrices(staffContext(), fund.ID, []*domain.FundPrice{domain.NewNAVFundPrice("", testNow, decimal.NewFromInt(1))}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		fundID string
+		prices []*domain.FundPrice
+	}{
+		{
+			name:   "unknown fund",
+			fundID: "non-existent",
+			prices: []*domain.FundPrice{domain.NewNAVFundPrice("", testNow.Add(time.Hour), decimal.NewFromInt(1))},
+		},
+		{
+			name:   "no prices",
+			fundID: fund.ID,
+			prices: nil,
+		},
+		{
+			name:   "invalid price",
+			fundID: fund.ID,
+			prices: []*domain.FundPrice{domain.NewNAVFundPrice("", testNow.Add(time.Hour), decimal.Zero)},
+		},
+		{
+			name:   "valuation date not after latest",
+			fundID: fund.ID,
+			prices: []*domain.FundPrice{domain.NewNAVFundPrice("", testNow, decimal.NewFromInt(1))},
+		},
+		{
+			name:   "duplicate valuation dates",
+			fundID: fund.ID,
+			prices: []*domain.FundPrice{
+				domain.NewNAVFundPrice("", testNow.Add(time.Hour), decimal.NewFromInt(1)),
+				domain.NewNAVFundPrice("", testNow.Add(time.Hour), decimal.NewFromInt(2)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ImportPrices(staffContext(), tt.fundID, tt.prices); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestPricingService_GetFundPrices(t *testing.T) {
+	service, _, fund := setupPricingTest()
+
+	service.ImportPrices(staffContext(), fund.ID, []*domain.FundPrice{
+		domain.NewNAVFundPrice("", testNow.Add(time.Hour), decimal.NewFromInt(2)),
+		domain.NewNAVFundPrice("", testNow, decimal.NewFromInt(1)),
+	})
+
+	prices, err := service.GetFundPrices(staffContext(), fund.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Errorf("Expected 2 prices, got %d", len(prices))
+	}
+
+	if _, err := service.GetFundPrices(staffContext(), "non-existent"); err == nil {
+		t.Error("Expected error for unknown fund, got nil")
+	}
+}
+
+func TestPricingService_ImportPrices_NeedsPermission(t *testing.T) {
+	fundRepo := NewSeededMockFundRepository()
+	priceRepo := NewMockFundPriceRepository()
+	service := NewPricingService(fundRepo, priceRepo, NewMockTransactionRepository(), NewMockUnitOfWork(), NewMockAuditLog(), NewMockAuthorizer(domain.PermissionManageFunds), NewMockClock(testNow))
+	fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+
+	_, err := service.ImportPrices(staffContext(), fund.ID, []*domain.FundPrice{domain.NewNAVFundPrice("", testNow, decimal.NewFromInt(1))})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden, got %v", err)
+	}
+
+	prices, err := service.GetFundPrices(staffContext(), fund.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 0 {
+		t.Errorf("Expected no prices imported, got %d", len(prices))
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"cushon/internal/core/domain"
+	"cushon/internal/core/ports/output"
+	"cushon/internal/core/ports/output/outputtest"
+)
+
+// The mock repositories stand in for the real adapters in the service tests,
+// so they are held to the same contract
+
+func TestMockDirectUserRepository_Conformance(t *testing.T) {
+	outputtest.RunDirectUserRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.DirectUser) output.DirectUserRepository {
+		repo := NewMockDirectUserRepository()
+		if existing != nil {
+			repo.Save(context.Background(), existing)
+		}
+		return repo
+	})
+}
+
+func TestMockFundRepository_Conformance(t *testing.T) {
+	outputtest.RunFundRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.Fund) output.FundRepository {
+		repo := NewMockFundRepository()
+		if existing != nil {
+			repo.Save(context.Background(), existing)
+		}
+		return repo
+	})
+}
+
+func TestMockFundPriceRepository_Conformance(t *testing.T) {
+	outputtest.RunFundPriceRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.FundPrice) output.FundPriceRepository {
+		repo := NewMockFundPriceRepository()
+		if existing != nil {
+			repo.Save(context.Background(), existing)
+		}
+		return repo
+	})
+}
+
+func TestMockTransactionRepository_Conformance(t *testing.T) {
+	outputtest.RunTransactionRepository(t, func(t *testing.T, _ outputtest.Op, existing *domain.Transaction) output.TransactionRepository {
+		repo := NewMockTransactionRepository()
+		if existing != nil {
+			repo.Save(context.Background(), existing)
+		}
+		return repo
+	})
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"cushon/internal/core/domain"
 )
@@ -17,7 +19,16 @@ func NewMockDirectUserRepository() *MockDirectUserRepository {
 	}
 }
 
-func (m *MockDirectUserRepository) Save(user *domain.DirectUser) error {
+// NewSeededMockDirectUserRepository returns a direct user repository holding a user for each ID
+func NewSeededMockDirectUserRepository(ids ...string) *MockDirectUserRepository {
+	repo := NewMockDirectUserRepository()
+	for _, id := range ids {
+		repo.Save(context.Background(), &domain.DirectUser{ID: id, Name: "Test User"})
+	}
+	return repo
+}
+
+func (m *MockDirectUserRepository) Save(ctx context.Context, user *domain.DirectUser) error {
 	if user == nil {
 		return errors.New("user cannot be nil")
 	}
@@ -31,17 +42,17 @@ func (m *MockDirectUserRepository) Save(user *domain.DirectUser) error {
 	return nil
 }
 
-func (m *MockDirectUserRepository) FindByID(id string) (*domain.DirectUser, error) {
+func (m *MockDirectUserRepository) FindByID(ctx context.Context, id string) (*domain.DirectUser, error) {
 	if id == "" {
 		return nil, errors.New("user ID is required")
 	}
 	if user, exists := m.users[id]; exists {
 		return user, nil
 	}
-	return nil, errors.New("direct user not found")
+	return nil, domain.ErrUserNotFound
 }
 
-func (m *MockDirectUserRepository) FindByName(name string) (*domain.DirectUser, error) {
+func (m *MockDirectUserRepository) FindByName(ctx context.Context, name string) (*domain.DirectUser, error) {
 	if name == "" {
 		return nil, errors.New("name is required")
 	}
@@ -50,10 +61,10 @@ func (m *MockDirectUserRepository) FindByName(name string) (*domain.DirectUser,
 			return user, nil
 		}
 	}
-	return nil, errors.New("direct user not found")
+	return nil, domain.ErrUserNotFound
 }
 
-func (m *MockDirectUserRepository) Update(user *domain.DirectUser) error {
+func (m *MockDirectUserRepository) Update(ctx context.Context, user *domain.DirectUser) error {
 	if user == nil {
 		return errors.New("user cannot be nil")
 	}
@@ -64,19 +75,321 @@ func (m *MockDirectUserRepository) Update(user *domain.DirectUser) error {
 		return errors.New("name is required")
 	}
 	if _, exists := m.users[user.ID]; !exists {
-		return errors.New("direct user not found")
+		return domain.ErrUserNotFound
 	}
 	m.users[user.ID] = user
 	return nil
 }
 
-func (m *MockDirectUserRepository) Delete(id string) error {
+func (m *MockDirectUserRepository) Delete(ctx context.Context, id string) error {
 	if id == "" {
 		return errors.New("user ID is required")
 	}
 	if _, exists := m.users[id]; !exists {
-		return errors.New("direct user not found")
+		return domain.ErrUserNotFound
 	}
 	delete(m.users, id)
 	return nil
-} 
\ No newline at end of file
+} 
+
+// MockFundRepository implements output.FundRepository for testing
+type MockFundRepository struct {
+	funds map[string]*domain.Fund
+}
+
+func NewMockFundRepository() *MockFundRepository {
+	return &MockFundRepository{
+		funds: make(map[string]*domain.Fund),
+	}
+}
+
+// NewSeededMockFundRepository returns a fund repository holding the open Cushon Equities Fund
+func NewSeededMockFundRepository() *MockFundRepository {
+	repo := NewMockFundRepository()
+	repo.Save(context.Background(), domain.NewFund(domain.CushonEquitiesFund, "GB00B3X7QG63", domain.AssetClassEquity, "GBP", 5))
+	return repo
+}
+
+func (m *MockFundRepository) Save(ctx context.Context, fund *domain.Fund) error {
+	if fund == nil {
+		return errors.New("fund cannot be nil")
+	}
+	if fund.ID == "" {
+		return errors.New("fund ID is required")
+	}
+	for _, existing := range m.funds {
+		if existing.ID == fund.ID || existing.Name == fund.Name || existing.ISIN == fund.ISIN {
+			return domain.ErrFundExists
+		}
+	}
+	m.funds[fund.ID] = fund
+	return nil
+}
+
+func (m *MockFundRepository) FindByID(ctx context.Context, id string) (*domain.Fund, error) {
+	if fund, exists := m.funds[id]; exists {
+		return fund, nil
+	}
+	return nil, domain.ErrFundNotFound
+}
+
+func (m *MockFundRepository) FindByName(ctx context.Context, name domain.FundName) (*domain.Fund, error) {
+	for _, fund := range m.funds {
+		if fund.Name == name {
+			return fund, nil
+		}
+	}
+	return nil, domain.ErrFundNotFound
+}
+
+func (m *MockFundRepository) FindAll(ctx context.Context) ([]*domain.Fund, error) {
+	var funds []*domain.Fund
+	for _, fund := range m.funds {
+		funds = append(funds, fund)
+	}
+	return funds, nil
+}
+
+func (m *MockFundRepository) Update(ctx context.Context, fund *domain.Fund) error {
+	if _, exists := m.funds[fund.ID]; !exists {
+		return domain.ErrFundNotFound
+	}
+	for id, existing := range m.funds {
+		if id != fund.ID && existing.ISIN == fund.ISIN {
+			return domain.ErrFundISINTaken
+		}
+	}
+	m.funds[fund.ID] = fund
+	return nil
+}
+
+func (m *MockFundRepository) Delete(ctx context.Context, id string) error {
+	if _, exists := m.funds[id]; !exists {
+		return domain.ErrFundNotFound
+	}
+	delete(m.funds, id)
+	return nil
+}
+
+// MockFundPriceRepository implements output.FundPriceRepository for testing
+type MockFundPriceRepository struct {
+	prices []*domain.FundPrice
+}
+
+func NewMockFundPriceRepository() *MockFundPriceRepository {
+	return &MockFundPriceRepository{}
+}
+
+func (m *MockFundPriceRepository) Save(ctx context.Context, price *domain.FundPrice) error {
+	if price == nil {
+		return errors.New("price cannot be nil")
+	}
+	m.prices = append(m.prices, price)
+	return nil
+}
+
+func (m *MockFundPriceRepository) FindByFundID(ctx context.Context, fundID string) ([]*domain.FundPrice, error) {
+	var prices []*domain.FundPrice
+	for _, price := range m.prices {
+		if price.FundID == fundID {
+			prices = append(prices, price)
+		}
+	}
+	return prices, nil
+}
+
+func (m *MockFundPriceRepository) FindLatest(ctx context.Context, fundID string) (*domain.FundPrice, error) {
+	var latest *domain.FundPrice
+	for _, price := range m.prices {
+		if price.FundID == fundID && (latest == nil || price.ValuationDate.After(latest.ValuationDate)) {
+			latest = price
+		}
+	}
+	return latest, nil
+}
+
+// MockUnitOfWork implements output.UnitOfWork for testing. It runs fn
+// directly, so nothing is rolled back, and counts the units of work run.
+type MockUnitOfWork struct {
+	Calls int
+}
+
+func NewMockUnitOfWork() *MockUnitOfWork {
+	return &MockUnitOfWork{}
+}
+
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Calls++
+	return fn(ctx)
+}
+
+// testNow is the time the mock clock shows in the service tests
+var testNow = time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC)
+
+// MockClock implements output.Clock for testing, standing still at a fixed time
+type MockClock struct {
+	now time.Time
+}
+
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+func (m *MockClock) Now() time.Time {
+	return m.now
+}
+
+// Advance moves the clock forward by d
+func (m *MockClock) Advance(d time.Duration) {
+	m.now = m.now.Add(d)
+}
+
+// testStaff is the member of staff the service tests act as
+var testStaff = &domain.Principal{Subject: "ops@cushon.co.uk", Role: domain.RoleAdmin}
+
+// staffContext returns a context carrying a member of staff, who may act on any account
+func staffContext() context.Context {
+	return domain.ContextWithPrincipal(context.Background(), testStaff)
+}
+
+// customerContext returns a context carrying the customer who owns the direct user
+func customerContext(userID string) context.Context {
+	return domain.ContextWithPrincipal(context.Background(), &domain.Principal{Subject: userID, Role: domain.RoleCustomer})
+}
+
+// MockAuthorizer implements output.Authorizer for testing. It grants every
+// permission but those it is told to deny, keeping customers to their own
+// accounts, and records the permissions it was asked for.
+type MockAuthorizer struct {
+	denied    map[domain.Permission]bool
+	Requested []domain.Permission
+}
+
+func NewMockAuthorizer(denied ...domain.Permission) *MockAuthorizer {
+	m := &MockAuthorizer{denied: make(map[domain.Permission]bool)}
+	for _, permission := range denied {
+		m.denied[permission] = true
+	}
+	return m
+}
+
+func (m *MockAuthorizer) Authorize(ctx context.Context, permission domain.Permission, ownerID string) error {
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	m.Requested = append(m.Requested, permission)
+	if m.denied[permission] {
+		return domain.NewForbiddenError("permission denied")
+	}
+	if ownerID != "" && !principal.CanAccessUser(ownerID) {
+		return domain.NewForbiddenError("the direct user's account belongs to someone else")
+	}
+	return nil
+}
+
+// MockAuditLog implements output.AuditLog for testing, keeping the entries it records
+type MockAuditLog struct {
+	Entries []*domain.AuditEntry
+}
+
+func NewMockAuditLog() *MockAuditLog {
+	return &MockAuditLog{}
+}
+
+func (m *MockAuditLog) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+func (m *MockAuditLog) FindPage(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	page := &domain.AuditPage{}
+	for _, entry := range m.Entries {
+		if query.Matches(entry) && len(page.Entries) < query.Limit {
+			page.Entries = append(page.Entries, entry)
+		}
+	}
+	return page, nil
+}
+
+// MockOutbox implements output.Outbox for testing, keeping the events added
+// and what became of them. Claims are not held, there being one relay.
+type MockOutbox struct {
+	Events    []*domain.Event
+	Published map[string]time.Time
+	// Failures holds the reasons each event failed to publish
+	Failures map[string][]string
+	// RetryAt holds when each failed event may be tried again
+	RetryAt map[string]time.Time
+	// DeadLettered holds when each event was given up on
+	DeadLettered map[string]time.Time
+}
+
+func NewMockOutbox() *MockOutbox {
+	return &MockOutbox{
+		Published:    make(map[string]time.Time),
+		Failures:     make(map[string][]string),
+		RetryAt:      make(map[string]time.Time),
+		DeadLettered: make(map[string]time.Time),
+	}
+}
+
+func (m *MockOutbox) Add(ctx context.Context, event *domain.Event) error {
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+func (m *MockOutbox) Claim(ctx context.Context, now time.Time, until time.Time, limit int) ([]*domain.PendingEvent, error) {
+	var claimed []*domain.PendingEvent
+	waiting := make(map[string]bool)
+	for _, event := range m.Events {
+		_, published := m.Published[event.ID]
+		_, dead := m.DeadLettered[event.ID]
+		if published || dead || waiting[event.AggregateID] {
+			continue
+		}
+		waiting[event.AggregateID] = true
+		if len(claimed) < limit && !m.RetryAt[event.ID].After(now) {
+			claimed = append(claimed, &domain.PendingEvent{Event: event, Attempts: len(m.Failures[event.ID])})
+		}
+	}
+	return claimed, nil
+}
+
+func (m *MockOutbox) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	m.Published[id] = publishedAt
+	return nil
+}
+
+func (m *MockOutbox) RecordFailure(ctx context.Context, id string, reason string, retryAt time.Time) error {
+	m.Failures[id] = append(m.Failures[id], reason)
+	m.RetryAt[id] = retryAt
+	return nil
+}
+
+func (m *MockOutbox) DeadLetter(ctx context.Context, id string, reason string, at time.Time) error {
+	m.Failures[id] = append(m.Failures[id], reason)
+	m.DeadLettered[id] = at
+	return nil
+}
+
+// MockEventPublisher implements output.EventPublisher for testing, keeping
+// the events it publishes
+type MockEventPublisher struct {
+	Published []*domain.Event
+	// Err, if set, fails every publish
+	Err error
+	// Failing holds the error for each event, by ID, that always fails
+	Failing map[string]error
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event *domain.Event) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if err := m.Failing[event.ID]; err != nil {
+		return err
+	}
+	m.Published = append(m.Published, event)
+	return nil
+}
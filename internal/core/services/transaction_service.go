@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"cushon/internal/core/domain"
 	"cushon/internal/core/ports/input"
@@ -13,33 +15,87 @@ import (
 // TransactionService implements the input.TransactionService interface
 type TransactionService struct {
 	transactionRepo output.TransactionRepository
+	directUserRepo  output.DirectUserRepository
+	fundRepo        output.FundRepository
+	unitOfWork      output.UnitOfWork
+	allowancePolicy domain.AllowancePolicy
+	auditLog        output.AuditLog
+	outbox          output.Outbox
+	authorizer      output.Authorizer
+	clock           output.Clock
 }
 
 // NewTransactionService creates a new transaction service instance
-func NewTransactionService(transactionRepo output.TransactionRepository) input.TransactionService {
+func NewTransactionService(transactionRepo output.TransactionRepository, directUserRepo output.DirectUserRepository, fundRepo output.FundRepository, unitOfWork output.UnitOfWork, allowancePolicy domain.AllowancePolicy, auditLog output.AuditLog, outbox output.Outbox, authorizer output.Authorizer, clock output.Clock) input.TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
+		directUserRepo:  directUserRepo,
+		fundRepo:        fundRepo,
+		unitOfWork:      unitOfWork,
+		allowancePolicy: allowancePolicy,
+		auditLog:        auditLog,
+		outbox:          outbox,
+		authorizer:      authorizer,
+		clock:           clock,
 	}
 }
 
 // CreateTransaction implements the transaction creation use case
-func (s *TransactionService) CreateTransaction(userID string, amount decimal.Decimal, fundName domain.FundName) (*domain.Transaction, error) {
+func (s *TransactionService) CreateTransaction(ctx context.Context, userID string, transactionType domain.TransactionType, amount decimal.Decimal, fundName domain.FundName) (*domain.Transaction, error) {
 	// Validate input
 	if userID == "" {
-		return nil, errors.New("user ID is required")
+		return nil, domain.NewValidationError("user_id", "user ID is required")
 	}
-	if amount.IsZero() {
-		return nil, errors.New("amount cannot be zero")
+	if !transactionType.IsValid() {
+		return nil, domain.NewValidationError("type", "invalid transaction type")
 	}
-	if !fundName.IsValid() {
-		return nil, errors.New("invalid fund name")
+	if !amount.IsPositive() {
+		return nil, domain.NewValidationError("amount", "amount must be positive")
+	}
+	// Customers may deposit and withdraw; other types need operations
+	if err := s.authorizer.Authorize(ctx, transactionType.Permission(), userID); err != nil {
+		return nil, err
 	}
 
-	// Create new transaction
-	transaction := domain.NewTransaction(userID, amount, fundName)
+	// The transaction is saved, audited and its event added in one unit of
+	// work. The balance and allowance are checked by the repository as it
+	// saves, under a lock on the user's account, so concurrent requests
+	// cannot both spend the same headroom.
+	var transaction *domain.Transaction
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.checkUserExists(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.validateFund(ctx, fundName, transactionType); err != nil {
+			return err
+		}
+
+		// Create new transaction
+		transaction = domain.NewTransaction(userID, transactionType, amount, fundName, s.clock.Now())
+
+		// Save transaction to repository, debits only if the balance covers
+		// them and deposits only if the allowance does
+		save := s.transactionRepo.Save
+		switch {
+		case transactionType.IsDebit():
+			save = s.transactionRepo.SaveDebit
+		case transactionType == domain.TransactionTypeDeposit:
+			save = s.saveDeposit
+		}
+		if err := save(ctx, transaction); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.auditLog, s.clock, domain.AuditActionCreate, nil, transaction); err != nil {
+			return err
+		}
 
-	// Save transaction to repository
-	if err := s.transactionRepo.Save(transaction); err != nil {
+		event, err := domain.NewTransactionCreatedEvent(transaction, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		return s.outbox.Add(ctx, event)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -47,75 +103,233 @@ func (s *TransactionService) CreateTransaction(userID string, amount decimal.Dec
 }
 
 // GetTransaction implements the transaction retrieval use case
-func (s *TransactionService) GetTransaction(id string) (*domain.Transaction, error) {
+func (s *TransactionService) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
 	if id == "" {
-		return nil, errors.New("transaction ID is required")
+		return nil, domain.NewValidationError("id", "transaction ID is required")
 	}
 
-	transaction, err := s.transactionRepo.FindByID(id)
-	if err != nil {
-		return nil, errors.New("transaction not found")
+	return s.findTransaction(ctx, id)
+}
+
+// GetUserTransactions implements the user transaction history use case
+func (s *TransactionService) GetUserTransactions(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error) {
+	if query.Sort == "" {
+		query.Sort = domain.SortNewestFirst
+	}
+	if query.Limit == 0 {
+		query.Limit = domain.DefaultPageSize
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewAccount, query.UserID); err != nil {
+		return nil, err
 	}
 
-	if transaction == nil {
-		return nil, errors.New("transaction not found")
+	// An unknown user is an error rather than a user with no transactions
+	if err := s.checkUserExists(ctx, query.UserID); err != nil {
+		return nil, err
 	}
 
-	return transaction, nil
+	return s.transactionRepo.FindPage(ctx, query)
 }
 
-// GetUserTransactions implements the user transactions retrieval use case
-func (s *TransactionService) GetUserTransactions(userID string) ([]*domain.Transaction, error) {
+// GetAllowance implements the ISA allowance retrieval use case
+func (s *TransactionService) GetAllowance(ctx context.Context, userID string) (*domain.Allowance, error) {
 	if userID == "" {
-		return nil, errors.New("user ID is required")
+		return nil, domain.NewValidationError("user_id", "user ID is required")
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionViewAccount, userID); err != nil {
+		return nil, err
+	}
+	if err := s.checkUserExists(ctx, userID); err != nil {
+		return nil, err
 	}
 
-	transactions, err := s.transactionRepo.FindByUserID(userID)
+	taxYear := domain.TaxYearFor(s.clock.Now())
+	used, err := s.transactionRepo.SumDeposits(ctx, userID, taxYear.Start(), taxYear.End())
 	if err != nil {
 		return nil, err
 	}
 
-	return transactions, nil
+	return domain.NewAllowance(userID, taxYear, s.allowancePolicy.LimitFor(taxYear), used), nil
 }
 
-// UpdateTransaction implements the transaction update use case
-func (s *TransactionService) UpdateTransaction(transaction *domain.Transaction) error {
-	if transaction == nil {
-		return errors.New("transaction cannot be nil")
+// CorrectTransaction implements the transaction correction use case. The
+// original entry is left untouched: it is reversed and a corrected entry of
+// the same type is recorded in its place, attributed to the principal making
+// the request.
+func (s *TransactionService) CorrectTransaction(ctx context.Context, id string, amount decimal.Decimal, fundName domain.FundName, reason string) (*domain.Reversal, error) {
+	if !amount.IsPositive() {
+		return nil, domain.NewValidationError("amount", "amount must be positive")
 	}
 
-	if transaction.ID == "" {
-		return errors.New("transaction ID is required")
+	var reversed *domain.Reversal
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		original, reversal, err := s.reverse(ctx, id, reason)
+		if err != nil {
+			return err
+		}
+
+		if err := s.validateFund(ctx, fundName, original.Type); err != nil {
+			return err
+		}
+
+		correction := domain.NewTransaction(original.UserID, original.Type, amount, fundName, s.clock.Now())
+		correction.Reason = reason
+		correction.Actor = reversal.Actor
+
+		// A corrected deposit counts in full towards the current tax year. It
+		// is saved after the reversal, which takes the original out of the
+		// total if that was made this tax year too, so then only an increase
+		// in the amount uses more of the allowance.
+		if original.Type == domain.TransactionTypeDeposit {
+			if err := s.transactionRepo.SaveReversal(ctx, reversal, nil); err != nil {
+				return err
+			}
+			if err := s.saveDeposit(ctx, correction); err != nil {
+				return err
+			}
+		} else if err := s.transactionRepo.SaveReversal(ctx, reversal, correction); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.auditLog, s.clock, domain.AuditActionCorrect, original, correction); err != nil {
+			return err
+		}
+
+		reversed = &domain.Reversal{Original: original, Reversal: reversal, Correction: correction}
+		return s.addReversedEvent(ctx, reversed)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if !transaction.FundName.IsValid() {
-		return errors.New("invalid fund name")
+	return reversed, nil
+}
+
+// ReverseTransaction implements the transaction reversal use case, the
+// reversal attributed to the principal making the request
+func (s *TransactionService) ReverseTransaction(ctx context.Context, id, reason string) (*domain.Reversal, error) {
+	var reversed *domain.Reversal
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		original, reversal, err := s.reverse(ctx, id, reason)
+		if err != nil {
+			return err
+		}
+
+		if err := s.transactionRepo.SaveReversal(ctx, reversal, nil); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, s.auditLog, s.clock, domain.AuditActionReverse, original, reversal); err != nil {
+			return err
+		}
+
+		reversed = &domain.Reversal{Original: original, Reversal: reversal}
+		return s.addReversedEvent(ctx, reversed)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Verify transaction exists
-	existingTransaction, err := s.transactionRepo.FindByID(transaction.ID)
+	return reversed, nil
+}
+
+// addReversedEvent adds the event for a reversal or correction to the outbox
+func (s *TransactionService) addReversedEvent(ctx context.Context, reversal *domain.Reversal) error {
+	event, err := domain.NewTransactionReversedEvent(reversal, s.clock.Now())
 	if err != nil {
 		return err
 	}
+	return s.outbox.Add(ctx, event)
+}
+
+// reverse looks up a transaction and creates the entry reversing it, which
+// the caller is responsible for saving
+func (s *TransactionService) reverse(ctx context.Context, id, reason string) (*domain.Transaction, *domain.Transaction, error) {
+	if id == "" {
+		return nil, nil, domain.NewValidationError("id", "transaction ID is required")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, nil, domain.NewValidationError("reason", "reason is required")
+	}
+
+	original, err := s.findTransaction(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, domain.PermissionCorrectTransaction, original.UserID); err != nil {
+		return nil, nil, err
+	}
+	principal, err := domain.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Update only allowed fields
-	existingTransaction.Amount = transaction.Amount
-	existingTransaction.FundName = transaction.FundName
+	existing, err := s.transactionRepo.FindReversal(ctx, original.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		return nil, nil, domain.ErrAlreadyReversed
+	}
 
-	return s.transactionRepo.Update(existingTransaction)
+	reversal, err := original.Reverse(reason, principal.Subject, s.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return original, reversal, nil
 }
 
-// DeleteTransaction implements the transaction deletion use case
-func (s *TransactionService) DeleteTransaction(id string) error {
-	if id == "" {
-		return errors.New("transaction ID is required")
+// findTransaction retrieves a transaction the principal may see. One they may
+// not is reported as not found, so transaction IDs cannot be probed for which
+// exist
+func (s *TransactionService) findTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
+	err = s.authorizer.Authorize(ctx, domain.PermissionViewAccount, transaction.UserID)
+	if errors.Is(err, domain.ErrForbidden) {
+		return nil, domain.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// checkUserExists returns domain.ErrUserNotFound if the direct user does not exist
+func (s *TransactionService) checkUserExists(ctx context.Context, userID string) error {
+	_, err := s.directUserRepo.FindByID(ctx, userID)
+	return err
+}
 
-	// Verify transaction exists
-	_, err := s.transactionRepo.FindByID(id)
+// validateFund checks the fund catalogue for the fund, which must be open to
+// investment unless the transaction takes money out of it
+func (s *TransactionService) validateFund(ctx context.Context, fundName domain.FundName, transactionType domain.TransactionType) error {
+	if !fundName.IsValid() {
+		return domain.NewValidationError("fund_name", "invalid fund name")
+	}
+
+	fund, err := s.fundRepo.FindByName(ctx, fundName)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewValidationError("fund_name", "invalid fund name")
+	}
 	if err != nil {
 		return err
 	}
 
-	return s.transactionRepo.Delete(id)
-} 
\ No newline at end of file
+	if !fund.IsOpen() && !transactionType.IsDebit() {
+		return domain.NewValidationError("fund_name", "fund is closed to new investment")
+	}
+
+	return nil
+}
+
+// saveDeposit saves a deposit only if it is within the user's ISA allowance for its tax year
+func (s *TransactionService) saveDeposit(ctx context.Context, deposit *domain.Transaction) error {
+	limit := s.allowancePolicy.LimitFor(domain.TaxYearFor(deposit.CreatedAt))
+	return s.transactionRepo.SaveDeposit(ctx, deposit, limit)
+}
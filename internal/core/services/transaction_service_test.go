@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"cushon/internal/core/domain"
 
@@ -12,6 +15,8 @@ import (
 // MockTransactionRepository implements output.TransactionRepository for testing
 type MockTransactionRepository struct {
 	transactions map[string]*domain.Transaction
+	// lastQuery is the last query FindPage received
+	lastQuery domain.TransactionQuery
 }
 
 func NewMockTransactionRepository() *MockTransactionRepository {
@@ -20,19 +25,88 @@ func NewMockTransactionRepository() *MockTransactionRepository {
 	}
 }
 
-func (m *MockTransactionRepository) Save(transaction *domain.Transaction) error {
+func (m *MockTransactionRepository) Save(ctx context.Context, transaction *domain.Transaction) error {
 	m.transactions[transaction.ID] = transaction
 	return nil
 }
 
-func (m *MockTransactionRepository) FindByID(id string) (*domain.Transaction, error) {
+func (m *MockTransactionRepository) SaveDebit(ctx context.Context, transaction *domain.Transaction) error {
+	if err := m.checkBalance(transaction, decimal.Zero); err != nil {
+		return err
+	}
+	return m.Save(ctx, transaction)
+}
+
+func (m *MockTransactionRepository) SaveDeposit(ctx context.Context, transaction *domain.Transaction, limit decimal.Decimal) error {
+	taxYear := domain.TaxYearFor(transaction.CreatedAt)
+	used, _ := m.SumDeposits(ctx, transaction.UserID, taxYear.Start(), taxYear.End())
+	if used.Add(transaction.Amount).GreaterThan(limit) {
+		return &domain.AllowanceExceededError{
+			TaxYear:   taxYear,
+			Limit:     limit,
+			Used:      used,
+			Requested: transaction.Amount,
+		}
+	}
+	return m.Save(ctx, transaction)
+}
+
+func (m *MockTransactionRepository) SaveReversal(ctx context.Context, reversal, correction *domain.Transaction) error {
+	if err := m.checkBalance(reversal, decimal.Zero); err != nil {
+		return err
+	}
+	if correction != nil {
+		// The correction is checked against the balance after the reversal
+		adjustment := decimal.Zero
+		if correction.FundName == reversal.FundName {
+			adjustment = reversal.SignedAmount()
+		}
+		if err := m.checkBalance(correction, adjustment); err != nil {
+			return err
+		}
+	}
+
+	m.Save(ctx, reversal)
+	if correction != nil {
+		m.Save(ctx, correction)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) checkBalance(transaction *domain.Transaction, adjustment decimal.Decimal) error {
+	if !transaction.IsDebit() {
+		return nil
+	}
+	balance, _ := m.Balance(context.Background(), transaction.UserID, transaction.FundName)
+	balance = balance.Add(adjustment)
+	if transaction.Amount.GreaterThan(balance) {
+		return &domain.InsufficientBalanceError{
+			FundName:  transaction.FundName,
+			Available: balance,
+			Requested: transaction.Amount,
+		}
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) Balance(ctx context.Context, userID string, fundName domain.FundName) (decimal.Decimal, error) {
+	balance := decimal.Zero
+	for _, transaction := range m.transactions {
+		if transaction.UserID == userID && transaction.FundName == fundName {
+			balance = balance.Add(transaction.SignedAmount())
+		}
+	}
+	return balance, nil
+}
+
+func (m *MockTransactionRepository) FindByID(ctx context.Context, id string) (*domain.Transaction, error) {
 	if transaction, exists := m.transactions[id]; exists {
 		return transaction, nil
 	}
-	return nil, errors.New("transaction not found")
+	return nil, domain.ErrTransactionNotFound
 }
 
-func (m *MockTransactionRepository) FindByUserID(userID string) ([]*domain.Transaction, error) {
+func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.Transaction, error) {
 	var userTransactions []*domain.Transaction
 	for _, transaction := range m.transactions {
 		if transaction.UserID == userID {
@@ -42,7 +116,40 @@ func (m *MockTransactionRepository) FindByUserID(userID string) ([]*domain.Trans
 	return userTransactions, nil
 }
 
-func (m *MockTransactionRepository) Update(transaction *domain.Transaction) error {
+func (m *MockTransactionRepository) FindPage(ctx context.Context, query domain.TransactionQuery) (*domain.TransactionPage, error) {
+	m.lastQuery = query
+	page := &domain.TransactionPage{Transactions: []*domain.Transaction{}}
+	for _, transaction := range m.transactions {
+		if query.Matches(transaction) {
+			page.Total++
+			if len(page.Transactions) < query.Limit {
+				page.Transactions = append(page.Transactions, transaction)
+			}
+		}
+	}
+	return page, nil
+}
+
+func (m *MockTransactionRepository) FindReversal(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	for _, transaction := range m.transactions {
+		if transaction.ReversalOf == transactionID {
+			return transaction, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) FindUnpriced(ctx context.Context, fundName domain.FundName, placedBefore time.Time) ([]*domain.Transaction, error) {
+	var pending []*domain.Transaction
+	for _, transaction := range m.transactions {
+		if transaction.FundName == fundName && !transaction.IsPriced() && transaction.CreatedAt.Before(placedBefore) {
+			pending = append(pending, transaction)
+		}
+	}
+	return pending, nil
+}
+
+func (m *MockTransactionRepository) AllocateUnits(ctx context.Context, transaction *domain.Transaction) error {
 	if _, exists := m.transactions[transaction.ID]; !exists {
 		return errors.New("transaction not found")
 	}
@@ -50,17 +157,22 @@ func (m *MockTransactionRepository) Update(transaction *domain.Transaction) erro
 	return nil
 }
 
-func (m *MockTransactionRepository) Delete(id string) error {
-	if _, exists := m.transactions[id]; !exists {
-		return errors.New("transaction not found")
+func (m *MockTransactionRepository) SumDeposits(ctx context.Context, userID string, from, to time.Time) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for id, transaction := range m.transactions {
+		if transaction.UserID == userID && transaction.Type == domain.TransactionTypeDeposit && !transaction.IsReversal() &&
+			!transaction.CreatedAt.Before(from) && transaction.CreatedAt.Before(to) {
+			if reversal, _ := m.FindReversal(ctx, id); reversal == nil {
+				total = total.Add(transaction.Amount)
+			}
+		}
 	}
-	delete(m.transactions, id)
-	return nil
+	return total, nil
 }
 
 func TestTransactionService_CreateTransaction(t *testing.T) {
 	repo := NewMockTransactionRepository()
-	service := NewTransactionService(repo)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	tests := []struct {
 		name          string
@@ -90,6 +202,13 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 			fundName:      "Cushon Equities Fund",
 			expectedError: true,
 		},
+		{
+			name:          "negative amount",
+			userID:        "user123",
+			amount:        decimal.NewFromFloat(-1000.50),
+			fundName:      "Cushon Equities Fund",
+			expectedError: true,
+		},
 		{
 			name:          "invalid fund name",
 			userID:        "user123",
@@ -101,7 +220,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			transaction, err := service.CreateTransaction(tt.userID, tt.amount, tt.fundName)
+			transaction, err := service.CreateTransaction(staffContext(), tt.userID, domain.TransactionTypeDeposit, tt.amount, tt.fundName)
 
 			if tt.expectedError {
 				if err == nil {
@@ -128,7 +247,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 			}
 
 			// Verify transaction was saved in repository
-			savedTransaction, err := repo.FindByID(transaction.ID)
+			savedTransaction, err := repo.FindByID(context.Background(), transaction.ID)
 			if err != nil {
 				t.Errorf("Failed to find saved transaction: %v", err)
 			}
@@ -139,13 +258,146 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionService_CreateTransaction_UnknownUser(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	_, err := service.CreateTransaction(staffContext(), "unknown-user", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("Expected ErrUserNotFound, got %v", err)
+	}
+	if len(repo.transactions) != 0 {
+		t.Errorf("Expected nothing to be saved, got %d transactions", len(repo.transactions))
+	}
+}
+
+func TestTransactionService_CreateTransaction_ClosedFund(t *testing.T) {
+	fundRepo := NewSeededMockFundRepository()
+	fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+	fund.Status = domain.FundStatusClosed
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123"), fundRepo, NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	_, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromFloat(1000.50), domain.CushonEquitiesFund)
+	if err == nil {
+		t.Error("Expected error investing in a closed fund, got nil")
+	}
+}
+
+func TestTransactionService_CreateTransaction_Withdrawal(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	fundRepo := NewSeededMockFundRepository()
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), fundRepo, NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund)
+
+	withdrawal, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(600), domain.CushonEquitiesFund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !withdrawal.SignedAmount().Equal(decimal.NewFromInt(-600)) {
+		t.Errorf("Expected signed amount -600, got %s", withdrawal.SignedAmount())
+	}
+
+	// Only 400 remains, so a further 500 withdrawal is rejected
+	_, err = service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(500), domain.CushonEquitiesFund)
+	var balanceErr *domain.InsufficientBalanceError
+	if !errors.As(err, &balanceErr) {
+		t.Fatalf("Expected InsufficientBalanceError, got %v", err)
+	}
+	if !balanceErr.Available.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("Expected 400 available, got %s", balanceErr.Available)
+	}
+
+	// Fees are debits too
+	if _, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeFee, decimal.NewFromInt(401), domain.CushonEquitiesFund); err == nil {
+		t.Error("Expected fee exceeding balance to be rejected, got nil")
+	}
+
+	// Withdrawals remain possible once a fund closes to new investment
+	fund, _ := fundRepo.FindByName(context.Background(), domain.CushonEquitiesFund)
+	fund.Status = domain.FundStatusClosed
+	if _, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(400), domain.CushonEquitiesFund); err != nil {
+		t.Errorf("Unexpected error withdrawing from a closed fund: %v", err)
+	}
+
+	if _, err := service.CreateTransaction(staffContext(), "user123", "refund", decimal.NewFromInt(1), domain.CushonEquitiesFund); err == nil {
+		t.Error("Expected error for invalid transaction type, got nil")
+	}
+}
+
+func TestTransactionService_CreateTransaction_TransferInExcludedFromAllowance(t *testing.T) {
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	if _, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeTransferIn, decimal.NewFromInt(50000), domain.CushonEquitiesFund); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	allowance, _ := service.GetAllowance(staffContext(), "user123")
+	if !allowance.Used.IsZero() {
+		t.Errorf("Expected transfers in not to use the allowance, got %s used", allowance.Used)
+	}
+}
+
+func TestTransactionService_CreateTransaction_AllowanceExceeded(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123", "user456"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	if _, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(15000), domain.CushonEquitiesFund); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.RequireFromString("5000.01"), domain.CushonEquitiesFund)
+	var allowanceErr *domain.AllowanceExceededError
+	if !errors.As(err, &allowanceErr) {
+		t.Fatalf("Expected AllowanceExceededError, got %v", err)
+	}
+	if !allowanceErr.Used.Equal(decimal.NewFromInt(15000)) {
+		t.Errorf("Expected used allowance 15000, got %s", allowanceErr.Used)
+	}
+
+	// Topping up to exactly the limit is allowed
+	if _, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(5000), domain.CushonEquitiesFund); err != nil {
+		t.Errorf("Unexpected error depositing up to the limit: %v", err)
+	}
+
+	// Another user's allowance is unaffected
+	if _, err := service.CreateTransaction(staffContext(), "user456", domain.TransactionTypeDeposit, decimal.NewFromInt(20000), domain.CushonEquitiesFund); err != nil {
+		t.Errorf("Unexpected error for another user: %v", err)
+	}
+}
+
+func TestTransactionService_GetAllowance(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	policy := domain.DefaultAllowancePolicy()
+	policy.Limits[domain.TaxYearFor(testNow)] = decimal.NewFromInt(10000)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), policy, NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(2500), domain.CushonEquitiesFund)
+
+	allowance, err := service.GetAllowance(staffContext(), "user123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !allowance.Limit.Equal(decimal.NewFromInt(10000)) {
+		t.Errorf("Expected limit 10000, got %s", allowance.Limit)
+	}
+	if !allowance.Remaining.Equal(decimal.NewFromInt(7500)) {
+		t.Errorf("Expected remaining 7500, got %s", allowance.Remaining)
+	}
+
+	if _, err := service.GetAllowance(staffContext(), ""); err == nil {
+		t.Error("Expected error for empty user ID, got nil")
+	}
+}
+
 func TestTransactionService_GetTransaction(t *testing.T) {
 	repo := NewMockTransactionRepository()
-	service := NewTransactionService(repo)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create a test transaction
-	testTransaction, _ := service.CreateTransaction(
+	testTransaction, _ := service.CreateTransaction(staffContext(), 
 		"user123",
+		domain.TransactionTypeDeposit,
 		decimal.NewFromFloat(1000.50),
 		"Cushon Equities Fund",
 	)
@@ -174,7 +426,7 @@ func TestTransactionService_GetTransaction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			transaction, err := service.GetTransaction(tt.transactionID)
+			transaction, err := service.GetTransaction(staffContext(), tt.transactionID)
 
 			if tt.expectedError {
 				if err == nil {
@@ -197,12 +449,12 @@ func TestTransactionService_GetTransaction(t *testing.T) {
 
 func TestTransactionService_GetUserTransactions(t *testing.T) {
 	repo := NewMockTransactionRepository()
-	service := NewTransactionService(repo)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123", "no-transactions"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create test transactions for a user
 	userID := "user123"
-	service.CreateTransaction(userID, decimal.NewFromFloat(1000.50), "Cushon Equities Fund")
-	service.CreateTransaction(userID, decimal.NewFromFloat(2000.75), "Cushon Equities Fund")
+	service.CreateTransaction(staffContext(), userID, domain.TransactionTypeDeposit, decimal.NewFromFloat(1000.50), "Cushon Equities Fund")
+	service.CreateTransaction(staffContext(), userID, domain.TransactionTypeDeposit, decimal.NewFromFloat(2000.75), "Cushon Equities Fund")
 
 	tests := []struct {
 		name          string
@@ -222,6 +474,12 @@ func TestTransactionService_GetUserTransactions(t *testing.T) {
 			expectedCount: 0,
 			expectedError: false,
 		},
+		{
+			name:          "unknown user",
+			userID:        "unknown-user",
+			expectedCount: 0,
+			expectedError: true,
+		},
 		{
 			name:          "empty user ID",
 			userID:        "",
@@ -232,7 +490,7 @@ func TestTransactionService_GetUserTransactions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			transactions, err := service.GetUserTransactions(tt.userID)
+			page, err := service.GetUserTransactions(staffContext(), domain.TransactionQuery{UserID: tt.userID})
 
 			if tt.expectedError {
 				if err == nil {
@@ -246,11 +504,11 @@ func TestTransactionService_GetUserTransactions(t *testing.T) {
 				return
 			}
 
-			if len(transactions) != tt.expectedCount {
-				t.Errorf("Expected %d transactions, got %d", tt.expectedCount, len(transactions))
+			if len(page.Transactions) != tt.expectedCount || page.Total != tt.expectedCount {
+				t.Errorf("Expected %d transactions, got %d of %d", tt.expectedCount, len(page.Transactions), page.Total)
 			}
 
-			for _, transaction := range transactions {
+			for _, transaction := range page.Transactions {
 				if transaction.UserID != tt.userID {
 					t.Errorf("Expected UserID %s, got %s", tt.userID, transaction.UserID)
 				}
@@ -259,62 +517,123 @@ func TestTransactionService_GetUserTransactions(t *testing.T) {
 	}
 }
 
-func TestTransactionService_UpdateTransaction(t *testing.T) {
+func TestTransactionService_GetUserTransactions_Query(t *testing.T) {
 	repo := NewMockTransactionRepository()
-	service := NewTransactionService(repo)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	// An unset sort and limit take the defaults
+	if _, err := service.GetUserTransactions(staffContext(), domain.TransactionQuery{UserID: "user123"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if repo.lastQuery.Sort != domain.SortNewestFirst || repo.lastQuery.Limit != domain.DefaultPageSize {
+		t.Errorf("Expected newest first in pages of %d, got %q in pages of %d", domain.DefaultPageSize, repo.lastQuery.Sort, repo.lastQuery.Limit)
+	}
+
+	tests := []struct {
+		name          string
+		query         domain.TransactionQuery
+		expectedField string
+	}{
+		{name: "limit too large", query: domain.TransactionQuery{Limit: domain.MaxPageSize + 1}, expectedField: "limit"},
+		{name: "negative limit", query: domain.TransactionQuery{Limit: -1}, expectedField: "limit"},
+		{name: "unknown sort", query: domain.TransactionQuery{Sort: "amount"}, expectedField: "sort"},
+		{name: "unknown type", query: domain.TransactionQuery{Type: "refund"}, expectedField: "type"},
+		{name: "empty interval", query: domain.TransactionQuery{From: testNow, To: testNow}, expectedField: "to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.UserID = "user123"
+			_, err := service.GetUserTransactions(staffContext(), tt.query)
+
+			var validationErr *domain.ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.expectedField {
+				t.Errorf("Expected validation error for %s, got %v", tt.expectedField, err)
+			}
+		})
+	}
+}
+
+func TestTransactionService_CorrectTransaction(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create a test transaction
-	testTransaction, _ := service.CreateTransaction(
+	testTransaction, _ := service.CreateTransaction(staffContext(), 
 		"user123",
+		domain.TransactionTypeDeposit,
 		decimal.NewFromFloat(1000.50),
 		"Cushon Equities Fund",
 	)
+	reversedTransaction, _ := service.CreateTransaction(staffContext(), 
+		"user123",
+		domain.TransactionTypeDeposit,
+		decimal.NewFromInt(100),
+		"Cushon Equities Fund",
+	)
+	service.ReverseTransaction(staffContext(), reversedTransaction.ID, "entered in error")
 
 	tests := []struct {
 		name          string
-		transaction   *domain.Transaction
+		transactionID string
+		amount        decimal.Decimal
+		fundName      domain.FundName
+		reason        string
 		expectedError bool
 	}{
 		{
-			name: "valid update",
-			transaction: &domain.Transaction{
-				ID:       testTransaction.ID,
-				UserID:   testTransaction.UserID,
-				Amount:   decimal.NewFromFloat(2000.75),
-				FundName: "Cushon Equities Fund",
-			},
-			expectedError: false,
+			name:          "non-existent transaction",
+			transactionID: "non-existent",
+			amount:        decimal.NewFromFloat(2000.75),
+			fundName:      "Cushon Equities Fund",
+			reason:        "wrong amount",
+			expectedError: true,
 		},
 		{
-			name: "non-existent transaction",
-			transaction: &domain.Transaction{
-				ID:       "non-existent",
-				UserID:   "user123",
-				Amount:   decimal.NewFromFloat(2000.75),
-				FundName: "Cushon Equities Fund",
-			},
+			name:          "invalid fund name",
+			transactionID: testTransaction.ID,
+			amount:        decimal.NewFromFloat(2000.75),
+			fundName:      "Invalid Fund",
+			reason:        "wrong amount",
 			expectedError: true,
 		},
 		{
-			name: "invalid fund name",
-			transaction: &domain.Transaction{
-				ID:       testTransaction.ID,
-				UserID:   testTransaction.UserID,
-				Amount:   decimal.NewFromFloat(2000.75),
-				FundName: "Invalid Fund",
-			},
+			name:          "negative amount",
+			transactionID: testTransaction.ID,
+			amount:        decimal.NewFromInt(-1),
+			fundName:      "Cushon Equities Fund",
+			reason:        "wrong amount",
 			expectedError: true,
 		},
 		{
-			name:          "nil transaction",
-			transaction:   nil,
+			name:          "missing reason",
+			transactionID: testTransaction.ID,
+			amount:        decimal.NewFromFloat(2000.75),
+			fundName:      "Cushon Equities Fund",
+			reason:        " ",
 			expectedError: true,
 		},
+		{
+			name:          "already reversed",
+			transactionID: reversedTransaction.ID,
+			amount:        decimal.NewFromInt(50),
+			fundName:      "Cushon Equities Fund",
+			reason:        "wrong amount",
+			expectedError: true,
+		},
+		{
+			name:          "valid correction",
+			transactionID: testTransaction.ID,
+			amount:        decimal.NewFromFloat(2000.75),
+			fundName:      "Cushon Equities Fund",
+			reason:        "wrong amount",
+			expectedError: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := service.UpdateTransaction(tt.transaction)
+			result, err := service.CorrectTransaction(staffContext(), tt.transactionID, tt.amount, tt.fundName, tt.reason)
 
 			if tt.expectedError {
 				if err == nil {
@@ -328,54 +647,150 @@ func TestTransactionService_UpdateTransaction(t *testing.T) {
 				return
 			}
 
-			// Verify update in repository
-			updatedTransaction, err := repo.FindByID(tt.transaction.ID)
-			if err != nil {
-				t.Errorf("Failed to find updated transaction: %v", err)
+			// The original is left as it was
+			original, _ := repo.FindByID(context.Background(), tt.transactionID)
+			if !original.Amount.Equal(decimal.NewFromFloat(1000.50)) {
+				t.Errorf("Expected original amount to be unchanged, got %s", original.Amount)
+			}
+			if result.Reversal.ReversalOf != tt.transactionID || result.Correction == nil {
+				t.Fatalf("Expected a reversal of %s and a correction, got %+v", tt.transactionID, result)
+			}
+			if !result.Correction.Amount.Equal(tt.amount) || result.Correction.Reason != tt.reason {
+				t.Errorf("Expected correction of %s for %q, got %s for %q", tt.amount, tt.reason, result.Correction.Amount, result.Correction.Reason)
 			}
-			if !updatedTransaction.Amount.Equal(tt.transaction.Amount) {
-				t.Errorf("Expected updated amount %s, got %s", tt.transaction.Amount.String(), updatedTransaction.Amount.String())
+			if result.Correction.Actor != testStaff.Subject {
+				t.Errorf("Expected the correction attributed to %s, got %q", testStaff.Subject, result.Correction.Actor)
+			}
+
+			balance, _ := repo.Balance(context.Background(), "user123", "Cushon Equities Fund")
+			if !balance.Equal(tt.amount) {
+				t.Errorf("Expected balance %s, got %s", tt.amount, balance)
 			}
 		})
 	}
 }
 
-func TestTransactionService_DeleteTransaction(t *testing.T) {
+func TestTransactionService_CorrectTransaction_AcrossTaxYears(t *testing.T) {
+	clock := NewMockClock(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123", "user456"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), clock)
+
+	// Each user deposited the whole allowance last tax year and 1000 this year
+	lastYear := make(map[string]*domain.Transaction)
+	for _, userID := range []string{"user123", "user456"} {
+		deposit, err := service.CreateTransaction(staffContext(), userID, domain.TransactionTypeDeposit, decimal.NewFromInt(20000), domain.CushonEquitiesFund)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		lastYear[userID] = deposit
+	}
+	clock.Advance(testNow.Sub(clock.Now()))
+	for _, userID := range []string{"user123", "user456"} {
+		if _, err := service.CreateTransaction(staffContext(), userID, domain.TransactionTypeDeposit, decimal.NewFromInt(1000), domain.CushonEquitiesFund); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// The corrected entry is dated this tax year, so its whole amount counts
+	// towards this year's allowance, not just the change
+	_, err := service.CorrectTransaction(staffContext(), lastYear["user123"].ID, decimal.NewFromInt(20000), domain.CushonEquitiesFund, "wrong amount")
+	var allowanceErr *domain.AllowanceExceededError
+	if !errors.As(err, &allowanceErr) {
+		t.Fatalf("Expected AllowanceExceededError, got %v", err)
+	}
+	if allowanceErr.TaxYear != domain.TaxYearFor(testNow) || !allowanceErr.Requested.Equal(decimal.NewFromInt(20000)) {
+		t.Errorf("Expected 20000 requested from %s, got %s from %s", domain.TaxYearFor(testNow), allowanceErr.Requested, allowanceErr.TaxYear)
+	}
+
+	if _, err := service.CorrectTransaction(staffContext(), lastYear["user456"].ID, decimal.NewFromInt(19000), domain.CushonEquitiesFund, "wrong amount"); err != nil {
+		t.Errorf("Unexpected error correcting within the allowance: %v", err)
+	}
+}
+
+func TestTransactionService_Timestamps(t *testing.T) {
+	clock := NewMockClock(testNow)
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), clock)
+
+	transaction, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !transaction.CreatedAt.Equal(testNow) || !transaction.UpdatedAt.Equal(testNow) {
+		t.Errorf("Expected transaction placed at %s, got created %s and updated %s", testNow, transaction.CreatedAt, transaction.UpdatedAt)
+	}
+
+	// A reversal is placed when it is made, not when the original was
+	clock.Advance(24 * time.Hour)
+	reversal, err := service.ReverseTransaction(staffContext(), transaction.ID, "Duplicate deposit")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reversal.Reversal.CreatedAt.Equal(testNow.Add(24 * time.Hour)) {
+		t.Errorf("Expected reversal placed at %s, got %s", testNow.Add(24*time.Hour), reversal.Reversal.CreatedAt)
+	}
+	if !reversal.Original.CreatedAt.Equal(testNow) {
+		t.Errorf("Expected original to keep its time, got %s", reversal.Original.CreatedAt)
+	}
+}
+
+func TestTransactionService_ReverseTransaction(t *testing.T) {
 	repo := NewMockTransactionRepository()
-	service := NewTransactionService(repo)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
 
 	// Create a test transaction
-	testTransaction, _ := service.CreateTransaction(
+	testTransaction, _ := service.CreateTransaction(staffContext(), 
 		"user123",
+		domain.TransactionTypeDeposit,
 		decimal.NewFromFloat(1000.50),
 		"Cushon Equities Fund",
 	)
 
 	tests := []struct {
 		name          string
+		ctx           context.Context
 		transactionID string
 		expectedError bool
 	}{
 		{
-			name:          "existing transaction",
-			transactionID: testTransaction.ID,
-			expectedError: false,
+			name:          "empty ID",
+			ctx:           staffContext(),
+			transactionID: "",
+			expectedError: true,
 		},
 		{
 			name:          "non-existent transaction",
+			ctx:           staffContext(),
 			transactionID: "non-existent",
 			expectedError: true,
 		},
 		{
-			name:          "empty ID",
-			transactionID: "",
+			name:          "no principal",
+			ctx:           context.Background(),
+			transactionID: testTransaction.ID,
+			expectedError: true,
+		},
+		{
+			name:          "another customer",
+			ctx:           customerContext("user456"),
+			transactionID: testTransaction.ID,
+			expectedError: true,
+		},
+		{
+			name:          "existing transaction",
+			ctx:           staffContext(),
+			transactionID: testTransaction.ID,
+			expectedError: false,
+		},
+		{
+			name:          "already reversed",
+			ctx:           staffContext(),
+			transactionID: testTransaction.ID,
 			expectedError: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := service.DeleteTransaction(tt.transactionID)
+			result, err := service.ReverseTransaction(tt.ctx, tt.transactionID, "entered in error")
 
 			if tt.expectedError {
 				if err == nil {
@@ -389,11 +804,252 @@ func TestTransactionService_DeleteTransaction(t *testing.T) {
 				return
 			}
 
-			// Verify deletion from repository
-			_, err = repo.FindByID(tt.transactionID)
-			if err == nil {
-				t.Error("Expected transaction to be deleted, but found in repository")
+			if result.Original.ID != tt.transactionID || result.Reversal.ReversalOf != tt.transactionID || result.Correction != nil {
+				t.Errorf("Expected a reversal of %s without a correction, got %+v", tt.transactionID, result)
+			}
+			if result.Reversal.Actor != testStaff.Subject {
+				t.Errorf("Expected the reversal attributed to %s, got %q", testStaff.Subject, result.Reversal.Actor)
+			}
+
+			// Both entries remain in the ledger and cancel out
+			if _, err := repo.FindByID(context.Background(), tt.transactionID); err != nil {
+				t.Errorf("Expected original to remain in the ledger: %v", err)
+			}
+			balance, _ := repo.Balance(context.Background(), "user123", "Cushon Equities Fund")
+			if !balance.IsZero() {
+				t.Errorf("Expected balance 0 after reversal, got %s", balance)
+			}
+
+			// A reversed deposit no longer counts towards the allowance
+			allowance, _ := service.GetAllowance(staffContext(), "user123")
+			if !allowance.Used.IsZero() {
+				t.Errorf("Expected no allowance used, got %s", allowance.Used)
+			}
+		})
+	}
+}
+
+func TestTransactionService_ReverseTransaction_InsufficientBalance(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	deposit, _ := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeWithdrawal, decimal.NewFromInt(60), domain.CushonEquitiesFund)
+
+	// Reversing the deposit would leave the balance negative
+	_, err := service.ReverseTransaction(staffContext(), deposit.ID, "payment returned")
+
+	var balanceErr *domain.InsufficientBalanceError
+	if !errors.As(err, &balanceErr) {
+		t.Fatalf("Expected InsufficientBalanceError, got %v", err)
+	}
+	if !balanceErr.Available.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("Expected 40 available, got %s", balanceErr.Available)
+	}
+}
+
+func TestTransactionService_Authorization(t *testing.T) {
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123", "user456"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), NewMockAuthorizer(), NewMockClock(testNow))
+
+	others, err := service.CreateTransaction(staffContext(), "user456", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		call    func(ctx context.Context) error
+		wantErr error
+	}{
+		{
+			name: "customer depositing into their own account",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.CreateTransaction(ctx, "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+				return err
+			},
+		},
+		{
+			name: "customer depositing into another account",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.CreateTransaction(ctx, "user456", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+				return err
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "customer reading another customer's transaction",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetTransaction(ctx, others.ID)
+				return err
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name: "customer reading another customer's history",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetUserTransactions(ctx, domain.TransactionQuery{UserID: "user456"})
+				return err
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "customer reading another customer's allowance",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetAllowance(ctx, "user456")
+				return err
+			},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "customer correcting another customer's transaction",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.CorrectTransaction(ctx, others.ID, decimal.NewFromInt(50), domain.CushonEquitiesFund, "wrong amount")
+				return err
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name: "customer reading a transaction that does not exist",
+			ctx:  customerContext("user123"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetTransaction(ctx, "missing")
+				return err
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name: "customer reading their own allowance",
+			ctx:  customerContext("user456"),
+			call: func(ctx context.Context) error {
+				_, err := service.GetAllowance(ctx, "user456")
+				return err
+			},
+		},
+		{
+			name: "no principal",
+			ctx:  context.Background(),
+			call: func(ctx context.Context) error {
+				_, err := service.GetTransaction(ctx, others.ID)
+				return err
+			},
+			wantErr: domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(tt.ctx)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestTransactionService_CorrectionsNeedPermission(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	authorizer := NewMockAuthorizer(domain.PermissionCorrectTransaction)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), authorizer, NewMockClock(testNow))
+
+	transaction, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := service.CorrectTransaction(staffContext(), transaction.ID, decimal.NewFromInt(50), domain.CushonEquitiesFund, "wrong amount"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden correcting without the permission, got %v", err)
+	}
+	if _, err := service.ReverseTransaction(staffContext(), transaction.ID, "entered in error"); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden reversing without the permission, got %v", err)
+	}
+
+	// Nothing was recorded against the original
+	if reversal, _ := repo.FindReversal(context.Background(), transaction.ID); reversal != nil {
+		t.Errorf("Expected no reversal, got %+v", reversal)
+	}
+
+	want := []domain.Permission{
+		domain.PermissionCreateTransaction,
+		domain.PermissionViewAccount,
+		domain.PermissionCorrectTransaction,
+		domain.PermissionViewAccount,
+		domain.PermissionCorrectTransaction,
+	}
+	if !slices.Equal(authorizer.Requested, want) {
+		t.Errorf("Expected permissions %v to be checked, got %v", want, authorizer.Requested)
+	}
+}
+
+func TestTransactionService_BookingNeedsPermission(t *testing.T) {
+	repo := NewMockTransactionRepository()
+	// Customers hold the permission to deposit and withdraw, but not to book other entries
+	authorizer := NewMockAuthorizer(domain.PermissionBookTransaction)
+	service := NewTransactionService(repo, NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), NewMockOutbox(), authorizer, NewMockClock(testNow))
+
+	if _, err := service.CreateTransaction(customerContext("user123"), "user123", domain.TransactionTypeInterest, decimal.NewFromInt(50000), domain.CushonEquitiesFund); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden booking interest as a customer, got %v", err)
+	}
+	if _, err := service.CreateTransaction(customerContext("user123"), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund); err != nil {
+		t.Errorf("Unexpected error depositing as a customer: %v", err)
+	}
+
+	if transactions, _ := repo.FindByUserID(context.Background(), "user123"); len(transactions) != 1 || transactions[0].Type != domain.TransactionTypeDeposit {
+		t.Errorf("Expected only the deposit to be saved, got %v", transactions)
+	}
+	want := []domain.Permission{domain.PermissionBookTransaction, domain.PermissionCreateTransaction}
+	if !slices.Equal(authorizer.Requested, want) {
+		t.Errorf("Expected permissions %v to be checked, got %v", want, authorizer.Requested)
+	}
+}
+
+func TestTransactionService_AddsEvents(t *testing.T) {
+	outbox := NewMockOutbox()
+	service := NewTransactionService(NewMockTransactionRepository(), NewSeededMockDirectUserRepository("user123"), NewSeededMockFundRepository(), NewMockUnitOfWork(), domain.DefaultAllowancePolicy(), NewMockAuditLog(), outbox, NewMockAuthorizer(), NewMockClock(testNow))
+
+	deposit, err := service.CreateTransaction(staffContext(), "user123", domain.TransactionTypeDeposit, decimal.NewFromInt(100), domain.CushonEquitiesFund)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	corrected, err := service.CorrectTransaction(staffContext(), deposit.ID, decimal.NewFromInt(150), domain.CushonEquitiesFund, "wrong amount")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := service.ReverseTransaction(staffContext(), corrected.Correction.ID, "duplicate"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct {
+		eventType   domain.EventType
+		aggregateID string
+	}{
+		{domain.EventTransactionCreated, deposit.ID},
+		{domain.EventTransactionReversed, deposit.ID},
+		{domain.EventTransactionReversed, corrected.Correction.ID},
+	}
+	if len(outbox.Events) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(outbox.Events))
+	}
+	for i, event := range outbox.Events {
+		if event.Type != want[i].eventType || event.AggregateID != want[i].aggregateID {
+			t.Errorf("Event %d: expected %s for %s, got %s for %s", i, want[i].eventType, want[i].aggregateID, event.Type, event.AggregateID)
+		}
+	}
+
+	// A correction carries the corrected entry, a plain reversal does not
+	if correction, ok := snapshotField(t, outbox.Events[1].Payload, "correction").(map[string]any); !ok || correction["amount"] != "150" {
+		t.Errorf("Expected the corrected entry in the payload, got %s", outbox.Events[1].Payload)
+	}
+	if correction := snapshotField(t, outbox.Events[2].Payload, "correction"); correction != nil {
+		t.Errorf("Expected no corrected entry for a reversal, got %v", correction)
+	}
+}
@@ -0,0 +1,170 @@
+// Package health checks whether the API's dependencies are usable, so an
+// orchestrator or load balancer only sends traffic to instances that can
+// serve it.
+//
+// Checks are registered by name with a Registry. A failing critical check
+// makes the instance not ready; a failing optional check only marks it
+// degraded, for dependencies the API can limp along without.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check reports whether a dependency is usable, returning an error saying
+// why not if it isn't. It should give up when ctx is done.
+type Check func(ctx context.Context) error
+
+// Status is the health of a single check or of the instance as a whole
+type Status string
+
+const (
+	// StatusUp means every check passed
+	StatusUp Status = "up"
+	// StatusDegraded means only optional checks failed, the instance can still serve
+	StatusDegraded Status = "degraded"
+	// StatusDown means a critical check failed or the instance is shutting down
+	StatusDown Status = "down"
+)
+
+// Result is the outcome of running one check
+type Result struct {
+	Name     string
+	Status   Status
+	Critical bool
+	Latency  time.Duration
+	// Err is why the check failed, nil if it passed
+	Err error
+}
+
+// Report is the outcome of running every registered check
+type Report struct {
+	Status   Status
+	Draining bool
+	Results  []Result
+}
+
+// Ready reports whether the instance should receive traffic
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// registeredCheck is a check and how its failure affects readiness
+type registeredCheck struct {
+	name     string
+	check    Check
+	critical bool
+}
+
+// Registry holds the checks that decide whether the instance is ready
+type Registry struct {
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checks   []registeredCheck
+	draining atomic.Bool
+}
+
+// NewRegistry creates an empty registry whose checks are each given timeout to finish
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a check the instance cannot serve without
+func (r *Registry) Register(name string, check Check) {
+	r.add(registeredCheck{name: name, check: check, critical: true})
+}
+
+// RegisterOptional adds a check whose failure degrades the instance without
+// taking it out of service
+func (r *Registry) RegisterOptional(name string, check Check) {
+	r.add(registeredCheck{name: name, check: check})
+}
+
+func (r *Registry) add(check registeredCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+}
+
+// Drain marks the instance as shutting down, so it reports not ready while
+// in-flight requests finish. It cannot be undone.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Check runs every registered check concurrently and reports the results in
+// registration order. A draining instance is reported down without running
+// any checks.
+func (r *Registry) Check(ctx context.Context) Report {
+	if r.draining.Load() {
+		return Report{Status: StatusDown, Draining: true, Results: []Result{}}
+	}
+
+	r.mu.RLock()
+	checks := r.checks
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Results: results}
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+		case result.Critical:
+			report.Status = StatusDown
+		case report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run runs one check, giving up at the registry's timeout even if the check
+// ignores its context
+func (r *Registry) run(ctx context.Context, check registeredCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- fmt.Errorf("check panicked: %v", recovered)
+			}
+		}()
+		done <- check.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not finish: %w", ctx.Err())
+	}
+
+	result := Result{
+		Name:     check.name,
+		Status:   StatusUp,
+		Critical: check.critical,
+		Latency:  time.Since(start),
+		Err:      err,
+	}
+	if err != nil {
+		result.Status = StatusDown
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func pass(ctx context.Context) error { return nil }
+
+func fail(ctx context.Context) error { return errors.New("connection refused") }
+
+func TestRegistry_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *Registry)
+		want     Status
+	}{
+		{
+			name:     "no checks",
+			register: func(r *Registry) {},
+			want:     StatusUp,
+		},
+		{
+			name: "all passing",
+			register: func(r *Registry) {
+				r.Register("database", pass)
+				r.RegisterOptional("pricing", pass)
+			},
+			want: StatusUp,
+		},
+		{
+			name: "optional check failing",
+			register: func(r *Registry) {
+				r.Register("database", pass)
+				r.RegisterOptional("pricing", fail)
+			},
+			want: StatusDegraded,
+		},
+		{
+			name: "critical check failing",
+			register: func(r *Registry) {
+				r.Register("database", fail)
+				r.RegisterOptional("pricing", fail)
+			},
+			want: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(time.Second)
+			tt.register(registry)
+
+			report := registry.Check(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("Check() status = %s, want %s", report.Status, tt.want)
+			}
+			if report.Ready() != (tt.want != StatusDown) {
+				t.Errorf("Ready() = %v for status %s", report.Ready(), report.Status)
+			}
+		})
+	}
+}
+
+func TestRegistry_Check_Results(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("database", pass)
+	registry.RegisterOptional("pricing", fail)
+
+	report := registry.Check(context.Background())
+
+	if len(report.Results) != 2 {
+		t.Fatalf("Check() returned %d results, want 2", len(report.Results))
+	}
+	database, pricing := report.Results[0], report.Results[1]
+	if database.Name != "database" || database.Status != StatusUp || !database.Critical || database.Err != nil {
+		t.Errorf("database result = %+v, want a passing critical check", database)
+	}
+	if pricing.Name != "pricing" || pricing.Status != StatusDown || pricing.Critical || pricing.Err == nil {
+		t.Errorf("pricing result = %+v, want a failing optional check", pricing)
+	}
+}
+
+func TestRegistry_Check_Timeout(t *testing.T) {
+	registry := NewRegistry(50 * time.Millisecond)
+	// A check that ignores its context must not hold up the probe
+	registry.Register("stuck", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	report := registry.Check(context.Background())
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Check() took %s, want it to give up at the timeout", elapsed)
+	}
+	if report.Status != StatusDown || !errors.Is(report.Results[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Check() = %+v, want the stuck check to fail with a deadline", report)
+	}
+}
+
+func TestRegistry_Check_Panic(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("broken", func(ctx context.Context) error {
+		panic("nil pointer")
+	})
+
+	if report := registry.Check(context.Background()); report.Status != StatusDown {
+		t.Errorf("Check() status = %s, want a panicking check to fail", report.Status)
+	}
+}
+
+func TestRegistry_Drain(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error {
+		t.Error("Checks should not run while draining")
+		return nil
+	})
+
+	registry.Drain()
+	report := registry.Check(context.Background())
+
+	if report.Status != StatusDown || !report.Draining || report.Ready() {
+		t.Errorf("Check() = %+v, want a draining instance to be not ready", report)
+	}
+}